@@ -0,0 +1,319 @@
+package vadu
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TipoEventoAnalise classifica os eventos emitidos por WatchAnalise.
+type TipoEventoAnalise int
+
+const (
+	// EventoStatus indica uma mudança no progresso da análise.
+	EventoStatus TipoEventoAnalise = iota
+	// EventoFase indica a mudança de fase da análise (ex.: início da finalização do arquivo).
+	EventoFase
+	// EventoConcluida indica que a análise foi concluída. É o último evento da análise.
+	EventoConcluida
+	// EventoErro indica uma falha ao consultar o status. É o último evento da análise apenas
+	// quando as falhas consecutivas excedem o limite configurado.
+	EventoErro
+)
+
+// String retorna o nome do tipo de evento.
+func (t TipoEventoAnalise) String() string {
+	switch t {
+	case EventoStatus:
+		return "status"
+	case EventoFase:
+		return "fase"
+	case EventoConcluida:
+		return "concluida"
+	case EventoErro:
+		return "erro"
+	default:
+		return fmt.Sprintf("TipoEventoAnalise(%d)", int(t))
+	}
+}
+
+// FaseAnalise identifica a etapa de processamento de uma análise.
+type FaseAnalise string
+
+const (
+	// FaseProcessando indica que os documentos ainda estão sendo analisados.
+	FaseProcessando FaseAnalise = "processando"
+	// FaseFinalizandoArquivo indica que a API está gerando o resultado da análise.
+	FaseFinalizandoArquivo FaseAnalise = "finalizando_arquivo"
+	// FaseConcluida indica que a análise foi concluída.
+	FaseConcluida FaseAnalise = "concluida"
+)
+
+// faseStatus deriva a fase a partir do status da análise.
+func faseStatus(status *StatusAnalise) FaseAnalise {
+	switch {
+	case status.Concluido:
+		return FaseConcluida
+	case status.FinalizandoArquivo:
+		return FaseFinalizandoArquivo
+	default:
+		return FaseProcessando
+	}
+}
+
+// EventoAnalise é um evento de progresso de uma análise acompanhada por WatchAnalise.
+type EventoAnalise struct {
+	AnaliseID int
+	Tipo      TipoEventoAnalise
+	Fase      FaseAnalise    // Fase após o evento
+	Status    *StatusAnalise // Último status conhecido (pode ser nil em eventos de erro)
+	Err       error          // Erro da consulta, em eventos EventoErro
+	Final     bool           // Último evento desta análise
+	Momento   time.Time
+}
+
+// WatchOptions configura o acompanhamento de análises. Como a consulta de cada análise é
+// compartilhada entre todos que a acompanham, as opções e a autenticação do primeiro
+// acompanhamento são usadas enquanto houver algum ativo.
+type WatchOptions struct {
+	IntervaloInicial        time.Duration // Padrão IntervaloInicialPadrao
+	IntervaloMaximo         time.Duration // Padrão IntervaloMaximoPadrao
+	Multiplicador           float64       // Padrão MultiplicadorIntervaloPadrao
+	MaximoErrosConsecutivos int           // Falhas seguidas toleradas antes do evento final de erro (padrão ErrosConsecutivosPadrao)
+}
+
+// espera converte as opções para o cálculo de intervalo de WaitOptions.
+func (o WatchOptions) espera() WaitOptions {
+	return WaitOptions{
+		IntervaloInicial:        o.IntervaloInicial,
+		IntervaloMaximo:         o.IntervaloMaximo,
+		Multiplicador:           o.Multiplicador,
+		MaximoErrosConsecutivos: o.MaximoErrosConsecutivos,
+	}.normaliza()
+}
+
+// WatchAnalise acompanha uma ou mais análises e retorna um canal com os eventos de progresso:
+// mudanças de status, de fase, a conclusão e erros de consulta. Análises acompanhadas por
+// mais de um chamador são consultadas uma única vez. O canal é fechado quando todas as
+// análises emitem o evento final ou quando o ctx termina.
+func (vc *VaduClient) WatchAnalise(ctx context.Context, analiseIDs []int, opts WatchOptions, auth AuthenticationInterface) (<-chan EventoAnalise, error) {
+	if len(analiseIDs) == 0 {
+		return nil, fmt.Errorf("nenhuma análise informada")
+	}
+	ids := make(map[int]bool, len(analiseIDs))
+	for _, id := range analiseIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("analiseID deve ser um número positivo")
+		}
+		ids[id] = true
+	}
+
+	a := &assinanteAnalise{sinal: make(chan struct{}, 1), pendentes: ids}
+	for id := range ids {
+		vc.acompanhamentos.assina(vc, id, a, opts, auth)
+	}
+
+	eventos := make(chan EventoAnalise)
+	go func() {
+		defer close(eventos)
+		defer vc.acompanhamentos.cancela(a, ids)
+		for {
+			evento, ok, fim := a.proximo()
+			if fim {
+				return
+			}
+			if !ok {
+				select {
+				case <-a.sinal:
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case eventos <- evento:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return eventos, nil
+}
+
+// assinanteAnalise acumula os eventos de um WatchAnalise até que sejam entregues, para que um
+// consumidor lento não atrase a consulta compartilhada.
+type assinanteAnalise struct {
+	mu        sync.Mutex
+	fila      []EventoAnalise
+	pendentes map[int]bool // Análises que ainda não emitiram o evento final
+	sinal     chan struct{}
+}
+
+// publica enfileira um evento e acorda o consumidor.
+func (a *assinanteAnalise) publica(evento EventoAnalise) {
+	a.mu.Lock()
+	a.fila = append(a.fila, evento)
+	a.mu.Unlock()
+	select {
+	case a.sinal <- struct{}{}:
+	default:
+	}
+}
+
+// proximo retira o próximo evento da fila. fim indica que a fila está vazia e todas as
+// análises já emitiram o evento final.
+func (a *assinanteAnalise) proximo() (evento EventoAnalise, ok bool, fim bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.fila) == 0 {
+		return EventoAnalise{}, false, len(a.pendentes) == 0
+	}
+	evento, a.fila = a.fila[0], a.fila[1:]
+	if evento.Final {
+		delete(a.pendentes, evento.AnaliseID)
+	}
+	return evento, true, false
+}
+
+// hubAnalises compartilha a consulta de status de cada análise entre os acompanhamentos ativos.
+type hubAnalises struct {
+	mu      sync.Mutex
+	pollers map[int]*pollerAnalise
+}
+
+func newHubAnalises() *hubAnalises {
+	return &hubAnalises{pollers: make(map[int]*pollerAnalise)}
+}
+
+// pollerAnalise consulta o status de uma análise e distribui os eventos aos assinantes.
+type pollerAnalise struct {
+	cancel     context.CancelFunc
+	assinantes map[*assinanteAnalise]struct{}
+	ultimo     *EventoAnalise // Último evento de status, repassado a novos assinantes
+}
+
+// assina registra o assinante na consulta da análise, iniciando-a se necessário.
+func (h *hubAnalises) assina(vc *VaduClient, analiseID int, a *assinanteAnalise, opts WatchOptions, auth AuthenticationInterface) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p, ok := h.pollers[analiseID]; ok {
+		p.assinantes[a] = struct{}{}
+		if p.ultimo != nil {
+			a.publica(*p.ultimo)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &pollerAnalise{cancel: cancel, assinantes: map[*assinanteAnalise]struct{}{a: {}}}
+	h.pollers[analiseID] = p
+	go h.consulta(ctx, vc, analiseID, p, opts.espera(), auth)
+}
+
+// cancela remove o assinante e encerra as consultas que ficaram sem assinantes.
+func (h *hubAnalises) cancela(a *assinanteAnalise, ids map[int]bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id := range ids {
+		p, ok := h.pollers[id]
+		if !ok {
+			continue
+		}
+		delete(p.assinantes, a)
+		if len(p.assinantes) == 0 {
+			p.cancel()
+			delete(h.pollers, id)
+		}
+	}
+}
+
+// distribui entrega o evento aos assinantes da análise. Eventos finais encerram a consulta.
+func (h *hubAnalises) distribui(analiseID int, p *pollerAnalise, evento EventoAnalise) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if evento.Tipo == EventoStatus || evento.Tipo == EventoFase {
+		p.ultimo = &evento
+	}
+	for a := range p.assinantes {
+		a.publica(evento)
+	}
+	if evento.Final && h.pollers[analiseID] == p {
+		delete(h.pollers, analiseID)
+	}
+}
+
+// consulta executa o ciclo de consultas de uma análise até o evento final ou o cancelamento.
+func (h *hubAnalises) consulta(ctx context.Context, vc *VaduClient, analiseID int, p *pollerAnalise, opts WaitOptions, auth AuthenticationInterface) {
+	defer p.cancel()
+
+	intervalo := opts.IntervaloInicial
+	var ultimo *StatusAnalise
+	errosConsecutivos := 0
+	for {
+		status, err := vc.PegaStatusAnalise(ctx, analiseID, auth)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			errosConsecutivos++
+			final := errosConsecutivos > opts.MaximoErrosConsecutivos
+			fase := FaseProcessando
+			if ultimo != nil {
+				fase = faseStatus(ultimo)
+			}
+			h.distribui(analiseID, p, EventoAnalise{
+				AnaliseID: analiseID, Tipo: EventoErro, Fase: fase, Status: ultimo, Err: err, Final: final, Momento: time.Now(),
+			})
+			if final {
+				vc.logger.WithFields(logrus.Fields{
+					"analiseID": analiseID,
+					"erros":     errosConsecutivos,
+				}).WithError(err).Error("Acompanhamento da análise encerrado por falhas consecutivas")
+				return
+			}
+			intervalo = opts.proximo(intervalo, false)
+		} else {
+			errosConsecutivos = 0
+			fase := faseStatus(status)
+			var eventos []EventoAnalise
+			switch {
+			case status.Concluido:
+				eventos = append(eventos, EventoAnalise{Tipo: EventoConcluida, Final: true})
+			case ultimo != nil && fase != faseStatus(ultimo):
+				eventos = append(eventos, EventoAnalise{Tipo: EventoFase})
+			case ultimo == nil ||
+				status.PercentualConcluido != ultimo.PercentualConcluido ||
+				status.PercentualConsultasReceita != ultimo.PercentualConsultasReceita ||
+				status.QuantidadeCNPJsCPFsConcluidos != ultimo.QuantidadeCNPJsCPFsConcluidos:
+				eventos = append(eventos, EventoAnalise{Tipo: EventoStatus})
+			}
+
+			avancou := len(eventos) > 0 || status.FinalizandoArquivo
+			if ultimo != nil {
+				intervalo = opts.proximo(intervalo, avancou)
+			}
+			ultimo = status
+
+			for _, evento := range eventos {
+				evento.AnaliseID, evento.Fase, evento.Status, evento.Momento = analiseID, fase, status, time.Now()
+				h.distribui(analiseID, p, evento)
+			}
+			if status.Concluido {
+				return
+			}
+		}
+
+		espera := time.NewTimer(intervalo)
+		select {
+		case <-ctx.Done():
+			espera.Stop()
+			return
+		case <-espera.C:
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package vadu_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AcompanhaAnaliseTestSuite struct {
+	suite.Suite
+	assert         *assert.Assertions
+	ctx            context.Context
+	logger         *logrus.Logger
+	authentication *mock.MockAuthentication
+}
+
+func TestAcompanhaAnaliseTestSuite(t *testing.T) {
+	suite.Run(t, new(AcompanhaAnaliseTestSuite))
+}
+
+func (s *AcompanhaAnaliseTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+	s.authentication = new(mock.MockAuthentication)
+	s.authentication.On("Token", testifymock.Anything).Return("mocked_token", nil)
+}
+
+// servidorStatus responde o status de cada análise com a próxima etapa da sequência,
+// repetindo a última, e conta as consultas por análise.
+type servidorStatus struct {
+	mu        sync.Mutex
+	etapas    map[int][]string
+	consultas map[int]int
+	liberado  chan struct{}
+}
+
+func (m *servidorStatus) client() *http.Client {
+	return &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if m.liberado != nil {
+				<-m.liberado
+			}
+			partes := strings.Split(req.URL.Path, "/")
+			var id int
+			fmt.Sscan(partes[len(partes)-1], &id)
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			etapas := m.etapas[id]
+			etapa := etapas[len(etapas)-1]
+			if m.consultas[id] < len(etapas) {
+				etapa = etapas[m.consultas[id]]
+			}
+			m.consultas[id]++
+			if etapa == "erro" {
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: ioutil.NopCloser(strings.NewReader("erro"))}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(etapa))}, nil
+		},
+	}}
+}
+
+func (m *servidorStatus) total(id int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.consultas[id]
+}
+
+const (
+	statusMetade      = `{"percentual_concluido": 50, "percentual_consultas_receita": 100}`
+	statusFinalizando = `{"percentual_concluido": 100, "percentual_consultas_receita": 100, "finalizando_arquivo": true}`
+	statusConcluido   = `{"percentual_concluido": 100, "percentual_consultas_receita": 100, "concluido": true}`
+)
+
+func (s *AcompanhaAnaliseTestSuite) client(servidor *servidorStatus) *vadu.VaduClient {
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	return vadu.NewVaduClient(servidor.client(), *session, s.logger)
+}
+
+func coleta(eventos <-chan vadu.EventoAnalise) []vadu.EventoAnalise {
+	var lista []vadu.EventoAnalise
+	for evento := range eventos {
+		lista = append(lista, evento)
+	}
+	return lista
+}
+
+var opcoesRapidas = vadu.WatchOptions{IntervaloInicial: time.Millisecond, IntervaloMaximo: 2 * time.Millisecond}
+
+// TestWatchAnalise verifica a sequência de eventos de várias análises e o fechamento do canal
+func (s *AcompanhaAnaliseTestSuite) TestWatchAnalise() {
+	servidor := &servidorStatus{
+		etapas: map[int][]string{
+			1: {statusMetade, statusMetade, "erro", statusFinalizando, statusConcluido},
+			2: {"erro"},
+		},
+		consultas: map[int]int{},
+	}
+	vaduClient := s.client(servidor)
+
+	eventos, err := vaduClient.WatchAnalise(s.ctx, []int{1, 2}, vadu.WatchOptions{IntervaloInicial: time.Millisecond, MaximoErrosConsecutivos: 1}, s.authentication)
+	s.assert.NoError(err)
+	lista := coleta(eventos)
+
+	var tipos []vadu.TipoEventoAnalise
+	var erros []vadu.EventoAnalise
+	for _, evento := range lista {
+		switch evento.AnaliseID {
+		case 1:
+			tipos = append(tipos, evento.Tipo)
+		case 2:
+			erros = append(erros, evento)
+		}
+	}
+	s.assert.Equal([]vadu.TipoEventoAnalise{vadu.EventoStatus, vadu.EventoErro, vadu.EventoFase, vadu.EventoConcluida}, tipos)
+	s.assert.Len(erros, 2)
+	s.assert.False(erros[0].Final)
+	s.assert.True(erros[1].Final)
+	s.assert.Error(erros[1].Err)
+
+	for _, evento := range lista {
+		if evento.AnaliseID == 1 && evento.Tipo == vadu.EventoFase {
+			s.assert.Equal(vadu.FaseFinalizandoArquivo, evento.Fase)
+		}
+		if evento.Tipo == vadu.EventoConcluida {
+			s.assert.True(evento.Final)
+			s.assert.True(evento.Status.Concluido)
+		}
+	}
+
+	_, err = vaduClient.WatchAnalise(s.ctx, nil, opcoesRapidas, s.authentication)
+	s.assert.Error(err)
+}
+
+// TestWatchAnaliseCompartilhado verifica que acompanhamentos da mesma análise compartilham as consultas
+func (s *AcompanhaAnaliseTestSuite) TestWatchAnaliseCompartilhado() {
+	servidor := &servidorStatus{
+		etapas:    map[int][]string{7: {statusMetade, statusFinalizando, statusConcluido}},
+		consultas: map[int]int{},
+		liberado:  make(chan struct{}),
+	}
+	vaduClient := s.client(servidor)
+
+	primeiro, err := vaduClient.WatchAnalise(s.ctx, []int{7}, opcoesRapidas, s.authentication)
+	s.assert.NoError(err)
+	segundo, err := vaduClient.WatchAnalise(s.ctx, []int{7}, opcoesRapidas, s.authentication)
+	s.assert.NoError(err)
+	close(servidor.liberado)
+
+	var wg sync.WaitGroup
+	var listas [2][]vadu.EventoAnalise
+	for i, eventos := range []<-chan vadu.EventoAnalise{primeiro, segundo} {
+		wg.Add(1)
+		go func(i int, eventos <-chan vadu.EventoAnalise) {
+			defer wg.Done()
+			listas[i] = coleta(eventos)
+		}(i, eventos)
+	}
+	wg.Wait()
+
+	s.assert.Equal(3, servidor.total(7))
+	s.assert.Len(listas[0], 3)
+	s.assert.Len(listas[1], 3)
+	s.assert.Equal(vadu.EventoConcluida, listas[1][2].Tipo)
+}
+
+// TestWatchAnaliseCancelamento verifica o fechamento do canal e o fim das consultas ao cancelar o contexto
+func (s *AcompanhaAnaliseTestSuite) TestWatchAnaliseCancelamento() {
+	servidor := &servidorStatus{etapas: map[int][]string{3: {statusMetade}}, consultas: map[int]int{}}
+	vaduClient := s.client(servidor)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	eventos, err := vaduClient.WatchAnalise(ctx, []int{3}, opcoesRapidas, s.authentication)
+	s.assert.NoError(err)
+
+	evento := <-eventos
+	s.assert.Equal(vadu.EventoStatus, evento.Tipo)
+	s.assert.Equal(50, evento.Status.PercentualConcluido)
+	cancel()
+
+	for range eventos {
+	}
+	time.Sleep(10 * time.Millisecond)
+	consultas := servidor.total(3)
+	time.Sleep(10 * time.Millisecond)
+	s.assert.Equal(consultas, servidor.total(3))
+}
@@ -0,0 +1,501 @@
+package vadu
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Valores padrão de SchedulerOptions.
+const (
+	// ToleranciaAtrasoPadrao é o atraso máximo para que uma execução seja considerada no horário.
+	ToleranciaAtrasoPadrao = 5 * time.Minute
+	// limiteHorariosPerdidos limita a contagem de horários perdidos após uma longa indisponibilidade.
+	limiteHorariosPerdidos = 100000
+)
+
+// Carteira define um conjunto de documentos reanalisado periodicamente pelo Scheduler.
+type Carteira struct {
+	Nome           string // Identificador único da carteira
+	CNPJEmpresa    string
+	IDGrupoAnalise int
+	Documentos     []string
+	Agenda         string // Expressão cron (ver ParseCron)
+	PostBack       *PostBack
+}
+
+// EstadoExecucao identifica o estado de uma execução agendada.
+type EstadoExecucao string
+
+const (
+	// ExecucaoEmAndamento indica que o envio da carteira está em andamento.
+	ExecucaoEmAndamento EstadoExecucao = "em_andamento"
+	// ExecucaoConcluida indica que todas as partes da carteira foram enviadas.
+	ExecucaoConcluida EstadoExecucao = "concluida"
+	// ExecucaoFalhou indica que o envio falhou total ou parcialmente, ou foi interrompido.
+	ExecucaoFalhou EstadoExecucao = "falhou"
+	// ExecucaoIgnorada indica um horário não executado por sobreposição ou por ter sido perdido.
+	ExecucaoIgnorada EstadoExecucao = "ignorada"
+)
+
+// Motivos de execuções ignoradas.
+const (
+	MotivoSobreposicao = "sobreposicao" // A execução anterior ainda estava em andamento
+	MotivoPerdida      = "perdida"      // O horário passou durante uma indisponibilidade
+)
+
+// ExecucaoCarteira registra uma execução agendada de uma carteira.
+type ExecucaoCarteira struct {
+	ID         string         `json:"id"` // Carteira e horário agendado, único por execução
+	Carteira   string         `json:"carteira"`
+	Agendada   time.Time      `json:"agendada"`
+	Inicio     time.Time      `json:"inicio"`
+	Fim        time.Time      `json:"fim"`
+	Estado     EstadoExecucao `json:"estado"`
+	Motivo     string         `json:"motivo,omitempty"`   // Motivo de execuções ignoradas
+	Perdidas   int            `json:"perdidas,omitempty"` // Horários anteriores perdidos cobertos por esta execução
+	AnaliseIDs []int          `json:"analise_ids,omitempty"`
+	Erro       string         `json:"erro,omitempty"`
+}
+
+// idExecucao gera o ID determinístico da execução da carteira no horário agendado.
+func idExecucao(carteira string, agendada time.Time) string {
+	return fmt.Sprintf("%s@%s", carteira, agendada.UTC().Format(time.RFC3339))
+}
+
+// RunStore persiste as execuções do Scheduler.
+type RunStore interface {
+	// Save grava a execução, substituindo a versão anterior com o mesmo ID.
+	Save(ctx context.Context, execucao ExecucaoCarteira) error
+	// Ultima retorna a execução mais recente da carteira, pelo horário agendado, ou nil.
+	Ultima(ctx context.Context, carteira string) (*ExecucaoCarteira, error)
+	// List retorna as execuções da carteira ordenadas pelo horário agendado.
+	List(ctx context.Context, carteira string) ([]ExecucaoCarteira, error)
+}
+
+// filtraExecucoes retorna as execuções da carteira ordenadas pelo horário agendado.
+func filtraExecucoes(execucoes map[string]ExecucaoCarteira, carteira string) []ExecucaoCarteira {
+	var lista []ExecucaoCarteira
+	for _, execucao := range execucoes {
+		if execucao.Carteira == carteira {
+			lista = append(lista, execucao)
+		}
+	}
+	sort.Slice(lista, func(i, j int) bool { return lista[i].Agendada.Before(lista[j].Agendada) })
+	return lista
+}
+
+// MemoryRunStore mantém as execuções em memória. Não sobrevive a reinícios; útil para testes.
+type MemoryRunStore struct {
+	mu        sync.Mutex
+	execucoes map[string]ExecucaoCarteira
+}
+
+// NewMemoryRunStore cria um RunStore em memória.
+func NewMemoryRunStore() *MemoryRunStore {
+	return &MemoryRunStore{execucoes: make(map[string]ExecucaoCarteira)}
+}
+
+// Save grava a execução.
+func (m *MemoryRunStore) Save(ctx context.Context, execucao ExecucaoCarteira) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.execucoes[execucao.ID] = execucao
+	return nil
+}
+
+// Ultima retorna a execução mais recente da carteira.
+func (m *MemoryRunStore) Ultima(ctx context.Context, carteira string) (*ExecucaoCarteira, error) {
+	lista, _ := m.List(ctx, carteira)
+	if len(lista) == 0 {
+		return nil, nil
+	}
+	return &lista[len(lista)-1], nil
+}
+
+// List retorna as execuções da carteira.
+func (m *MemoryRunStore) List(ctx context.Context, carteira string) ([]ExecucaoCarteira, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return filtraExecucoes(m.execucoes, carteira), nil
+}
+
+// FileRunStore grava as execuções em um arquivo JSON Lines. Cada Save acrescenta a versão da
+// execução ao final do arquivo; na leitura prevalece a última versão de cada ID.
+type FileRunStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileRunStore cria um RunStore que grava no arquivo informado.
+func NewFileRunStore(path string) *FileRunStore {
+	return &FileRunStore{path: path}
+}
+
+// Save acrescenta a versão da execução ao final do arquivo e força a gravação em disco.
+func (f *FileRunStore) Save(ctx context.Context, execucao ExecucaoCarteira) error {
+	data, err := json.Marshal(execucao)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Ultima retorna a execução mais recente da carteira.
+func (f *FileRunStore) Ultima(ctx context.Context, carteira string) (*ExecucaoCarteira, error) {
+	lista, err := f.List(ctx, carteira)
+	if err != nil || len(lista) == 0 {
+		return nil, err
+	}
+	return &lista[len(lista)-1], nil
+}
+
+// List lê o arquivo e retorna a última versão das execuções da carteira.
+func (f *FileRunStore) List(ctx context.Context, carteira string) ([]ExecucaoCarteira, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	execucoes := make(map[string]ExecucaoCarteira)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for linha := 1; scanner.Scan(); linha++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var execucao ExecucaoCarteira
+		if err := json.Unmarshal(scanner.Bytes(), &execucao); err != nil {
+			return nil, fmt.Errorf("execução inválida na linha %d: %w", linha, err)
+		}
+		execucoes[execucao.ID] = execucao
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return filtraExecucoes(execucoes, carteira), nil
+}
+
+// PoliticaPerdidas define o tratamento de horários perdidos durante uma indisponibilidade.
+type PoliticaPerdidas int
+
+const (
+	// PerdidasExecutaUma executa uma única vez, no lugar do horário perdido mais recente, e
+	// registra em Perdidas a quantidade de horários anteriores não executados (padrão).
+	PerdidasExecutaUma PoliticaPerdidas = iota
+	// PerdidasIgnora registra o horário perdido mais recente como ignorado e aguarda o próximo.
+	PerdidasIgnora
+)
+
+// String retorna o nome da política.
+func (p PoliticaPerdidas) String() string {
+	switch p {
+	case PerdidasExecutaUma:
+		return "executa_uma"
+	case PerdidasIgnora:
+		return "ignora"
+	default:
+		return fmt.Sprintf("PoliticaPerdidas(%d)", int(p))
+	}
+}
+
+// SchedulerOptions configura o Scheduler.
+type SchedulerOptions struct {
+	Perdidas         PoliticaPerdidas // Tratamento de horários perdidos (padrão PerdidasExecutaUma)
+	ToleranciaAtraso time.Duration    // Atraso tolerado antes de um horário ser considerado perdido (padrão ToleranciaAtrasoPadrao)
+	Localizacao      *time.Location   // Fuso das agendas (padrão LocalizacaoSaoPaulo)
+	Lote             BatchOptions     // Divisão e concorrência do envio de cada carteira
+}
+
+// normaliza aplica os valores padrão.
+func (o SchedulerOptions) normaliza() SchedulerOptions {
+	if o.ToleranciaAtraso <= 0 {
+		o.ToleranciaAtraso = ToleranciaAtrasoPadrao
+	}
+	if o.Localizacao == nil {
+		o.Localizacao = LocalizacaoSaoPaulo()
+	}
+	return o
+}
+
+// carteiraAgendada associa a carteira à agenda interpretada e ao estado em memória.
+type carteiraAgendada struct {
+	Carteira
+	cron        *Cron
+	verificacao sync.Mutex // Serializa as verificações da carteira; protege referencia
+	referencia  time.Time  // Primeira verificação, início da contagem quando não há execuções persistidas
+	ativa       bool       // Envio em andamento neste processo; protegido por Scheduler.mu
+}
+
+// Scheduler reanalisa carteiras periodicamente conforme as agendas cron, enviando cada carteira
+// com SubmitBatch e persistindo cada execução em um RunStore. Uma execução não começa enquanto
+// a anterior da mesma carteira estiver em andamento; o horário é registrado como ignorado.
+//
+// A cada verificação, os horários entre a última execução persistida e o momento atual são
+// contados. Um único horário dentro de ToleranciaAtraso é executado normalmente; horários
+// perdidos (mais de um, ou atrasados além da tolerância) seguem a PoliticaPerdidas. Como o ID
+// de cada execução é determinado pela carteira e pelo horário, o resultado independe de
+// quantas verificações ocorrerem durante ou após a indisponibilidade.
+type Scheduler struct {
+	vc    *VaduClient
+	store RunStore
+	auth  AuthenticationInterface
+	opts  SchedulerOptions
+
+	mu        sync.Mutex
+	carteiras map[string]*carteiraAgendada
+	wg        sync.WaitGroup
+}
+
+// NewScheduler cria um Scheduler que usa o cliente e a autenticação informados.
+func NewScheduler(vc *VaduClient, store RunStore, auth AuthenticationInterface, opts SchedulerOptions) *Scheduler {
+	return &Scheduler{
+		vc:        vc,
+		store:     store,
+		auth:      auth,
+		opts:      opts.normaliza(),
+		carteiras: make(map[string]*carteiraAgendada),
+	}
+}
+
+// Adiciona registra uma carteira no Scheduler. Sem execuções persistidas, a contagem de
+// horários começa na primeira verificação após a adição.
+func (s *Scheduler) Adiciona(carteira Carteira) error {
+	if carteira.Nome == "" {
+		return fmt.Errorf("o nome da carteira é obrigatório")
+	}
+	if len(carteira.Documentos) == 0 {
+		return fmt.Errorf("a carteira %q não possui documentos", carteira.Nome)
+	}
+	cron, err := ParseCron(carteira.Agenda)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.carteiras[carteira.Nome]; ok {
+		return fmt.Errorf("a carteira %q já está registrada", carteira.Nome)
+	}
+	s.carteiras[carteira.Nome] = &carteiraAgendada{Carteira: carteira, cron: cron}
+	return nil
+}
+
+// Execucoes retorna as execuções persistidas da carteira.
+func (s *Scheduler) Execucoes(ctx context.Context, carteira string) ([]ExecucaoCarteira, error) {
+	return s.store.List(ctx, carteira)
+}
+
+// Run verifica as agendas até o fim do ctx, iniciando as execuções devidas. Ao terminar,
+// aguarda os envios em andamento e retorna o erro do contexto.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		agora := time.Now()
+		s.RunPending(ctx, agora)
+
+		// Verifica no próximo horário agendado ou, no máximo, a cada minuto
+		espera := time.Minute
+		s.mu.Lock()
+		for _, carteira := range s.carteiras {
+			if proximo := carteira.cron.Proximo(agora.In(s.opts.Localizacao)); !proximo.IsZero() && proximo.Sub(agora) < espera {
+				espera = proximo.Sub(agora)
+			}
+		}
+		s.mu.Unlock()
+
+		timer := time.NewTimer(espera)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.Wait()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RunPending verifica as agendas no momento agora e inicia em segundo plano as execuções
+// devidas, retornando as execuções registradas nesta verificação. Use Wait para aguardar
+// os envios iniciados.
+func (s *Scheduler) RunPending(ctx context.Context, agora time.Time) []ExecucaoCarteira {
+	s.mu.Lock()
+	nomes := make([]string, 0, len(s.carteiras))
+	for nome := range s.carteiras {
+		nomes = append(nomes, nome)
+	}
+	s.mu.Unlock()
+	sort.Strings(nomes)
+
+	var registradas []ExecucaoCarteira
+	for _, nome := range nomes {
+		execucao, err := s.verifica(ctx, nome, agora.In(s.opts.Localizacao))
+		if err != nil {
+			s.vc.logger.WithField("carteira", nome).WithError(err).Error("Erro ao verificar agenda da carteira")
+			continue
+		}
+		if execucao != nil {
+			registradas = append(registradas, *execucao)
+		}
+	}
+	return registradas
+}
+
+// Wait aguarda os envios em andamento.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// verifica registra a execução devida da carteira no momento agora, se houver.
+func (s *Scheduler) verifica(ctx context.Context, nome string, agora time.Time) (*ExecucaoCarteira, error) {
+	s.mu.Lock()
+	carteira := s.carteiras[nome]
+	s.mu.Unlock()
+	carteira.verificacao.Lock()
+	defer carteira.verificacao.Unlock()
+
+	// ativa é lida antes da consulta: executa persiste o fim da execução antes de liberá-la,
+	// então uma execução inativa aqui já tem o resultado gravado no RunStore
+	s.mu.Lock()
+	ativa := carteira.ativa
+	s.mu.Unlock()
+	execucoes, err := s.store.List(ctx, nome)
+	if err != nil {
+		return nil, err
+	}
+	var ultima *ExecucaoCarteira
+	if len(execucoes) > 0 {
+		ultima = &execucoes[len(execucoes)-1]
+	}
+
+	if ultima == nil && carteira.referencia.IsZero() {
+		carteira.referencia = agora
+		return nil, nil
+	}
+	referencia := carteira.referencia.In(agora.Location())
+	if ultima != nil {
+		referencia = ultima.Agendada.In(agora.Location())
+	}
+
+	// Execuções interrompidas por um reinício não são retomadas. A interrompida pode não ser
+	// a última quando os horários seguintes foram ignorados por sobreposição.
+	if !ativa {
+		for _, execucao := range execucoes {
+			if execucao.Estado != ExecucaoEmAndamento {
+				continue
+			}
+			execucao.Estado, execucao.Erro, execucao.Fim = ExecucaoFalhou, "execução interrompida antes da conclusão", agora
+			if err := s.store.Save(ctx, execucao); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Horários devidos entre a referência e agora
+	var horario time.Time
+	devidos := 0
+	for proximo := carteira.cron.Proximo(referencia); !proximo.IsZero() && !proximo.After(agora) && devidos < limiteHorariosPerdidos; proximo = carteira.cron.Proximo(proximo) {
+		horario = proximo
+		devidos++
+	}
+	if devidos == 0 {
+		return nil, nil
+	}
+
+	execucao := ExecucaoCarteira{
+		ID:       idExecucao(nome, horario),
+		Carteira: nome,
+		Agendada: horario,
+		Estado:   ExecucaoEmAndamento,
+		Perdidas: devidos - 1,
+	}
+	perdida := devidos > 1 || agora.Sub(horario) > s.opts.ToleranciaAtraso
+	switch {
+	case ativa:
+		execucao.Estado, execucao.Motivo = ExecucaoIgnorada, MotivoSobreposicao
+	case perdida && s.opts.Perdidas == PerdidasIgnora:
+		execucao.Estado, execucao.Motivo = ExecucaoIgnorada, MotivoPerdida
+	default:
+		execucao.Inicio = agora
+	}
+
+	if err := s.store.Save(ctx, execucao); err != nil {
+		return nil, err
+	}
+
+	campos := logrus.Fields{
+		"carteira": nome,
+		"agendada": horario,
+		"perdidas": execucao.Perdidas,
+	}
+	if execucao.Estado == ExecucaoIgnorada {
+		s.vc.logger.WithFields(campos).WithField("motivo", execucao.Motivo).Warn("Execução da carteira ignorada")
+		return &execucao, nil
+	}
+
+	s.vc.logger.WithFields(campos).Info("Iniciando execução da carteira")
+	s.mu.Lock()
+	carteira.ativa = true
+	s.mu.Unlock()
+	s.wg.Add(1)
+	go s.executa(ctx, carteira, execucao)
+	return &execucao, nil
+}
+
+// executa envia a carteira e registra o resultado da execução.
+func (s *Scheduler) executa(ctx context.Context, carteira *carteiraAgendada, execucao ExecucaoCarteira) {
+	defer s.wg.Done()
+
+	submission, err := s.vc.SubmitBatch(ctx, carteira.CNPJEmpresa, carteira.IDGrupoAnalise, carteira.Documentos, carteira.PostBack, s.opts.Lote, s.auth)
+	if submission != nil {
+		execucao.AnaliseIDs = submission.AnaliseIDs()
+	}
+	execucao.Fim = time.Now().In(s.opts.Localizacao)
+	execucao.Estado = ExecucaoConcluida
+	if err != nil {
+		execucao.Estado, execucao.Erro = ExecucaoFalhou, err.Error()
+		s.vc.logger.WithFields(logrus.Fields{
+			"carteira": carteira.Nome,
+			"agendada": execucao.Agendada,
+		}).WithError(err).Error("Falha na execução da carteira")
+	}
+
+	// A gravação não usa o ctx para que o resultado seja persistido mesmo no encerramento
+	if err := s.store.Save(context.Background(), execucao); err != nil {
+		s.vc.logger.WithFields(logrus.Fields{
+			"carteira": carteira.Nome,
+			"agendada": execucao.Agendada,
+		}).WithError(err).Error("Erro ao persistir execução da carteira")
+	}
+
+	s.mu.Lock()
+	carteira.ativa = false
+	s.mu.Unlock()
+}
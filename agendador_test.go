@@ -0,0 +1,194 @@
+package vadu_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AgendadorTestSuite struct {
+	suite.Suite
+	assert         *assert.Assertions
+	ctx            context.Context
+	logger         *logrus.Logger
+	authentication *mock.MockAuthentication
+	loc            *time.Location
+}
+
+func TestAgendadorTestSuite(t *testing.T) {
+	suite.Run(t, new(AgendadorTestSuite))
+}
+
+func (s *AgendadorTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+	s.authentication = new(mock.MockAuthentication)
+	s.authentication.On("Token", testifymock.Anything).Return("mocked_token", nil)
+	s.loc = vadu.LocalizacaoSaoPaulo()
+}
+
+func (s *AgendadorTestSuite) client(httpClient *http.Client) *vadu.VaduClient {
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	return vadu.NewVaduClient(httpClient, *session, s.logger)
+}
+
+func (s *AgendadorTestSuite) data(dia, hora, minuto int) time.Time {
+	return time.Date(2024, time.January, dia, hora, minuto, 0, 0, s.loc)
+}
+
+var carteiraDiaria = vadu.Carteira{
+	Nome:           "ativos",
+	CNPJEmpresa:    "33011770000199",
+	IDGrupoAnalise: 10802,
+	Documentos:     []string{"98960887000164", "00360305000104", "52998224725"},
+	Agenda:         "@daily",
+}
+
+// TestScheduler verifica a execução no horário, os horários perdidos e a retomada após um reinício
+func (s *AgendadorTestSuite) TestScheduler() {
+	servidor := &servidorLote{}
+	store := vadu.NewFileRunStore(filepath.Join(s.T().TempDir(), "execucoes.jsonl"))
+	scheduler := vadu.NewScheduler(s.client(servidor.client()), store, s.authentication, vadu.SchedulerOptions{Lote: vadu.BatchOptions{TamanhoParte: 2}})
+	s.assert.NoError(scheduler.Adiciona(carteiraDiaria))
+	s.assert.Error(scheduler.Adiciona(carteiraDiaria))
+	s.assert.ErrorIs(scheduler.Adiciona(vadu.Carteira{Nome: "x", Documentos: []string{"1"}, Agenda: "toda hora"}), vadu.ErrCronInvalido)
+
+	// A primeira verificação apenas inicia a contagem
+	s.assert.Empty(scheduler.RunPending(s.ctx, s.data(10, 12, 0)))
+
+	execucoes := scheduler.RunPending(s.ctx, s.data(11, 0, 1))
+	s.assert.Len(execucoes, 1)
+	s.assert.Equal(vadu.ExecucaoEmAndamento, execucoes[0].Estado)
+	s.assert.True(s.data(11, 0, 0).Equal(execucoes[0].Agendada))
+	scheduler.Wait()
+	s.assert.Empty(scheduler.RunPending(s.ctx, s.data(11, 0, 2)))
+
+	lista, err := scheduler.Execucoes(s.ctx, "ativos")
+	s.assert.NoError(err)
+	s.assert.Len(lista, 1)
+	s.assert.Equal(vadu.ExecucaoConcluida, lista[0].Estado)
+	s.assert.Len(lista[0].AnaliseIDs, 2)
+
+	// Após uma indisponibilidade, uma única execução cobre os horários perdidos
+	execucoes = scheduler.RunPending(s.ctx, s.data(15, 10, 0))
+	s.assert.Len(execucoes, 1)
+	s.assert.True(s.data(15, 0, 0).Equal(execucoes[0].Agendada))
+	s.assert.Equal(3, execucoes[0].Perdidas)
+	scheduler.Wait()
+	s.assert.Len(servidor.requisicoes, 4)
+
+	// Reinício com a política de ignorar horários perdidos
+	scheduler = vadu.NewScheduler(s.client(servidor.client()), store, s.authentication, vadu.SchedulerOptions{Perdidas: vadu.PerdidasIgnora})
+	s.assert.NoError(scheduler.Adiciona(carteiraDiaria))
+	execucoes = scheduler.RunPending(s.ctx, s.data(18, 9, 0))
+	s.assert.Len(execucoes, 1)
+	s.assert.Equal(vadu.ExecucaoIgnorada, execucoes[0].Estado)
+	s.assert.Equal(vadu.MotivoPerdida, execucoes[0].Motivo)
+	s.assert.Equal(2, execucoes[0].Perdidas)
+
+	execucoes = scheduler.RunPending(s.ctx, s.data(19, 0, 2))
+	s.assert.Len(execucoes, 1)
+	s.assert.Equal(vadu.ExecucaoEmAndamento, execucoes[0].Estado)
+	scheduler.Wait()
+	s.assert.Len(servidor.requisicoes, 5)
+
+	lista, err = scheduler.Execucoes(s.ctx, "ativos")
+	s.assert.NoError(err)
+	s.assert.Len(lista, 4)
+}
+
+// TestSchedulerSobreposicao verifica que uma execução não começa enquanto a anterior estiver em andamento
+func (s *AgendadorTestSuite) TestSchedulerSobreposicao() {
+	servidor := &servidorLote{}
+	liberado := make(chan struct{})
+	httpClient := servidor.client()
+	transporte := httpClient.Transport.(*mock.MockAuthHTTPClient)
+	envia := transporte.DoFunc
+	transporte.DoFunc = func(req *http.Request) (*http.Response, error) {
+		<-liberado
+		return envia(req)
+	}
+
+	store := vadu.NewMemoryRunStore()
+	scheduler := vadu.NewScheduler(s.client(httpClient), store, s.authentication, vadu.SchedulerOptions{})
+	carteira := carteiraDiaria
+	carteira.Agenda = "*/10 * * * *"
+	s.assert.NoError(scheduler.Adiciona(carteira))
+
+	scheduler.RunPending(s.ctx, s.data(10, 9, 55))
+	s.assert.Len(scheduler.RunPending(s.ctx, s.data(10, 10, 0)), 1)
+	execucoes := scheduler.RunPending(s.ctx, s.data(10, 10, 10))
+	s.assert.Len(execucoes, 1)
+	s.assert.Equal(vadu.ExecucaoIgnorada, execucoes[0].Estado)
+	s.assert.Equal(vadu.MotivoSobreposicao, execucoes[0].Motivo)
+
+	close(liberado)
+	scheduler.Wait()
+	lista, err := scheduler.Execucoes(s.ctx, "ativos")
+	s.assert.NoError(err)
+	s.assert.Len(lista, 2)
+	s.assert.Equal(vadu.ExecucaoConcluida, lista[0].Estado)
+	s.assert.Equal(vadu.ExecucaoIgnorada, lista[1].Estado)
+
+	// Uma execução em andamento persistida por um processo anterior é marcada como falha
+	s.assert.NoError(store.Save(s.ctx, vadu.ExecucaoCarteira{ID: "interrompida", Carteira: "ativos", Agendada: s.data(10, 10, 20), Estado: vadu.ExecucaoEmAndamento}))
+	scheduler = vadu.NewScheduler(s.client(httpClient), store, s.authentication, vadu.SchedulerOptions{})
+	s.assert.NoError(scheduler.Adiciona(carteira))
+	s.assert.Empty(scheduler.RunPending(s.ctx, s.data(10, 10, 25)))
+	ultima, err := store.Ultima(s.ctx, "ativos")
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.ExecucaoFalhou, ultima.Estado)
+	s.assert.NotEmpty(ultima.Erro)
+}
+
+// TestSchedulerReinicioAposSobreposicao verifica que a execução interrompida é marcada como falha
+// mesmo quando um horário seguinte foi ignorado por sobreposição
+func (s *AgendadorTestSuite) TestSchedulerReinicioAposSobreposicao() {
+	servidor := &servidorLote{}
+	liberado := make(chan struct{})
+	httpClient := servidor.client()
+	transporte := httpClient.Transport.(*mock.MockAuthHTTPClient)
+	envia := transporte.DoFunc
+	transporte.DoFunc = func(req *http.Request) (*http.Response, error) {
+		<-liberado
+		return envia(req)
+	}
+
+	// O processo é encerrado com a execução das 10:00 em andamento e a das 10:10 ignorada
+	store := vadu.NewFileRunStore(filepath.Join(s.T().TempDir(), "execucoes.jsonl"))
+	scheduler := vadu.NewScheduler(s.client(httpClient), store, s.authentication, vadu.SchedulerOptions{})
+	carteira := carteiraDiaria
+	carteira.Agenda = "*/10 * * * *"
+	s.assert.NoError(scheduler.Adiciona(carteira))
+	scheduler.RunPending(s.ctx, s.data(10, 9, 55))
+	s.assert.Len(scheduler.RunPending(s.ctx, s.data(10, 10, 0)), 1)
+	ignorada := scheduler.RunPending(s.ctx, s.data(10, 10, 10))
+	s.assert.Equal(vadu.ExecucaoIgnorada, ignorada[0].Estado)
+
+	// Após o reinício, a execução das 10:00 não fica em andamento indefinidamente
+	reiniciado := vadu.NewScheduler(s.client(servidor.client()), store, s.authentication, vadu.SchedulerOptions{})
+	s.assert.NoError(reiniciado.Adiciona(carteira))
+	s.assert.Empty(reiniciado.RunPending(s.ctx, s.data(10, 10, 15)))
+	lista, err := reiniciado.Execucoes(s.ctx, "ativos")
+	s.assert.NoError(err)
+	s.assert.Len(lista, 2)
+	s.assert.Equal(vadu.ExecucaoFalhou, lista[0].Estado)
+	s.assert.NotEmpty(lista[0].Erro)
+	s.assert.Equal(vadu.ExecucaoIgnorada, lista[1].Estado)
+
+	close(liberado)
+	scheduler.Wait()
+}
@@ -0,0 +1,175 @@
+package vadu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Valores padrão de WaitOptions.
+const (
+	IntervaloInicialPadrao       = 2 * time.Second
+	IntervaloMaximoPadrao        = 30 * time.Second
+	MultiplicadorIntervaloPadrao = 1.5
+	ErrosConsecutivosPadrao      = 3
+)
+
+// ErrAnaliseNaoConcluida indica que o contexto terminou antes da conclusão da análise.
+var ErrAnaliseNaoConcluida = errors.New("análise não concluída")
+
+// ProgressoAnalise é informado ao callback de progresso a cada consulta de status.
+type ProgressoAnalise struct {
+	AnaliseID                  int
+	PercentualConcluido        int
+	PercentualConsultasReceita int
+	Consulta                   int           // Número da consulta, a partir de 1
+	ProximoIntervalo           time.Duration // Espera até a próxima consulta (zero quando concluída)
+	Status                     StatusAnalise
+}
+
+// WaitOptions configura a espera pela conclusão de uma análise.
+//
+// O intervalo entre consultas começa em IntervaloInicial e é multiplicado por Multiplicador,
+// até IntervaloMaximo, enquanto o progresso não avança. Quando o percentual concluído ou o de
+// consultas à Receita avança, ou quando a API indica que está finalizando o arquivo, o
+// intervalo volta ao inicial.
+type WaitOptions struct {
+	IntervaloInicial        time.Duration          // Padrão IntervaloInicialPadrao
+	IntervaloMaximo         time.Duration          // Padrão IntervaloMaximoPadrao
+	Multiplicador           float64                // Padrão MultiplicadorIntervaloPadrao; valores <= 1 mantêm o intervalo fixo
+	MaximoErrosConsecutivos int                    // Falhas seguidas de PegaStatusAnalise toleradas (padrão ErrosConsecutivosPadrao)
+	Progresso               func(ProgressoAnalise) // Chamado após cada consulta bem-sucedida, opcional
+}
+
+// normaliza aplica os valores padrão.
+func (o WaitOptions) normaliza() WaitOptions {
+	if o.IntervaloInicial <= 0 {
+		o.IntervaloInicial = IntervaloInicialPadrao
+	}
+	if o.IntervaloMaximo <= 0 {
+		o.IntervaloMaximo = IntervaloMaximoPadrao
+	}
+	if o.IntervaloMaximo < o.IntervaloInicial {
+		o.IntervaloMaximo = o.IntervaloInicial
+	}
+	if o.Multiplicador == 0 {
+		o.Multiplicador = MultiplicadorIntervaloPadrao
+	}
+	if o.MaximoErrosConsecutivos <= 0 {
+		o.MaximoErrosConsecutivos = ErrosConsecutivosPadrao
+	}
+	return o
+}
+
+// proximo calcula o intervalo até a próxima consulta.
+func (o WaitOptions) proximo(atual time.Duration, avancou bool) time.Duration {
+	if avancou || o.Multiplicador <= 1 {
+		return o.IntervaloInicial
+	}
+	proximo := time.Duration(float64(atual) * o.Multiplicador)
+	if proximo > o.IntervaloMaximo {
+		proximo = o.IntervaloMaximo
+	}
+	return proximo
+}
+
+// WaitError é retornado por WaitForAnalise quando a análise não é concluída.
+type WaitError struct {
+	AnaliseID    int
+	UltimoStatus *StatusAnalise // Último status obtido, ou nil se nenhuma consulta teve sucesso
+	Err          error          // Erro do contexto ou da última consulta
+}
+
+// Error descreve o motivo e o último progresso conhecido.
+func (e *WaitError) Error() string {
+	if e.UltimoStatus == nil {
+		return fmt.Sprintf("%s: análise %d: %v", ErrAnaliseNaoConcluida.Error(), e.AnaliseID, e.Err)
+	}
+	return fmt.Sprintf("%s: análise %d com %d%% concluído: %v", ErrAnaliseNaoConcluida.Error(), e.AnaliseID, e.UltimoStatus.PercentualConcluido, e.Err)
+}
+
+// Is permite comparar o erro com ErrAnaliseNaoConcluida via errors.Is.
+func (e *WaitError) Is(target error) bool {
+	return target == ErrAnaliseNaoConcluida
+}
+
+// Unwrap retorna o erro do contexto ou da última consulta.
+func (e *WaitError) Unwrap() error {
+	return e.Err
+}
+
+// WaitForAnalise consulta PegaStatusAnalise até que StatusAnalise.Concluido seja verdadeiro,
+// com intervalo adaptativo configurado em opts. O prazo é controlado pelo ctx: quando ele
+// termina, ou quando PegaStatusAnalise falha mais de opts.MaximoErrosConsecutivos vezes
+// seguidas, é retornado um *WaitError junto com o último status obtido.
+func (vc *VaduClient) WaitForAnalise(ctx context.Context, analiseID int, opts WaitOptions, auth AuthenticationInterface) (*StatusAnalise, error) {
+	if analiseID <= 0 {
+		return nil, fmt.Errorf("analiseID deve ser um número positivo")
+	}
+	opts = opts.normaliza()
+	intervalo := opts.IntervaloInicial
+
+	var ultimo *StatusAnalise
+	errosConsecutivos := 0
+	for consulta := 1; ; consulta++ {
+		status, err := vc.PegaStatusAnalise(ctx, analiseID, auth)
+		switch {
+		case ctx.Err() != nil:
+			return ultimo, &WaitError{AnaliseID: analiseID, UltimoStatus: ultimo, Err: ctx.Err()}
+		case err != nil:
+			errosConsecutivos++
+			vc.logger.WithFields(logrus.Fields{
+				"analiseID": analiseID,
+				"consulta":  consulta,
+				"erros":     errosConsecutivos,
+			}).WithError(err).Warn("Erro ao consultar status durante a espera da análise")
+			if errosConsecutivos > opts.MaximoErrosConsecutivos {
+				return ultimo, &WaitError{AnaliseID: analiseID, UltimoStatus: ultimo, Err: err}
+			}
+			intervalo = opts.proximo(intervalo, false)
+		default:
+			errosConsecutivos = 0
+			avancou := ultimo == nil ||
+				status.PercentualConcluido > ultimo.PercentualConcluido ||
+				status.PercentualConsultasReceita > ultimo.PercentualConsultasReceita ||
+				status.FinalizandoArquivo
+			if consulta > 1 {
+				intervalo = opts.proximo(intervalo, avancou)
+			}
+			ultimo = status
+
+			if opts.Progresso != nil {
+				progresso := ProgressoAnalise{
+					AnaliseID:                  analiseID,
+					PercentualConcluido:        status.PercentualConcluido,
+					PercentualConsultasReceita: status.PercentualConsultasReceita,
+					Consulta:                   consulta,
+					ProximoIntervalo:           intervalo,
+					Status:                     *status,
+				}
+				if status.Concluido {
+					progresso.ProximoIntervalo = 0
+				}
+				opts.Progresso(progresso)
+			}
+			if status.Concluido {
+				vc.logger.WithFields(logrus.Fields{
+					"analiseID": analiseID,
+					"consultas": consulta,
+				}).Info("Análise concluída")
+				return status, nil
+			}
+		}
+
+		espera := time.NewTimer(intervalo)
+		select {
+		case <-ctx.Done():
+			espera.Stop()
+			return ultimo, &WaitError{AnaliseID: analiseID, UltimoStatus: ultimo, Err: ctx.Err()}
+		case <-espera.C:
+		}
+	}
+}
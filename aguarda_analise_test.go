@@ -0,0 +1,134 @@
+package vadu_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AguardaAnaliseTestSuite struct {
+	suite.Suite
+	assert         *assert.Assertions
+	ctx            context.Context
+	logger         *logrus.Logger
+	authentication *mock.MockAuthentication
+}
+
+func TestAguardaAnaliseTestSuite(t *testing.T) {
+	suite.Run(t, new(AguardaAnaliseTestSuite))
+}
+
+func (s *AguardaAnaliseTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+	s.authentication = new(mock.MockAuthentication)
+	s.authentication.On("Token", testifymock.Anything).Return("mocked_token", nil)
+}
+
+// statusSequencia retorna um cliente que responde cada consulta com o próximo percentual da
+// sequência, repetindo o último; percentuais negativos resultam em erro HTTP.
+func (s *AguardaAnaliseTestSuite) statusSequencia(percentuais ...int) (*vadu.VaduClient, *int) {
+	var mu sync.Mutex
+	consultas := 0
+	httpClient := &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if err := req.Context().Err(); err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			percentual := percentuais[len(percentuais)-1]
+			if consultas < len(percentuais) {
+				percentual = percentuais[consultas]
+			}
+			consultas++
+			if percentual < 0 {
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: ioutil.NopCloser(strings.NewReader("erro"))}, nil
+			}
+			body := fmt.Sprintf(`{"percentual_concluido": %d, "percentual_consultas_receita": %d, "concluido": %t}`,
+				percentual, percentual/2, percentual == 100)
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		},
+	}}
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	return vadu.NewVaduClient(httpClient, *session, s.logger), &consultas
+}
+
+// TestWaitForAnalise verifica a espera até a conclusão, o callback de progresso e o intervalo adaptativo
+func (s *AguardaAnaliseTestSuite) TestWaitForAnalise() {
+	vaduClient, consultas := s.statusSequencia(10, 10, 10, -1, 60, 100)
+
+	var progressos []vadu.ProgressoAnalise
+	opts := vadu.WaitOptions{
+		IntervaloInicial: time.Millisecond,
+		IntervaloMaximo:  3 * time.Millisecond,
+		Multiplicador:    2,
+		Progresso:        func(p vadu.ProgressoAnalise) { progressos = append(progressos, p) },
+	}
+	status, err := vaduClient.WaitForAnalise(s.ctx, 4768906, opts, s.authentication)
+	s.assert.NoError(err)
+	s.assert.True(status.Concluido)
+	s.assert.Equal(6, *consultas)
+
+	s.assert.Len(progressos, 5)
+	s.assert.Equal(10, progressos[0].PercentualConcluido)
+	s.assert.Equal(5, progressos[0].PercentualConsultasReceita)
+	s.assert.Equal(time.Millisecond, progressos[0].ProximoIntervalo)
+	s.assert.Equal(2*time.Millisecond, progressos[1].ProximoIntervalo)
+	s.assert.Equal(3*time.Millisecond, progressos[2].ProximoIntervalo)
+	s.assert.Equal(time.Millisecond, progressos[3].ProximoIntervalo)
+	s.assert.Equal(5, progressos[3].Consulta)
+	s.assert.Equal(100, progressos[4].PercentualConcluido)
+	s.assert.Zero(progressos[4].ProximoIntervalo)
+}
+
+// TestWaitForAnaliseTimeout verifica o retorno do último status quando o prazo do contexto termina
+func (s *AguardaAnaliseTestSuite) TestWaitForAnaliseTimeout() {
+	vaduClient, _ := s.statusSequencia(20, 40)
+
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Millisecond)
+	defer cancel()
+	status, err := vaduClient.WaitForAnalise(ctx, 4768906, vadu.WaitOptions{IntervaloInicial: time.Millisecond}, s.authentication)
+	s.assert.ErrorIs(err, vadu.ErrAnaliseNaoConcluida)
+	s.assert.ErrorIs(err, context.DeadlineExceeded)
+	s.assert.NotNil(status)
+	s.assert.Equal(40, status.PercentualConcluido)
+
+	var waitErr *vadu.WaitError
+	s.assert.True(errors.As(err, &waitErr))
+	s.assert.Equal(status, waitErr.UltimoStatus)
+
+	// Falhas consecutivas acima do limite encerram a espera
+	vaduClient, consultas := s.statusSequencia(-1)
+	status, err = vaduClient.WaitForAnalise(s.ctx, 4768906, vadu.WaitOptions{IntervaloInicial: time.Millisecond, MaximoErrosConsecutivos: 2}, s.authentication)
+	s.assert.ErrorIs(err, vadu.ErrAnaliseNaoConcluida)
+	s.assert.Nil(status)
+	s.assert.Equal(3, *consultas)
+}
+
+// TestPegaStatusAnaliseContexto verifica que o contexto do chamador é propagado para a requisição
+func (s *AguardaAnaliseTestSuite) TestPegaStatusAnaliseContexto() {
+	vaduClient, consultas := s.statusSequencia(100)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	_, err := vaduClient.PegaStatusAnalise(ctx, 4768906, s.authentication)
+	s.assert.ErrorIs(err, context.Canceled)
+	s.assert.Zero(*consultas)
+}
@@ -0,0 +1,237 @@
+package vadu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrAnaliseIncompleta indica que o ciclo de Analyze foi interrompido antes de reunir o resultado completo.
+var ErrAnaliseIncompleta = errors.New("análise incompleta")
+
+// EtapaAnalise identifica a etapa do ciclo de Analyze.
+type EtapaAnalise string
+
+const (
+	// EtapaEnvio é o envio dos documentos para análise.
+	EtapaEnvio EtapaAnalise = "envio"
+	// EtapaEspera é a espera pela conclusão da análise.
+	EtapaEspera EtapaAnalise = "espera"
+	// EtapaResumo é a consulta do resumo da análise.
+	EtapaResumo EtapaAnalise = "resumo"
+	// EtapaResumoDocumentos é a consulta do resumo de cada documento.
+	EtapaResumoDocumentos EtapaAnalise = "resumo_documentos"
+	// EtapaLogs é a consulta dos logs das regras de cada documento.
+	EtapaLogs EtapaAnalise = "logs"
+)
+
+// AnalysisRequest descreve uma análise a ser executada por Analyze. Quando ListaDados é
+// informada, os documentos são enviados com EnviaCNPJsComDadosParaAnalise e ListaCNPJCPF é
+// ignorada; caso contrário, com EnviaCNPJsParaAnalise.
+type AnalysisRequest struct {
+	CNPJEmpresa    string
+	IDGrupoAnalise int
+	ListaCNPJCPF   []string
+	ListaDados     []DadosIntegracao
+	PostBack       *PostBack
+	Espera         WaitOptions  // Configuração da espera pela conclusão
+	BatchPolicy    *BatchPolicy // Substitui a BatchPolicy da sessão no envio de ListaCNPJCPF (opcional)
+}
+
+// ResultadoDocumento reúne o resultado de um documento analisado.
+type ResultadoDocumento struct {
+	Documento Documento
+	Resumo    *ResumoCNPJ      // Resumo do documento, ou nil se ausente da resposta da API
+	Logs      []LogAnalise     // Todos os logs das regras aplicadas, sem filtro
+	Dados     *DadosIntegracao // Dados enviados, no envio com dados
+}
+
+// LogsComErroOuAlerta retorna apenas os logs das regras com erro ou alerta.
+func (r *ResultadoDocumento) LogsComErroOuAlerta() []LogAnalise {
+	var logs []LogAnalise
+	for _, log := range r.Logs {
+		if log.Erro || log.Alerta {
+			logs = append(logs, log)
+		}
+	}
+	return logs
+}
+
+// AnalysisResult consolida o envio, o resumo da análise e o resultado de cada documento.
+type AnalysisResult struct {
+	AnaliseID  int
+	Envio      *EnviaCNPJsResponse
+	Status     *StatusAnalise
+	Resumo     *ResumoAnalise
+	Documentos map[Documento]*ResultadoDocumento
+	Ordem      []Documento // Documentos na ordem de envio, seguidos dos que vieram apenas na resposta
+}
+
+// Documento retorna o resultado de um documento, com ou sem máscara.
+func (r *AnalysisResult) Documento(documento string) (*ResultadoDocumento, bool) {
+	resultado, ok := r.Documentos[Documento(NormalizaDocumento(documento))]
+	return resultado, ok
+}
+
+// Resultados retorna os resultados dos documentos na ordem de Ordem.
+func (r *AnalysisResult) Resultados() []*ResultadoDocumento {
+	resultados := make([]*ResultadoDocumento, 0, len(r.Ordem))
+	for _, documento := range r.Ordem {
+		resultados = append(resultados, r.Documentos[documento])
+	}
+	return resultados
+}
+
+// Ausentes retorna os documentos enviados que não constam no resumo nem no resumo detalhado da API.
+func (r *AnalysisResult) Ausentes() []Documento {
+	var documentos []Documento
+	for _, documento := range r.Ordem {
+		if r.Documentos[documento].Resumo == nil {
+			documentos = append(documentos, documento)
+		}
+	}
+	return documentos
+}
+
+// resultado retorna o resultado do documento, criando-o se necessário.
+func (r *AnalysisResult) resultado(documento Documento) *ResultadoDocumento {
+	documento = Documento(NormalizaDocumento(string(documento)))
+	if resultado, ok := r.Documentos[documento]; ok {
+		return resultado
+	}
+	resultado := &ResultadoDocumento{Documento: documento}
+	r.Documentos[documento] = resultado
+	r.Ordem = append(r.Ordem, documento)
+	return resultado
+}
+
+// AnalysisError é retornado por Analyze quando uma etapa falha. O AnalysisResult parcial,
+// com o que foi obtido até a falha, é retornado junto com o erro.
+type AnalysisError struct {
+	Etapa     EtapaAnalise
+	AnaliseID int // Zero quando o envio falhou
+	Err       error
+}
+
+// Error descreve a etapa e o erro que interrompeu a análise.
+func (e *AnalysisError) Error() string {
+	if e.AnaliseID == 0 {
+		return fmt.Sprintf("%s: etapa %s: %v", ErrAnaliseIncompleta.Error(), e.Etapa, e.Err)
+	}
+	return fmt.Sprintf("%s: análise %d, etapa %s: %v", ErrAnaliseIncompleta.Error(), e.AnaliseID, e.Etapa, e.Err)
+}
+
+// Is permite comparar o erro com ErrAnaliseIncompleta via errors.Is.
+func (e *AnalysisError) Is(target error) bool {
+	return target == ErrAnaliseIncompleta
+}
+
+// Unwrap retorna o erro da etapa.
+func (e *AnalysisError) Unwrap() error {
+	return e.Err
+}
+
+// Analyze executa o ciclo completo de uma análise: envia os documentos, aguarda a conclusão
+// com WaitForAnalise e consulta o resumo da análise, o resumo de cada documento e os logs
+// completos das regras, consolidando tudo em um AnalysisResult indexado por documento.
+// Se alguma etapa falhar, o resultado parcial é retornado junto com um *AnalysisError.
+func (vc *VaduClient) Analyze(ctx context.Context, req AnalysisRequest, auth AuthenticationInterface) (*AnalysisResult, error) {
+	result := novoAnalysisResult()
+	falha := func(etapa EtapaAnalise, err error) (*AnalysisResult, error) {
+		vc.logger.WithFields(logrus.Fields{
+			"analiseID": result.AnaliseID,
+			"etapa":     etapa,
+		}).WithError(err).Error("Análise interrompida")
+		return result, &AnalysisError{Etapa: etapa, AnaliseID: result.AnaliseID, Err: err}
+	}
+
+	if err := vc.enviaAnalise(ctx, req, result, auth); err != nil {
+		return falha(EtapaEnvio, err)
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"analiseID":  result.AnaliseID,
+		"documentos": len(result.Ordem),
+	}).Info("Análise enviada, aguardando conclusão")
+
+	var err error
+	result.Status, err = vc.WaitForAnalise(ctx, result.AnaliseID, req.Espera, auth)
+	if err != nil {
+		return falha(EtapaEspera, err)
+	}
+
+	if etapa, err := vc.coletaResultado(ctx, result, auth); err != nil {
+		return falha(etapa, err)
+	}
+	return result, nil
+}
+
+// novoAnalysisResult cria um resultado vazio.
+func novoAnalysisResult() *AnalysisResult {
+	return &AnalysisResult{Documentos: make(map[Documento]*ResultadoDocumento)}
+}
+
+// enviaAnalise envia os documentos da requisição e registra no resultado o envio e os documentos enviados.
+func (vc *VaduClient) enviaAnalise(ctx context.Context, req AnalysisRequest, result *AnalysisResult, auth AuthenticationInterface) error {
+	var err error
+	if len(req.ListaDados) > 0 {
+		for i := range req.ListaDados {
+			result.resultado(req.ListaDados[i].CNPJCPF).Dados = &req.ListaDados[i]
+		}
+		result.Envio, err = vc.EnviaCNPJsComDadosParaAnalise(ctx, req.CNPJEmpresa, req.IDGrupoAnalise, req.ListaDados, req.PostBack, auth)
+	} else {
+		var documentos []Documento
+		result.Envio, documentos, err = vc.enviaCNPJs(ctx, vc.batchPolicy(req.BatchPolicy), req.CNPJEmpresa, req.IDGrupoAnalise, req.ListaCNPJCPF, req.PostBack, auth)
+		for _, documento := range documentos {
+			result.resultado(documento)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	result.AnaliseID = result.Envio.AnaliseID
+	return nil
+}
+
+// coletaResultado consulta o resumo da análise concluída, o resumo de cada documento e os
+// logs completos das regras. Em caso de falha, retorna a etapa que falhou.
+func (vc *VaduClient) coletaResultado(ctx context.Context, result *AnalysisResult, auth AuthenticationInterface) (EtapaAnalise, error) {
+	// Resumo da análise
+	var err error
+	result.Resumo, err = vc.PegaResumoAnalise(ctx, result.AnaliseID, auth)
+	if err != nil {
+		return EtapaResumo, err
+	}
+
+	// Resumo de cada documento
+	resumos, err := vc.ListaResumoCNPJs(ctx, result.AnaliseID, auth)
+	if err != nil {
+		return EtapaResumoDocumentos, err
+	}
+	for i := range resumos {
+		result.resultado(resumos[i].CNPJCPF).Resumo = &resumos[i]
+	}
+
+	// Logs completos das regras de cada documento
+	detalhados, err := vc.buscaResumoCNPJsDetalhado(ctx, result.AnaliseID, auth)
+	if err != nil {
+		return EtapaLogs, err
+	}
+	for _, detalhado := range detalhados {
+		resultado := result.resultado(detalhado.CNPJCPF)
+		resultado.Logs = append(resultado.Logs, detalhado.Logs...)
+		if resultado.Resumo == nil {
+			// Documento ausente do resumo simples: usa os campos do resumo detalhado
+			resultado.Resumo = &ResumoCNPJ{ResumoCNPJBase: detalhado.ResumoCNPJBase}
+		}
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"analiseID":  result.AnaliseID,
+		"documentos": len(result.Documentos),
+		"ausentes":   len(result.Ausentes()),
+	}).Info("Análise concluída e consolidada")
+	return "", nil
+}
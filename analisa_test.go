@@ -0,0 +1,171 @@
+package vadu_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnalisaTestSuite struct {
+	suite.Suite
+	assert         *assert.Assertions
+	ctx            context.Context
+	logger         *logrus.Logger
+	authentication *mock.MockAuthentication
+}
+
+func TestAnalisaTestSuite(t *testing.T) {
+	suite.Run(t, new(AnalisaTestSuite))
+}
+
+func (s *AnalisaTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+	s.authentication = new(mock.MockAuthentication)
+	s.authentication.On("Token", s.ctx).Return("mocked_token", nil)
+}
+
+// servidorAnalise responde cada rota da API de análise com o corpo configurado,
+// ou com erro quando a rota não está configurada.
+func servidorAnalise(rotas map[string]string) *http.Client {
+	return &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			var rota string
+			switch path := req.URL.Path; {
+			case req.Method == http.MethodPost:
+				rota = "envio"
+			case strings.Contains(path, "/status/"):
+				rota = "status"
+			case strings.HasSuffix(path, "/cnpjcpf/detalhado"):
+				rota = "detalhado"
+			case strings.HasSuffix(path, "/cnpjcpf"):
+				rota = "documentos"
+			default:
+				rota = "resumo"
+			}
+			corpo, ok := rotas[rota]
+			if !ok {
+				return &http.Response{StatusCode: http.StatusInternalServerError, Body: ioutil.NopCloser(strings.NewReader("erro"))}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(corpo))}, nil
+		},
+	}}
+}
+
+var rotasAnalise = map[string]string{
+	"envio":  `{"analise_id": 4768906, "quantidade_cnpj": 2, "quantidade_cpf": 1, "id_grupo_analise": 10802}`,
+	"status": `{"quantidade_cnpj_cpf": 3, "percentual_concluido": 100, "percentual_consultas_receita": 100, "concluido": true}`,
+	"resumo": `{"analise_id": 4768906, "quantidade_cnpj": 2, "quantidade_cpf": 1, "concluido": true, "alerta": true, "rating_valor": 500, "rating_sigla": "B (500)"}`,
+	"documentos": `[
+		{"analise_id": 4768906, "cnpj_cpf": "98960887000164", "nome": "WEBSOLUTIONS LTDA", "alerta": true, "rating": 500},
+		{"analise_id": 4768906, "cnpj_cpf": "52998224725", "nome": "FULANO", "rating": 700}
+	]`,
+	"detalhado": `[
+		{"analise_id": 4768906, "cnpj_cpf": "98960887000164", "nome": "WEBSOLUTIONS LTDA", "alerta": true, "logs": [
+			{"regra_descricao": "Cadastro 13", "erro": false, "alerta": false},
+			{"regra_descricao": "Cadastro 14", "erro": false, "alerta": true}
+		]},
+		{"analise_id": 4768906, "cnpj_cpf": "00360305000104", "nome": "SOMENTE DETALHADO", "logs": [
+			{"regra_descricao": "Cadastro 13", "erro": false, "alerta": false}
+		]}
+	]`,
+}
+
+// TestAnalyze verifica o ciclo completo e a consolidação dos resultados por documento
+func (s *AnalisaTestSuite) TestAnalyze() {
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidorAnalise(rotasAnalise), *session, s.logger)
+
+	result, err := vaduClient.Analyze(s.ctx, vadu.AnalysisRequest{
+		CNPJEmpresa:    "33.011.770/0001-99",
+		IDGrupoAnalise: 10802,
+		ListaCNPJCPF:   []string{"98.960.887/0001-64", "529.982.247-25", "33011770000199"},
+	}, s.authentication)
+	s.assert.NoError(err)
+	s.assert.Equal(4768906, result.AnaliseID)
+	s.assert.True(result.Status.Concluido)
+	s.assert.Equal("B (500)", result.Resumo.RatingSigla)
+	s.assert.Equal([]vadu.Documento{"98960887000164", "52998224725", "33011770000199", "00360305000104"}, result.Ordem)
+
+	// Os logs são completos, sem o filtro de ListaResumoCNPJsDetalhado
+	empresa, ok := result.Documento("98.960.887/0001-64")
+	s.assert.True(ok)
+	s.assert.Equal("WEBSOLUTIONS LTDA", empresa.Resumo.Nome)
+	s.assert.Len(empresa.Logs, 2)
+	s.assert.Len(empresa.LogsComErroOuAlerta(), 1)
+
+	pessoa, _ := result.Documento("52998224725")
+	s.assert.Equal(700, pessoa.Resumo.Rating)
+	s.assert.Empty(pessoa.Logs)
+
+	// Documento presente apenas no resumo detalhado
+	detalhado, _ := result.Documento("00360305000104")
+	s.assert.Equal("SOMENTE DETALHADO", detalhado.Resumo.Nome)
+
+	s.assert.Equal([]vadu.Documento{"33011770000199"}, result.Ausentes())
+	s.assert.Len(result.Resultados(), 4)
+}
+
+// TestAnalyzeComDados verifica o ciclo com o envio de DadosIntegracao
+func (s *AnalisaTestSuite) TestAnalyzeComDados() {
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidorAnalise(rotasAnalise), *session, s.logger)
+
+	result, err := vaduClient.Analyze(s.ctx, vadu.AnalysisRequest{
+		CNPJEmpresa:    "33011770000199",
+		IDGrupoAnalise: 10802,
+		ListaDados: []vadu.DadosIntegracao{
+			{CNPJCPF: "98960887000164", AtivoTotal: vadu.MustDecimal("1500000.00")},
+		},
+	}, s.authentication)
+	s.assert.NoError(err)
+	empresa, ok := result.Documento("98960887000164")
+	s.assert.True(ok)
+	s.assert.Equal(vadu.MustDecimal("1500000"), empresa.Dados.AtivoTotal)
+	s.assert.Len(empresa.Logs, 2)
+}
+
+// TestAnalyzeFalha verifica o resultado parcial e a etapa informada quando uma consulta falha
+func (s *AnalisaTestSuite) TestAnalyzeFalha() {
+	rotas := map[string]string{}
+	for rota, corpo := range rotasAnalise {
+		if rota != "detalhado" {
+			rotas[rota] = corpo
+		}
+	}
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidorAnalise(rotas), *session, s.logger)
+
+	result, err := vaduClient.Analyze(s.ctx, vadu.AnalysisRequest{
+		CNPJEmpresa:    "33011770000199",
+		IDGrupoAnalise: 10802,
+		ListaCNPJCPF:   []string{"98960887000164"},
+	}, s.authentication)
+	s.assert.ErrorIs(err, vadu.ErrAnaliseIncompleta)
+	var analysisErr *vadu.AnalysisError
+	s.assert.ErrorAs(err, &analysisErr)
+	s.assert.Equal(vadu.EtapaLogs, analysisErr.Etapa)
+	s.assert.Equal(4768906, analysisErr.AnaliseID)
+	s.assert.NotNil(result.Resumo)
+	empresa, _ := result.Documento("98960887000164")
+	s.assert.NotNil(empresa.Resumo)
+
+	// Falha no envio: nenhum ID de análise
+	_, err = vaduClient.Analyze(s.ctx, vadu.AnalysisRequest{CNPJEmpresa: "", IDGrupoAnalise: 10802, ListaCNPJCPF: []string{"98960887000164"}, BatchPolicy: policy(vadu.BatchPolicyFail)}, s.authentication)
+	s.assert.ErrorAs(err, &analysisErr)
+	s.assert.Equal(vadu.EtapaEnvio, analysisErr.Etapa)
+	s.assert.ErrorIs(err, vadu.ErrBatchInvalido)
+}
@@ -0,0 +1,539 @@
+package vadu
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AuditEvento identifica o tipo de registro gravado na trilha de auditoria.
+type AuditEvento string
+
+const (
+	// AuditEventoSubmissao registra o envio de documentos para análise.
+	AuditEventoSubmissao AuditEvento = "submissao"
+	// AuditEventoResumoAnalise registra o resumo retornado para uma análise.
+	AuditEventoResumoAnalise AuditEvento = "resumo_analise"
+	// AuditEventoResultadoDocumentos registra os resultados dos documentos retornados por
+	// uma consulta da análise, em um único registro por consulta.
+	AuditEventoResultadoDocumentos AuditEvento = "resultado_documentos"
+)
+
+// ErrAuditChainBroken indica que a cadeia de hashes da trilha de auditoria foi violada.
+var ErrAuditChainBroken = errors.New("trilha de auditoria violada")
+
+// AuditRecord representa um registro imutável da trilha de auditoria.
+// Cada registro carrega o hash do registro anterior, formando uma cadeia
+// que permite detectar alterações, remoções ou inserções fora de ordem.
+type AuditRecord struct {
+	Sequencia      uint64          `json:"sequencia"`
+	DataHora       time.Time       `json:"data_hora"`
+	Evento         AuditEvento     `json:"evento"`
+	AnaliseID      int             `json:"analise_id,omitempty"`
+	CNPJEmpresa    Documento       `json:"cnpj_empresa,omitempty"`
+	IDGrupoAnalise int             `json:"id_grupo_analise,omitempty"`
+	Usuario        string          `json:"usuario,omitempty"`
+	Documentos     []Documento     `json:"documentos,omitempty"`
+	Dados          json.RawMessage `json:"dados,omitempty"`
+	HashAnterior   string          `json:"hash_anterior"`
+	Hash           string          `json:"hash"`
+}
+
+// calculaHash calcula o hash SHA-256 do registro, desconsiderando o próprio campo Hash.
+func (r AuditRecord) calculaHash() (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditSink define o destino onde os registros de auditoria são persistidos.
+// Implementações devem apenas acrescentar registros, nunca alterá-los.
+type AuditSink interface {
+	Append(ctx context.Context, record AuditRecord) error
+	Last(ctx context.Context) (*AuditRecord, error)
+	Records(ctx context.Context) ([]AuditRecord, error)
+}
+
+// tentativasAudit limita as gravações de um registro quando outra trilha ocupa a mesma sequência.
+const tentativasAudit = 3
+
+// AuditTrail encadeia e grava os registros de auditoria em um AuditSink.
+//
+// O último registro é lido do sink a cada gravação, portanto várias trilhas podem gravar no
+// mesmo sink. Gravações simultâneas de processos diferentes só são seguras quando o sink
+// rejeita uma sequência repetida, como o SQLAuditSink com a chave primária em sequencia: a
+// trilha relê o último registro e grava novamente. O FileAuditSink não detecta essa
+// concorrência e deve ter um único processo gravador.
+type AuditTrail struct {
+	sink  AuditSink
+	mu    sync.Mutex
+	agora func() time.Time
+}
+
+// NewAuditTrail cria uma nova trilha de auditoria gravando no sink informado.
+func NewAuditTrail(sink AuditSink) *AuditTrail {
+	if sink == nil {
+		panic("AuditSink não pode ser nulo")
+	}
+	return &AuditTrail{
+		sink:  sink,
+		agora: time.Now,
+	}
+}
+
+// Registra completa a sequência, a data/hora e os hashes do registro e o grava no sink.
+func (t *AuditTrail) Registra(ctx context.Context, record AuditRecord) (*AuditRecord, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Normaliza os documentos para que o hash independa da máscara informada.
+	record.CNPJEmpresa = Documento(NormalizaDocumento(string(record.CNPJEmpresa)))
+	documentos := make([]Documento, 0, len(record.Documentos))
+	for _, documento := range record.Documentos {
+		documentos = append(documentos, Documento(NormalizaDocumento(string(documento))))
+	}
+	if len(documentos) > 0 {
+		record.Documentos = documentos
+	}
+
+	for tentativa := 1; ; tentativa++ {
+		ultimo, err := t.sink.Last(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler último registro de auditoria: %w", err)
+		}
+		record.Sequencia = 1
+		record.HashAnterior = ""
+		if ultimo != nil {
+			record.Sequencia = ultimo.Sequencia + 1
+			record.HashAnterior = ultimo.Hash
+		}
+		record.DataHora = t.agora().UTC()
+
+		hash, err := record.calculaHash()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao calcular hash do registro de auditoria: %w", err)
+		}
+		record.Hash = hash
+
+		err = t.sink.Append(ctx, record)
+		if err == nil {
+			return &record, nil
+		}
+
+		// Outra trilha pode ter gravado a mesma sequência; nesse caso a gravação é repetida
+		atual, errLast := t.sink.Last(ctx)
+		if tentativa >= tentativasAudit || errLast != nil || atual == nil || atual.Sequencia < record.Sequencia {
+			return nil, fmt.Errorf("erro ao gravar registro de auditoria: %w", err)
+		}
+	}
+}
+
+// AuditHead identifica o último registro da trilha de auditoria. A cadeia de hashes não
+// detecta registros removidos do final da trilha; para isso, guarde o AuditHead fora do
+// sink (ex.: em outro sistema ou em um log externo) e confira-o com VerifyAuditTrailHead.
+type AuditHead struct {
+	Registros int    `json:"registros"`
+	Hash      string `json:"hash"`
+}
+
+// Head retorna o AuditHead do último registro do sink.
+func (t *AuditTrail) Head(ctx context.Context) (AuditHead, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ultimo, err := t.sink.Last(ctx)
+	if err != nil {
+		return AuditHead{}, fmt.Errorf("erro ao ler último registro de auditoria: %w", err)
+	}
+	if ultimo == nil {
+		return AuditHead{}, nil
+	}
+	return AuditHead{Registros: int(ultimo.Sequencia), Hash: ultimo.Hash}, nil
+}
+
+// VerifyAuditTrail percorre todos os registros do sink validando a sequência e a
+// cadeia de hashes. Retorna a quantidade de registros verificados.
+// Registros removidos do final da trilha não são detectados; use VerifyAuditTrailHead.
+func VerifyAuditTrail(ctx context.Context, sink AuditSink) (int, error) {
+	head, err := VerifyAuditTrailHead(ctx, sink, AuditHead{})
+	return head.Registros, err
+}
+
+// VerifyAuditTrailHead valida a cadeia como VerifyAuditTrail e confere que a trilha ainda
+// contém o registro identificado por esperado, obtido anteriormente com AuditTrail.Head.
+// Uma trilha com menos registros que esperado ou com outro hash nessa posição foi truncada
+// ou substituída. Com esperado vazio, apenas a cadeia é validada. Retorna o AuditHead atual
+// da trilha ou, em caso de violação da cadeia, o do último registro íntegro.
+func VerifyAuditTrailHead(ctx context.Context, sink AuditSink, esperado AuditHead) (AuditHead, error) {
+	records, err := sink.Records(ctx)
+	if err != nil {
+		return AuditHead{}, fmt.Errorf("erro ao ler registros de auditoria: %w", err)
+	}
+	head, err := verificaRegistros(records)
+	if err != nil {
+		return head, err
+	}
+	if esperado.Registros == 0 {
+		return head, nil
+	}
+	if len(records) < esperado.Registros {
+		return head, fmt.Errorf("%w: %d registros encontrados, %d esperados", ErrAuditChainBroken, len(records), esperado.Registros)
+	}
+	if records[esperado.Registros-1].Hash != esperado.Hash {
+		return head, fmt.Errorf("%w: hash divergente do esperado na sequência %d", ErrAuditChainBroken, esperado.Registros)
+	}
+	return head, nil
+}
+
+// verificaRegistros valida a sequência e a cadeia de hashes. Em caso de falha, o AuditHead
+// retornado identifica o último registro íntegro.
+func verificaRegistros(records []AuditRecord) (AuditHead, error) {
+	var head AuditHead
+	for _, record := range records {
+		if record.Sequencia != uint64(head.Registros+1) {
+			return head, fmt.Errorf("%w: sequência %d encontrada na posição %d", ErrAuditChainBroken, record.Sequencia, head.Registros+1)
+		}
+		if record.HashAnterior != head.Hash {
+			return head, fmt.Errorf("%w: hash anterior divergente na sequência %d", ErrAuditChainBroken, record.Sequencia)
+		}
+		hash, err := record.calculaHash()
+		if err != nil {
+			return head, fmt.Errorf("erro ao calcular hash da sequência %d: %w", record.Sequencia, err)
+		}
+		if hash != record.Hash {
+			return head, fmt.Errorf("%w: conteúdo alterado na sequência %d", ErrAuditChainBroken, record.Sequencia)
+		}
+		head = AuditHead{Registros: head.Registros + 1, Hash: record.Hash}
+	}
+	return head, nil
+}
+
+// MemoryAuditSink mantém os registros de auditoria em memória.
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+// NewMemoryAuditSink cria um sink de auditoria em memória.
+func NewMemoryAuditSink() *MemoryAuditSink {
+	return &MemoryAuditSink{}
+}
+
+// Append acrescenta um registro ao final da lista.
+func (m *MemoryAuditSink) Append(ctx context.Context, record AuditRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, record)
+	return nil
+}
+
+// Last retorna o último registro gravado ou nil se não houver registros.
+func (m *MemoryAuditSink) Last(ctx context.Context) (*AuditRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.records) == 0 {
+		return nil, nil
+	}
+	record := m.records[len(m.records)-1]
+	return &record, nil
+}
+
+// Records retorna uma cópia de todos os registros gravados.
+func (m *MemoryAuditSink) Records(ctx context.Context) ([]AuditRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make([]AuditRecord, len(m.records))
+	copy(records, m.records)
+	return records, nil
+}
+
+// FileAuditSink grava os registros de auditoria em um arquivo JSON Lines, um registro por linha.
+type FileAuditSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileAuditSink cria um sink de auditoria que acrescenta registros ao arquivo informado.
+func NewFileAuditSink(path string) *FileAuditSink {
+	return &FileAuditSink{path: path}
+}
+
+// Append acrescenta o registro ao final do arquivo e força a gravação em disco.
+func (f *FileAuditSink) Append(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Last retorna o último registro do arquivo ou nil se o arquivo não existir ou estiver vazio.
+// Apenas o final do arquivo é lido.
+func (f *FileAuditSink) Last(ctx context.Context) (*AuditRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	linha, err := ultimaLinha(file, info.Size())
+	if err != nil || len(linha) == 0 {
+		return nil, err
+	}
+	var record AuditRecord
+	if err := json.Unmarshal(linha, &record); err != nil {
+		return nil, fmt.Errorf("último registro inválido: %w", err)
+	}
+	return &record, nil
+}
+
+// ultimaLinha lê o arquivo em blocos, do final para o início, até encontrar a última linha não vazia.
+func ultimaLinha(file *os.File, tamanho int64) ([]byte, error) {
+	const bloco = 4096
+	var final []byte
+	for fim := tamanho; fim > 0; {
+		inicio := fim - bloco
+		if inicio < 0 {
+			inicio = 0
+		}
+		buf := make([]byte, fim-inicio)
+		if _, err := file.ReadAt(buf, inicio); err != nil {
+			return nil, err
+		}
+		final = append(buf, final...)
+		fim = inicio
+
+		texto := bytes.TrimRight(final, "\n")
+		if i := bytes.LastIndexByte(texto, '\n'); i >= 0 {
+			return texto[i+1:], nil
+		}
+	}
+	return bytes.TrimRight(final, "\n"), nil
+}
+
+// Records lê todos os registros do arquivo na ordem em que foram gravados.
+func (f *FileAuditSink) Records(ctx context.Context) ([]AuditRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for linha := 1; scanner.Scan(); linha++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("registro inválido na linha %d: %w", linha, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// SQLAuditSink grava os registros de auditoria em uma tabela acessada via database/sql.
+// A tabela pode ser criada com CreateTable.
+type SQLAuditSink struct {
+	db    *sql.DB
+	table string
+
+	// Placeholder gera o marcador do n-ésimo parâmetro (iniciando em 1).
+	// O padrão é "?"; para PostgreSQL utilize, por exemplo, func(n int) string { return fmt.Sprintf("$%d", n) }.
+	Placeholder func(n int) string
+}
+
+// NewSQLAuditSink cria um sink de auditoria que grava na tabela informada.
+func NewSQLAuditSink(db *sql.DB, table string) *SQLAuditSink {
+	if db == nil {
+		panic("sql.DB não pode ser nulo")
+	}
+	return &SQLAuditSink{
+		db:          db,
+		table:       table,
+		Placeholder: func(int) string { return "?" },
+	}
+}
+
+// CreateTable cria a tabela de auditoria caso ela ainda não exista.
+func (s *SQLAuditSink) CreateTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		sequencia BIGINT PRIMARY KEY,
+		data_hora VARCHAR(64) NOT NULL,
+		evento VARCHAR(64) NOT NULL,
+		analise_id BIGINT,
+		registro TEXT NOT NULL,
+		hash_anterior VARCHAR(64) NOT NULL,
+		hash VARCHAR(64) NOT NULL
+	)`, s.table)
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
+
+// Append insere o registro na tabela. A chave primária em sequencia impede
+// que dois registros ocupem a mesma posição da cadeia.
+func (s *SQLAuditSink) Append(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (sequencia, data_hora, evento, analise_id, registro, hash_anterior, hash) VALUES (%s, %s, %s, %s, %s, %s, %s)",
+		s.table, s.Placeholder(1), s.Placeholder(2), s.Placeholder(3), s.Placeholder(4), s.Placeholder(5), s.Placeholder(6), s.Placeholder(7))
+	_, err = s.db.ExecContext(ctx, query,
+		int64(record.Sequencia),
+		record.DataHora.Format(time.RFC3339Nano),
+		string(record.Evento),
+		record.AnaliseID,
+		string(data),
+		record.HashAnterior,
+		record.Hash,
+	)
+	return err
+}
+
+// Last retorna o registro de maior sequência ou nil se a tabela estiver vazia.
+func (s *SQLAuditSink) Last(ctx context.Context) (*AuditRecord, error) {
+	query := fmt.Sprintf("SELECT registro FROM %s ORDER BY sequencia DESC LIMIT 1", s.table)
+	var data string
+	err := s.db.QueryRowContext(ctx, query).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Records retorna todos os registros ordenados pela sequência.
+func (s *SQLAuditSink) Records(ctx context.Context) ([]AuditRecord, error) {
+	query := fmt.Sprintf("SELECT registro FROM %s ORDER BY sequencia", s.table)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []AuditRecord
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var record AuditRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// audita grava um registro na trilha de auditoria da sessão, quando configurada.
+// Falhas de auditoria são logadas, mas não interrompem a operação já concluída na API.
+func (vc *VaduClient) audita(ctx context.Context, record AuditRecord, dados interface{}) {
+	if vc.session.AuditTrail == nil {
+		return
+	}
+
+	if dados != nil {
+		data, err := json.Marshal(dados)
+		if err != nil {
+			vc.logger.WithError(err).Error("Erro ao serializar dados de auditoria")
+			return
+		}
+		record.Dados = data
+	}
+
+	gravado, err := vc.session.AuditTrail.Registra(ctx, record)
+	if err != nil {
+		vc.logger.WithFields(logrus.Fields{
+			"evento":    record.Evento,
+			"analiseID": record.AnaliseID,
+			"error":     err,
+		}).Error("Erro ao registrar trilha de auditoria")
+		return
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"evento":    gravado.Evento,
+		"analiseID": gravado.AnaliseID,
+		"sequencia": gravado.Sequencia,
+	}).Debug("Registro de auditoria gravado")
+}
+
+// auditaSubmissao registra o envio de documentos para análise e a resposta recebida.
+func (vc *VaduClient) auditaSubmissao(ctx context.Context, requisicao interface{}, cnpjEmpresa Documento, idGrupoAnalise int, documentos []Documento, resposta *EnviaCNPJsResponse) {
+	vc.audita(ctx, AuditRecord{
+		Evento:         AuditEventoSubmissao,
+		AnaliseID:      resposta.AnaliseID,
+		CNPJEmpresa:    cnpjEmpresa,
+		IDGrupoAnalise: idGrupoAnalise,
+		Usuario:        resposta.Usuario,
+		Documentos:     documentos,
+	}, map[string]interface{}{
+		"requisicao": requisicao,
+		"resposta":   resposta,
+	})
+}
+
+// auditaResultados registra, em um único registro, os resultados dos documentos retornados
+// por uma consulta da análise.
+func (vc *VaduClient) auditaResultados(ctx context.Context, analiseID int, documentos []Documento, resultados interface{}) {
+	vc.audita(ctx, AuditRecord{
+		Evento:     AuditEventoResultadoDocumentos,
+		AnaliseID:  analiseID,
+		Documentos: documentos,
+	}, resultados)
+}
@@ -0,0 +1,198 @@
+package vadu_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	ctx    context.Context
+	logger *logrus.Logger
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+}
+
+// TestCadeiaDeHashes verifica o encadeamento dos registros gravados
+func (s *AuditTestSuite) TestCadeiaDeHashes() {
+	sink := vadu.NewMemoryAuditSink()
+	trail := vadu.NewAuditTrail(sink)
+
+	primeiro, err := trail.Registra(s.ctx, vadu.AuditRecord{Evento: vadu.AuditEventoSubmissao, AnaliseID: 1})
+	s.assert.NoError(err)
+	segundo, err := trail.Registra(s.ctx, vadu.AuditRecord{Evento: vadu.AuditEventoResumoAnalise, AnaliseID: 1})
+	s.assert.NoError(err)
+
+	s.assert.Equal(uint64(1), primeiro.Sequencia)
+	s.assert.Empty(primeiro.HashAnterior)
+	s.assert.Equal(uint64(2), segundo.Sequencia)
+	s.assert.Equal(primeiro.Hash, segundo.HashAnterior)
+
+	verificados, err := vadu.VerifyAuditTrail(s.ctx, sink)
+	s.assert.NoError(err)
+	s.assert.Equal(2, verificados)
+}
+
+// TestArquivoAdulterado verifica que alterações no arquivo são detectadas
+func (s *AuditTestSuite) TestArquivoAdulterado() {
+	path := filepath.Join(s.T().TempDir(), "auditoria.jsonl")
+	trail := vadu.NewAuditTrail(vadu.NewFileAuditSink(path))
+
+	for i := 1; i <= 3; i++ {
+		_, err := trail.Registra(s.ctx, vadu.AuditRecord{
+			Evento:     vadu.AuditEventoResultadoDocumentos,
+			AnaliseID:  4768906,
+			Documentos: []vadu.Documento{"98960887000164"},
+		})
+		s.assert.NoError(err)
+	}
+
+	// Uma nova trilha sobre o mesmo arquivo continua a cadeia existente
+	continuacao := vadu.NewAuditTrail(vadu.NewFileAuditSink(path))
+	record, err := continuacao.Registra(s.ctx, vadu.AuditRecord{Evento: vadu.AuditEventoResumoAnalise})
+	s.assert.NoError(err)
+	s.assert.Equal(uint64(4), record.Sequencia)
+
+	verificados, err := vadu.VerifyAuditTrail(s.ctx, vadu.NewFileAuditSink(path))
+	s.assert.NoError(err)
+	s.assert.Equal(4, verificados)
+
+	// Adulterar o documento do segundo registro
+	data, err := os.ReadFile(path)
+	s.assert.NoError(err)
+	linhas := strings.Split(string(data), "\n")
+	linhas[1] = strings.Replace(linhas[1], "98960887000164", "33011770000199", 1)
+	s.assert.NoError(os.WriteFile(path, []byte(strings.Join(linhas, "\n")), 0o600))
+
+	verificados, err = vadu.VerifyAuditTrail(s.ctx, vadu.NewFileAuditSink(path))
+	s.assert.ErrorIs(err, vadu.ErrAuditChainBroken)
+	s.assert.Equal(1, verificados)
+}
+
+// TestTrilhasCompartilhadas verifica que trilhas diferentes sobre o mesmo arquivo mantêm uma única cadeia
+func (s *AuditTestSuite) TestTrilhasCompartilhadas() {
+	path := filepath.Join(s.T().TempDir(), "auditoria.jsonl")
+	trilhas := []*vadu.AuditTrail{
+		vadu.NewAuditTrail(vadu.NewFileAuditSink(path)),
+		vadu.NewAuditTrail(vadu.NewFileAuditSink(path)),
+	}
+
+	for i := 0; i < 4; i++ {
+		record, err := trilhas[i%2].Registra(s.ctx, vadu.AuditRecord{Evento: vadu.AuditEventoSubmissao, AnaliseID: i})
+		s.assert.NoError(err)
+		s.assert.Equal(uint64(i+1), record.Sequencia)
+	}
+
+	verificados, err := vadu.VerifyAuditTrail(s.ctx, vadu.NewFileAuditSink(path))
+	s.assert.NoError(err)
+	s.assert.Equal(4, verificados)
+}
+
+// TestArquivoTruncado verifica que registros removidos do final são detectados pelo AuditHead
+func (s *AuditTestSuite) TestArquivoTruncado() {
+	path := filepath.Join(s.T().TempDir(), "auditoria.jsonl")
+	trail := vadu.NewAuditTrail(vadu.NewFileAuditSink(path))
+
+	head, err := trail.Head(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.AuditHead{}, head)
+
+	for i := 1; i <= 3; i++ {
+		_, err := trail.Registra(s.ctx, vadu.AuditRecord{Evento: vadu.AuditEventoSubmissao, AnaliseID: i})
+		s.assert.NoError(err)
+	}
+	head, err = trail.Head(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Equal(3, head.Registros)
+
+	// O head de uma nova trilha vem do sink
+	reaberta, err := vadu.NewAuditTrail(vadu.NewFileAuditSink(path)).Head(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Equal(head, reaberta)
+
+	atual, err := vadu.VerifyAuditTrailHead(s.ctx, vadu.NewFileAuditSink(path), head)
+	s.assert.NoError(err)
+	s.assert.Equal(head, atual)
+
+	// Remover o último registro mantém a cadeia válida, mas não o head esperado
+	data, err := os.ReadFile(path)
+	s.assert.NoError(err)
+	linhas := strings.SplitAfter(string(data), "\n")
+	s.assert.NoError(os.WriteFile(path, []byte(strings.Join(linhas[:2], "")), 0o600))
+
+	verificados, err := vadu.VerifyAuditTrail(s.ctx, vadu.NewFileAuditSink(path))
+	s.assert.NoError(err)
+	s.assert.Equal(2, verificados)
+
+	atual, err = vadu.VerifyAuditTrailHead(s.ctx, vadu.NewFileAuditSink(path), head)
+	s.assert.ErrorIs(err, vadu.ErrAuditChainBroken)
+	s.assert.Equal(2, atual.Registros)
+}
+
+// TestClienteAuditaSubmissao verifica que o VaduClient alimenta a trilha automaticamente
+func (s *AuditTestSuite) TestClienteAuditaSubmissao() {
+	sink := vadu.NewMemoryAuditSink()
+	session, err := vadu.NewSession(vadu.Config{
+		ClientToken: vadu.String("mock-token"),
+		Cookie:      vadu.String("mock-cookie-value"),
+		AuditTrail:  vadu.NewAuditTrail(sink),
+	})
+	s.assert.NoError(err)
+
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", s.ctx).Return("mocked_token", nil)
+
+	vaduClient := vadu.NewVaduClient(mock.EnviaCNPJsParaAnaliseMock(), *session, s.logger)
+	_, err = vaduClient.EnviaCNPJsParaAnalise(s.ctx, "33011770000199", 10802, []string{"98960887000164"}, nil, authentication)
+	s.assert.NoError(err)
+
+	vaduClient = vadu.NewVaduClient(mock.ListaResumoCNPJsMock(), *session, s.logger)
+	_, err = vaduClient.ListaResumoCNPJs(s.ctx, 4768906, authentication)
+	s.assert.NoError(err)
+
+	records, err := sink.Records(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Len(records, 2)
+
+	submissao := records[0]
+	s.assert.Equal(vadu.AuditEventoSubmissao, submissao.Evento)
+	s.assert.Equal(4768906, submissao.AnaliseID)
+	s.assert.Equal(10802, submissao.IDGrupoAnalise)
+	s.assert.Equal(vadu.Documento("33011770000199"), submissao.CNPJEmpresa)
+	s.assert.Equal("Contbank - Usuário p/Integração Não excluir", submissao.Usuario)
+	s.assert.Equal([]vadu.Documento{"98960887000164"}, submissao.Documentos)
+
+	var dados map[string]json.RawMessage
+	s.assert.NoError(json.Unmarshal(submissao.Dados, &dados))
+	s.assert.Contains(dados, "requisicao")
+	s.assert.Contains(dados, "resposta")
+
+	s.assert.Equal(vadu.AuditEventoResultadoDocumentos, records[1].Evento)
+	s.assert.Equal(submissao.Hash, records[1].HashAnterior)
+	s.assert.Equal(4768906, records[1].AnaliseID)
+	s.assert.Equal([]vadu.Documento{"98960887000164"}, records[1].Documentos)
+
+	_, err = vadu.VerifyAuditTrail(s.ctx, sink)
+	s.assert.NoError(err)
+}
@@ -0,0 +1,361 @@
+package vadu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ConcorrenciaLotePadrao é a quantidade padrão de partes enviadas em paralelo.
+	ConcorrenciaLotePadrao = 4
+	// TamanhoPayloadPadrao é o tamanho máximo padrão, em bytes, do corpo de cada parte enviada com dados.
+	TamanhoPayloadPadrao = 1 << 20
+)
+
+// ErrLoteParcial indica que uma ou mais partes de um lote não foram enviadas.
+var ErrLoteParcial = errors.New("lote enviado parcialmente")
+
+// BatchOptions configura a divisão e o envio de um lote em partes.
+type BatchOptions struct {
+	TamanhoParte int          // Itens por parte (padrão e máximo LimiteDocumentosPorRequisicao, ou LimiteDadosPorRequisicao no envio com dados)
+	MaximoBytes  int          // Tamanho máximo do corpo de cada parte no envio com dados (padrão TamanhoPayloadPadrao)
+	Concorrencia int          // Partes enviadas em paralelo (padrão ConcorrenciaLotePadrao)
+	BatchPolicy  *BatchPolicy // Substitui a BatchPolicy da sessão na validação do lote (opcional)
+}
+
+// normaliza aplica os valores padrão e o limite de itens por requisição da API.
+func (o BatchOptions) normaliza(limite int) BatchOptions {
+	if o.TamanhoParte <= 0 || o.TamanhoParte > limite {
+		o.TamanhoParte = limite
+	}
+	if o.MaximoBytes <= 0 {
+		o.MaximoBytes = TamanhoPayloadPadrao
+	}
+	if o.Concorrencia <= 0 {
+		o.Concorrencia = ConcorrenciaLotePadrao
+	}
+	return o
+}
+
+// BatchChunk é uma parte de um lote, enviada em uma única requisição.
+type BatchChunk struct {
+	Indice     int                 // Posição da parte no lote
+	Documentos []Documento         // Documentos enviados na parte
+	Dados      []DadosIntegracao   // Dados enviados na parte, no envio com EnviaCNPJsComDadosParaAnalise
+	Bytes      int                 // Tamanho estimado do corpo da requisição, no envio com dados
+	Resposta   *EnviaCNPJsResponse // Resposta da API, quando enviada com sucesso
+	Err        error               // Erro do último envio, ou nil
+}
+
+// Enviada informa se a parte foi aceita pela API.
+func (c BatchChunk) Enviada() bool {
+	return c.Resposta != nil && c.Err == nil
+}
+
+// BatchSubmission agrega o envio de um lote dividido em partes. Partes com erro podem ser
+// reenviadas com ResumeBatch sem repetir as que já foram aceitas.
+type BatchSubmission struct {
+	CNPJEmpresa    Documento
+	IDGrupoAnalise int
+	PostBack       *PostBack
+	Report         *BatchValidationReport // Validação do lote completo (nil no envio com dados)
+	Partes         []BatchChunk
+	Opcoes         BatchOptions
+}
+
+// AnaliseIDs retorna os IDs das análises criadas, na ordem das partes.
+func (b *BatchSubmission) AnaliseIDs() []int {
+	ids := make([]int, 0, len(b.Partes))
+	for _, parte := range b.Partes {
+		if parte.Enviada() {
+			ids = append(ids, parte.Resposta.AnaliseID)
+		}
+	}
+	return ids
+}
+
+// Falhas retorna as partes que não foram enviadas.
+func (b *BatchSubmission) Falhas() []BatchChunk {
+	var falhas []BatchChunk
+	for _, parte := range b.Partes {
+		if !parte.Enviada() {
+			falhas = append(falhas, parte)
+		}
+	}
+	return falhas
+}
+
+// Concluida informa se todas as partes foram enviadas.
+func (b *BatchSubmission) Concluida() bool {
+	return len(b.Falhas()) == 0
+}
+
+// QuantidadeCNPJ soma os CNPJs aceitos nas partes enviadas.
+func (b *BatchSubmission) QuantidadeCNPJ() int {
+	total := 0
+	for _, parte := range b.Partes {
+		if parte.Enviada() {
+			total += parte.Resposta.QuantidadeCNPJ
+		}
+	}
+	return total
+}
+
+// QuantidadeCPF soma os CPFs aceitos nas partes enviadas.
+func (b *BatchSubmission) QuantidadeCPF() int {
+	total := 0
+	for _, parte := range b.Partes {
+		if parte.Enviada() {
+			total += parte.Resposta.QuantidadeCPF
+		}
+	}
+	return total
+}
+
+// Err retorna um *BatchSubmissionError se alguma parte não foi enviada.
+func (b *BatchSubmission) Err() error {
+	falhas := b.Falhas()
+	if len(falhas) == 0 {
+		return nil
+	}
+	return &BatchSubmissionError{Falhas: falhas, Total: len(b.Partes)}
+}
+
+// BatchSubmissionError descreve as partes de um lote que não foram enviadas.
+type BatchSubmissionError struct {
+	Falhas []BatchChunk
+	Total  int // Quantidade total de partes do lote
+}
+
+// Error lista as partes com erro e os motivos.
+func (e *BatchSubmissionError) Error() string {
+	motivos := make([]string, 0, len(e.Falhas))
+	for _, falha := range e.Falhas {
+		motivos = append(motivos, fmt.Sprintf("parte %d (%v)", falha.Indice, falha.Err))
+	}
+	return fmt.Sprintf("%s: %d de %d partes com erro: %s", ErrLoteParcial.Error(), len(e.Falhas), e.Total, strings.Join(motivos, ", "))
+}
+
+// Is permite comparar o erro com ErrLoteParcial via errors.Is.
+func (e *BatchSubmissionError) Is(target error) bool {
+	return target == ErrLoteParcial
+}
+
+// Unwrap expõe os erros das partes para errors.Is e errors.As.
+func (e *BatchSubmissionError) Unwrap() []error {
+	erros := make([]error, 0, len(e.Falhas))
+	for _, falha := range e.Falhas {
+		if falha.Err != nil {
+			erros = append(erros, falha.Err)
+		}
+	}
+	return erros
+}
+
+// divideDocumentos separa os documentos em partes de até tamanho itens.
+func divideDocumentos(documentos []Documento, tamanho int) []BatchChunk {
+	partes := make([]BatchChunk, 0, (len(documentos)+tamanho-1)/tamanho)
+	for inicio := 0; inicio < len(documentos); inicio += tamanho {
+		fim := inicio + tamanho
+		if fim > len(documentos) {
+			fim = len(documentos)
+		}
+		partes = append(partes, BatchChunk{Indice: len(partes), Documentos: documentos[inicio:fim]})
+	}
+	return partes
+}
+
+// SubmitBatch valida o lote completo conforme opts.BatchPolicy ou a da sessão, divide os documentos em
+// partes dentro do limite da API e as envia com EnviaCNPJsParaAnalise, com no máximo
+// opts.Concorrencia requisições simultâneas. O BatchSubmission é retornado mesmo quando há
+// partes com erro, junto com um *BatchSubmissionError; use ResumeBatch para reenviá-las.
+func (vc *VaduClient) SubmitBatch(ctx context.Context, cnpjEmpresa string, idGrupoAnalise int, listaCNPJCPF []string, postBack *PostBack, opts BatchOptions, auth AuthenticationInterface) (*BatchSubmission, error) {
+	// A validação é feita no lote completo para detectar duplicidades entre partes
+	report, documentos, err := vc.aplicaBatchPolicy(vc.batchPolicy(opts.BatchPolicy), cnpjEmpresa, listaCNPJCPF)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = opts.normaliza(LimiteDocumentosPorRequisicao)
+	submission := &BatchSubmission{
+		CNPJEmpresa:    report.CNPJEmpresa,
+		IDGrupoAnalise: idGrupoAnalise,
+		PostBack:       postBack,
+		Report:         report,
+		Partes:         divideDocumentos(documentos, opts.TamanhoParte),
+		Opcoes:         opts,
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"cnpjEmpresa":    submission.CNPJEmpresa,
+		"idGrupoAnalise": idGrupoAnalise,
+		"documentos":     len(documentos),
+		"partes":         len(submission.Partes),
+		"concorrencia":   opts.Concorrencia,
+	}).Info("Enviando lote dividido em partes")
+
+	vc.enviaPartes(ctx, submission, auth)
+	return submission, submission.Err()
+}
+
+// ResumeBatch reenvia apenas as partes do lote que não foram aceitas, atualizando o
+// BatchSubmission. Retorna um *BatchSubmissionError se ainda restarem partes com erro.
+func (vc *VaduClient) ResumeBatch(ctx context.Context, submission *BatchSubmission, auth AuthenticationInterface) error {
+	vc.logger.WithFields(logrus.Fields{
+		"cnpjEmpresa":    submission.CNPJEmpresa,
+		"idGrupoAnalise": submission.IDGrupoAnalise,
+		"partes":         len(submission.Falhas()),
+	}).Info("Retomando envio das partes com erro")
+
+	vc.enviaPartes(ctx, submission, auth)
+	return submission.Err()
+}
+
+// enviaPartes envia as partes ainda não aceitas com concorrência limitada.
+func (vc *VaduClient) enviaPartes(ctx context.Context, submission *BatchSubmission, auth AuthenticationInterface) {
+	opts := submission.Opcoes.normaliza(LimiteDocumentosPorRequisicao)
+	semaforo := make(chan struct{}, opts.Concorrencia)
+	var wg sync.WaitGroup
+
+	for i := range submission.Partes {
+		parte := &submission.Partes[i]
+		if parte.Enviada() {
+			continue
+		}
+
+		select {
+		case semaforo <- struct{}{}:
+		case <-ctx.Done():
+			parte.Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(parte *BatchChunk) {
+			defer wg.Done()
+			defer func() { <-semaforo }()
+
+			if err := ctx.Err(); err != nil {
+				parte.Err = err
+				return
+			}
+			parte.Resposta, parte.Err = vc.enviaParte(ctx, submission, parte, auth)
+			err := parte.Err
+			if err != nil {
+				vc.logger.WithFields(logrus.Fields{
+					"parte":      parte.Indice,
+					"documentos": len(parte.Documentos),
+				}).WithError(err).Error("Erro ao enviar parte do lote")
+			}
+		}(parte)
+	}
+	wg.Wait()
+}
+
+// enviaParte envia uma parte pela API correspondente ao seu conteúdo.
+func (vc *VaduClient) enviaParte(ctx context.Context, submission *BatchSubmission, parte *BatchChunk, auth AuthenticationInterface) (*EnviaCNPJsResponse, error) {
+	if parte.Dados != nil {
+		return vc.EnviaCNPJsComDadosParaAnalise(ctx, string(submission.CNPJEmpresa), submission.IDGrupoAnalise, parte.Dados, submission.PostBack, auth)
+	}
+	documentos := make([]string, len(parte.Documentos))
+	for i, documento := range parte.Documentos {
+		documentos[i] = string(documento)
+	}
+	// Os documentos já foram selecionados pela BatchPolicy na validação do lote completo
+	response, _, err := vc.enviaCNPJs(ctx, BatchPolicySubmitAnyway, string(submission.CNPJEmpresa), submission.IDGrupoAnalise, documentos, submission.PostBack, auth)
+	return response, err
+}
+
+// divideDados separa os dados em partes limitadas pela quantidade de itens e pelo tamanho do
+// corpo serializado. Um item que sozinho excede o tamanho máximo é enviado em uma parte própria.
+func divideDados(requestBody EnviaCNPJsComDadosRequest, listaDados []DadosIntegracao, opts BatchOptions) ([]BatchChunk, error) {
+	// Tamanho do corpo sem nenhum item, incluindo cnpjEmpresa, grupo e postBack
+	requestBody.ListaCNPJCPFDadosIntegracao = []DadosIntegracao{}
+	envelope, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao preparar o payload: %w", err)
+	}
+
+	var partes []BatchChunk
+	atual := BatchChunk{Bytes: len(envelope)}
+	for _, dados := range listaDados {
+		item, err := json.Marshal(dados)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao preparar o payload: %w", err)
+		}
+		tamanho := len(item)
+		if len(atual.Dados) > 0 {
+			tamanho++ // vírgula entre os itens
+		}
+		if len(atual.Dados) > 0 && (len(atual.Dados) == opts.TamanhoParte || atual.Bytes+tamanho > opts.MaximoBytes) {
+			partes = append(partes, atual)
+			atual = BatchChunk{Indice: len(partes), Bytes: len(envelope)}
+			tamanho = len(item)
+		}
+		atual.Dados = append(atual.Dados, dados)
+		atual.Documentos = append(atual.Documentos, Documento(NormalizaDocumento(string(dados.CNPJCPF))))
+		atual.Bytes += tamanho
+	}
+	if len(atual.Dados) > 0 {
+		partes = append(partes, atual)
+	}
+	return partes, nil
+}
+
+// SubmitBatchComDados divide os dados de integração em partes limitadas por quantidade
+// (LimiteDadosPorRequisicao) e pelo tamanho do corpo serializado (opts.MaximoBytes) e as envia
+// com EnviaCNPJsComDadosParaAnalise, com concorrência limitada. A verificação de CNPJs
+// alfanuméricos e a ConsistencyPolicy são aplicadas ao lote completo antes do primeiro envio.
+// Os resultados são agregados em um BatchSubmission, como em SubmitBatch, e partes com erro
+// podem ser reenviadas com ResumeBatch.
+func (vc *VaduClient) SubmitBatchComDados(ctx context.Context, cnpjEmpresa string, idGrupoAnalise int, listaDados []DadosIntegracao, postBack *PostBack, opts BatchOptions, auth AuthenticationInterface) (*BatchSubmission, error) {
+	requestBody := EnviaCNPJsComDadosRequest{
+		CNPJEmpresa:    Documento(NormalizaDocumento(cnpjEmpresa)),
+		IDGrupoAnalise: idGrupoAnalise,
+		PostBack:       postBack,
+	}
+
+	documentos := []Documento{requestBody.CNPJEmpresa}
+	for _, dados := range listaDados {
+		documentos = append(documentos, Documento(NormalizaDocumento(string(dados.CNPJCPF))))
+	}
+	if err := vc.verificaCNPJAlfanumerico(documentos...); err != nil {
+		vc.logger.WithError(err).Error("Documento não suportado no lote")
+		return nil, err
+	}
+	if err := vc.aplicaConsistencyPolicy(listaDados); err != nil {
+		return nil, err
+	}
+
+	opts = opts.normaliza(LimiteDadosPorRequisicao)
+	partes, err := divideDados(requestBody, listaDados, opts)
+	if err != nil {
+		vc.logger.WithError(err).Error("Erro ao dividir o lote com dados")
+		return nil, err
+	}
+	submission := &BatchSubmission{
+		CNPJEmpresa:    requestBody.CNPJEmpresa,
+		IDGrupoAnalise: idGrupoAnalise,
+		PostBack:       postBack,
+		Partes:         partes,
+		Opcoes:         opts,
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"cnpjEmpresa":    submission.CNPJEmpresa,
+		"idGrupoAnalise": idGrupoAnalise,
+		"documentos":     len(listaDados),
+		"partes":         len(partes),
+		"maximoBytes":    opts.MaximoBytes,
+		"concorrencia":   opts.Concorrencia,
+	}).Info("Enviando lote com dados dividido em partes")
+
+	vc.enviaPartes(ctx, submission, auth)
+	return submission, submission.Err()
+}
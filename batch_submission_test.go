@@ -0,0 +1,224 @@
+package vadu_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchSubmissionTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	ctx    context.Context
+	logger *logrus.Logger
+}
+
+func TestBatchSubmissionTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchSubmissionTestSuite))
+}
+
+func (s *BatchSubmissionTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+}
+
+// geraCPFs gera n CPFs válidos e distintos.
+func geraCPFs(n int) []string {
+	cpfs := make([]string, 0, n)
+	for base := 100000000; len(cpfs) < n; base++ {
+		digitos := fmt.Sprintf("%09d", base)
+		for _, tamanho := range []int{9, 10} {
+			soma := 0
+			for i := 0; i < tamanho; i++ {
+				soma += int(digitos[i]-'0') * (tamanho + 1 - i)
+			}
+			dv := soma * 10 % 11 % 10
+			digitos += fmt.Sprint(dv)
+		}
+		cpfs = append(cpfs, digitos)
+	}
+	return cpfs
+}
+
+// servidorLote simula a API de envio, registrando os documentos de cada requisição e
+// falhando uma vez as partes cujo primeiro documento estiver em falhar.
+type servidorLote struct {
+	mu          sync.Mutex
+	requisicoes []int
+	falhar      map[string]bool
+	proximoID   int
+}
+
+func (m *servidorLote) client() *http.Client {
+	return &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			var corpo struct {
+				ListaCNPJCPF []string `json:"lista_cnpj_cpf"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&corpo); err != nil {
+				return nil, err
+			}
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.requisicoes = append(m.requisicoes, len(corpo.ListaCNPJCPF))
+			if m.falhar[corpo.ListaCNPJCPF[0]] {
+				delete(m.falhar, corpo.ListaCNPJCPF[0])
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader("indisponível"))}, nil
+			}
+			m.proximoID++
+			body := fmt.Sprintf(`{"analise_id": %d, "quantidade_cnpj": 0, "quantidade_cpf": %d}`, m.proximoID, len(corpo.ListaCNPJCPF))
+			return &http.Response{StatusCode: http.StatusCreated, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		},
+	}}
+}
+
+// TestSubmitBatch verifica a divisão em partes, a agregação e a retomada das partes com erro
+func (s *BatchSubmissionTestSuite) TestSubmitBatch() {
+	cpfs := geraCPFs(4500)
+	servidor := &servidorLote{falhar: map[string]bool{cpfs[2000]: true}}
+
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", s.ctx).Return("mocked_token", nil)
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidor.client(), *session, s.logger)
+
+	submission, err := vaduClient.SubmitBatch(s.ctx, "33011770000199", 10802, cpfs, nil, vadu.BatchOptions{Concorrencia: 2}, authentication)
+	s.assert.ErrorIs(err, vadu.ErrLoteParcial)
+	s.assert.NotNil(submission)
+	s.assert.Len(submission.Partes, 3)
+	s.assert.Len(submission.Partes[0].Documentos, 2000)
+	s.assert.Len(submission.Partes[2].Documentos, 500)
+	s.assert.False(submission.Concluida())
+	s.assert.Len(submission.Falhas(), 1)
+	s.assert.Equal(1, submission.Falhas()[0].Indice)
+	s.assert.Len(submission.AnaliseIDs(), 2)
+	s.assert.Equal(2500, submission.QuantidadeCPF())
+	s.assert.ElementsMatch([]int{2000, 2000, 500}, servidor.requisicoes)
+
+	// A retomada reenvia apenas a parte com erro
+	s.assert.NoError(vaduClient.ResumeBatch(s.ctx, submission, authentication))
+	s.assert.True(submission.Concluida())
+	s.assert.Len(submission.AnaliseIDs(), 3)
+	s.assert.Equal(4500, submission.QuantidadeCPF())
+	s.assert.Len(servidor.requisicoes, 4)
+	s.assert.Equal(2000, servidor.requisicoes[3])
+}
+
+// TestSubmitBatchOpcoes verifica o tamanho de parte, a validação do lote completo e o cancelamento
+func (s *BatchSubmissionTestSuite) TestSubmitBatchOpcoes() {
+	servidor := &servidorLote{}
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", s.ctx).Return("mocked_token", nil)
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidor.client(), *session, s.logger)
+
+	cpfs := geraCPFs(25)
+	submission, err := vaduClient.SubmitBatch(s.ctx, "33011770000199", 10802, cpfs, nil, vadu.BatchOptions{TamanhoParte: 10}, authentication)
+	s.assert.NoError(err)
+	s.assert.Len(submission.Partes, 3)
+	s.assert.Len(submission.AnaliseIDs(), 3)
+
+	// Duplicidades entre partes são detectadas antes de qualquer envio
+	_, err = vaduClient.SubmitBatch(s.ctx, "33011770000199", 10802, append(cpfs, cpfs[0]), nil, vadu.BatchOptions{TamanhoParte: 10, BatchPolicy: policy(vadu.BatchPolicyFail)}, authentication)
+	s.assert.ErrorIs(err, vadu.ErrBatchInvalido)
+	s.assert.Len(servidor.requisicoes, 3)
+
+	// Com o contexto cancelado, nenhuma parte é enviada e todas podem ser retomadas
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	submission, err = vaduClient.SubmitBatch(ctx, "33011770000199", 10802, cpfs, nil, vadu.BatchOptions{TamanhoParte: 10}, authentication)
+	s.assert.ErrorIs(err, vadu.ErrLoteParcial)
+	s.assert.ErrorIs(err, context.Canceled)
+	s.assert.Len(submission.Falhas(), 3)
+	s.assert.Len(servidor.requisicoes, 3)
+}
+
+// TestSubmitBatchComDados verifica a divisão por quantidade e por tamanho do corpo no envio com dados
+func (s *BatchSubmissionTestSuite) TestSubmitBatchComDados() {
+	var mu sync.Mutex
+	var tamanhos, quantidades []int
+	httpClient := &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			corpo, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			var requisicao vadu.EnviaCNPJsComDadosRequest
+			if err := json.Unmarshal(corpo, &requisicao); err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			tamanhos = append(tamanhos, len(corpo))
+			quantidades = append(quantidades, len(requisicao.ListaCNPJCPFDadosIntegracao))
+			body := fmt.Sprintf(`{"analise_id": %d, "quantidade_cpf": %d}`, len(tamanhos), len(requisicao.ListaCNPJCPFDadosIntegracao))
+			return &http.Response{StatusCode: http.StatusCreated, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		},
+	}}
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", s.ctx).Return("mocked_token", nil)
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(httpClient, *session, s.logger)
+
+	listaDados := make([]vadu.DadosIntegracao, 0, 250)
+	for _, cpf := range geraCPFs(250) {
+		listaDados = append(listaDados, vadu.DadosIntegracao{CNPJCPF: vadu.Documento(cpf), AtivoTotal: vadu.MustDecimal("1234.56")})
+	}
+
+	// Apenas o limite de itens por requisição
+	submission, err := vaduClient.SubmitBatchComDados(s.ctx, "33011770000199", 10802, listaDados, nil, vadu.BatchOptions{}, authentication)
+	s.assert.NoError(err)
+	s.assert.Len(submission.Partes, 3)
+	s.assert.Len(submission.Partes[0].Dados, vadu.LimiteDadosPorRequisicao)
+	s.assert.Len(submission.Partes[2].Documentos, 50)
+	s.assert.Equal(250, submission.QuantidadeCPF())
+	s.assert.ElementsMatch([]int{100, 100, 50}, quantidades)
+
+	// Limite de bytes menor que o corpo de 100 itens
+	tamanhos, quantidades = nil, nil
+	submission, err = vaduClient.SubmitBatchComDados(s.ctx, "33011770000199", 10802, listaDados, nil, vadu.BatchOptions{MaximoBytes: 64 * 1024}, authentication)
+	s.assert.NoError(err)
+	s.assert.Greater(len(submission.Partes), 3)
+	s.assert.Len(submission.AnaliseIDs(), len(submission.Partes))
+	s.assert.Equal(250, submission.QuantidadeCPF())
+	for i, tamanho := range tamanhos {
+		s.assert.LessOrEqual(tamanho, 64*1024, "requisição %d", i)
+	}
+	for _, parte := range submission.Partes {
+		s.assert.LessOrEqual(parte.Bytes, 64*1024)
+	}
+
+	// Um item maior que o limite é enviado sozinho
+	submission, err = vaduClient.SubmitBatchComDados(s.ctx, "33011770000199", 10802, listaDados[:3], nil, vadu.BatchOptions{MaximoBytes: 10}, authentication)
+	s.assert.NoError(err)
+	s.assert.Len(submission.Partes, 3)
+
+	// A ConsistencyPolicy é aplicada ao lote completo antes de qualquer envio
+	policy := vadu.ConsistencyPolicyRejectErrors
+	session, err = vadu.NewSession(vadu.Config{ConsistencyPolicy: &policy})
+	s.assert.NoError(err)
+	vaduClient = vadu.NewVaduClient(httpClient, *session, s.logger)
+	enviadas := len(tamanhos)
+	invalidos := append([]vadu.DadosIntegracao{}, listaDados...)
+	invalidos[200].AtivoTotal = vadu.MustDecimal("-1")
+	_, err = vaduClient.SubmitBatchComDados(s.ctx, "33011770000199", 10802, invalidos, nil, vadu.BatchOptions{}, authentication)
+	s.assert.ErrorIs(err, vadu.ErrDadosInconsistentes)
+	s.assert.Len(tamanhos, enviadas)
+}
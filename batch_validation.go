@@ -0,0 +1,316 @@
+package vadu
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LimiteDocumentosPorRequisicao é a quantidade máxima de documentos aceita por EnviaCNPJsParaAnalise.
+	LimiteDocumentosPorRequisicao = 2000
+	// LimiteDadosPorRequisicao é a quantidade máxima de documentos aceita por EnviaCNPJsComDadosParaAnalise.
+	LimiteDadosPorRequisicao = 100
+)
+
+// ProblemaDocumento identifica um problema encontrado na validação de um item do lote.
+type ProblemaDocumento string
+
+const (
+	// ProblemaVazio indica um item vazio ou composto apenas por máscara.
+	ProblemaVazio ProblemaDocumento = "vazio"
+	// ProblemaFormatoInvalido indica um item que não tem formato de CPF nem de CNPJ.
+	ProblemaFormatoInvalido ProblemaDocumento = "formato_invalido"
+	// ProblemaDigitoVerificador indica um CPF ou CNPJ com dígitos verificadores incorretos.
+	ProblemaDigitoVerificador ProblemaDocumento = "digito_verificador"
+	// ProblemaDuplicado indica um documento já presente no lote, com ou sem máscara.
+	ProblemaDuplicado ProblemaDocumento = "duplicado"
+	// ProblemaCNPJAlfanumerico indica um CNPJ alfanumérico em um cliente que não o aceita.
+	ProblemaCNPJAlfanumerico ProblemaDocumento = "cnpj_alfanumerico_nao_suportado"
+)
+
+// BatchPolicy define o que fazer com um lote que não passou na validação. A política da
+// sessão pode ser substituída por chamada em BatchOptions.BatchPolicy e AnalysisRequest.BatchPolicy.
+type BatchPolicy int
+
+const (
+	// BatchPolicySubmitAnyway envia o lote normalizado mesmo com problemas, inclusive com o
+	// cnpjEmpresa inválido, apenas registrando-os no log (padrão, mantém o comportamento
+	// anterior à validação de lotes).
+	BatchPolicySubmitAnyway BatchPolicy = iota
+	// BatchPolicyDropInvalid descarta itens inválidos e duplicados e envia os demais. Um
+	// cnpjEmpresa inválido rejeita o lote.
+	BatchPolicyDropInvalid
+	// BatchPolicyFail rejeita o lote inteiro se houver qualquer problema.
+	BatchPolicyFail
+)
+
+// batchPolicy retorna a política informada na chamada ou, se nil, a da sessão.
+func (vc *VaduClient) batchPolicy(policy *BatchPolicy) BatchPolicy {
+	if policy != nil {
+		return *policy
+	}
+	return vc.session.BatchPolicy
+}
+
+// String retorna o nome da política.
+func (p BatchPolicy) String() string {
+	switch p {
+	case BatchPolicyFail:
+		return "fail"
+	case BatchPolicyDropInvalid:
+		return "drop_invalid"
+	case BatchPolicySubmitAnyway:
+		return "submit_anyway"
+	default:
+		return fmt.Sprintf("BatchPolicy(%d)", int(p))
+	}
+}
+
+// ErrBatchInvalido indica que o lote não passou na validação prévia ao envio.
+var ErrBatchInvalido = errors.New("lote de documentos inválido")
+
+// BatchItem contém o resultado da validação de um item do lote.
+type BatchItem struct {
+	Indice      int                 // Posição do item na lista original
+	Entrada     string              // Valor informado, com ou sem máscara
+	Documento   Documento           // Valor normalizado
+	Tipo        TipoDocumento       // Classificação do documento
+	Problemas   []ProblemaDocumento // Problemas encontrados (vazio se o item é válido)
+	DuplicadoDe int                 // Índice da primeira ocorrência, ou -1 se não é duplicado
+}
+
+// Valido informa se o item não possui problemas.
+func (i BatchItem) Valido() bool {
+	return len(i.Problemas) == 0
+}
+
+// BatchValidationReport descreve a validação de um lote antes do envio para análise.
+type BatchValidationReport struct {
+	CNPJEmpresa          Documento
+	ErroCNPJEmpresa      error
+	Itens                []BatchItem
+	QuantidadeCNPJ       int // CNPJs válidos e únicos
+	QuantidadeCPF        int // CPFs válidos e únicos
+	QuantidadeVazios     int
+	QuantidadeInvalidos  int // Itens com formato ou dígito verificador inválido
+	QuantidadeDuplicados int
+	ExcedeLimite         bool // Mais documentos válidos do que LimiteDocumentosPorRequisicao
+}
+
+// Valido informa se todos os itens e o cnpjEmpresa são válidos. O limite de
+// documentos por requisição é informado à parte em ExcedeLimite.
+func (r *BatchValidationReport) Valido() bool {
+	if r.ErroCNPJEmpresa != nil {
+		return false
+	}
+	for _, item := range r.Itens {
+		if !item.Valido() {
+			return false
+		}
+	}
+	return true
+}
+
+// Invalidos retorna os itens que apresentaram algum problema.
+func (r *BatchValidationReport) Invalidos() []BatchItem {
+	var itens []BatchItem
+	for _, item := range r.Itens {
+		if !item.Valido() {
+			itens = append(itens, item)
+		}
+	}
+	return itens
+}
+
+// DocumentosValidos retorna os documentos válidos, sem duplicidades, na ordem original.
+func (r *BatchValidationReport) DocumentosValidos() []Documento {
+	documentos := make([]Documento, 0, len(r.Itens))
+	for _, item := range r.Itens {
+		if item.Valido() {
+			documentos = append(documentos, item.Documento)
+		}
+	}
+	return documentos
+}
+
+// Documentos retorna todos os documentos normalizados, inclusive inválidos e duplicados.
+func (r *BatchValidationReport) Documentos() []Documento {
+	documentos := make([]Documento, 0, len(r.Itens))
+	for _, item := range r.Itens {
+		documentos = append(documentos, item.Documento)
+	}
+	return documentos
+}
+
+// BatchValidationError é retornado quando o lote é rejeitado pela validação.
+type BatchValidationError struct {
+	Report *BatchValidationReport
+	Motivo string
+}
+
+// Error descreve o motivo da rejeição do lote.
+func (e *BatchValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrBatchInvalido.Error(), e.Motivo)
+}
+
+// Is permite comparar o erro com ErrBatchInvalido via errors.Is. Quando o lote
+// contém CNPJs alfanuméricos não habilitados, o erro também corresponde a
+// ErrCNPJAlfanumericoNaoSuportado.
+func (e *BatchValidationError) Is(target error) bool {
+	switch target {
+	case ErrBatchInvalido:
+		return true
+	case ErrCNPJAlfanumericoNaoSuportado:
+		if errors.Is(e.Report.ErroCNPJEmpresa, ErrCNPJAlfanumericoNaoSuportado) {
+			return true
+		}
+		for _, item := range e.Report.Itens {
+			for _, problema := range item.Problemas {
+				if problema == ProblemaCNPJAlfanumerico {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// resumo descreve os problemas do relatório em uma frase.
+func (r *BatchValidationReport) resumo() string {
+	var partes []string
+	if r.ErroCNPJEmpresa != nil {
+		partes = append(partes, "cnpjEmpresa inválido")
+	}
+	if r.QuantidadeVazios > 0 {
+		partes = append(partes, fmt.Sprintf("%d itens vazios", r.QuantidadeVazios))
+	}
+	if r.QuantidadeInvalidos > 0 {
+		partes = append(partes, fmt.Sprintf("%d itens inválidos", r.QuantidadeInvalidos))
+	}
+	if r.QuantidadeDuplicados > 0 {
+		partes = append(partes, fmt.Sprintf("%d itens duplicados", r.QuantidadeDuplicados))
+	}
+	return strings.Join(partes, ", ")
+}
+
+// BatchValidationOptions configura a validação de um lote.
+type BatchValidationOptions struct {
+	AceitaCNPJAlfanumerico bool // Considera válidos os CNPJs alfanuméricos
+}
+
+// ValidateBatch valida um lote de documentos sem realizar nenhuma chamada de rede.
+// São verificados o cnpjEmpresa, itens vazios, formato e dígitos verificadores,
+// duplicidades (inclusive entre versões com e sem máscara) e o limite de documentos.
+func ValidateBatch(cnpjEmpresa string, listaCNPJCPF []string, opts BatchValidationOptions) *BatchValidationReport {
+	report := &BatchValidationReport{
+		Itens: make([]BatchItem, 0, len(listaCNPJCPF)),
+	}
+
+	// Validar a empresa solicitante
+	empresa, err := NewCNPJ(cnpjEmpresa)
+	report.CNPJEmpresa = Documento(NormalizaDocumento(cnpjEmpresa))
+	if err == nil && empresa.Alfanumerico() && !opts.AceitaCNPJAlfanumerico {
+		err = fmt.Errorf("%w: %q", ErrCNPJAlfanumericoNaoSuportado, empresa.String())
+	}
+	report.ErroCNPJEmpresa = err
+
+	primeiros := make(map[Documento]int, len(listaCNPJCPF))
+	for indice, entrada := range listaCNPJCPF {
+		documento := Documento(NormalizaDocumento(entrada))
+		item := BatchItem{
+			Indice:      indice,
+			Entrada:     entrada,
+			Documento:   documento,
+			Tipo:        documento.Tipo(),
+			DuplicadoDe: -1,
+		}
+
+		switch err := documento.Valida(); {
+		case documento == "":
+			item.Problemas = append(item.Problemas, ProblemaVazio)
+			report.QuantidadeVazios++
+		case errors.Is(err, ErrDigitoVerificador):
+			item.Problemas = append(item.Problemas, ProblemaDigitoVerificador)
+			report.QuantidadeInvalidos++
+		case err != nil:
+			item.Problemas = append(item.Problemas, ProblemaFormatoInvalido)
+			report.QuantidadeInvalidos++
+		case documento.Alfanumerico() && !opts.AceitaCNPJAlfanumerico:
+			item.Problemas = append(item.Problemas, ProblemaCNPJAlfanumerico)
+			report.QuantidadeInvalidos++
+		}
+
+		if documento != "" {
+			if primeiro, ok := primeiros[documento]; ok {
+				item.Problemas = append(item.Problemas, ProblemaDuplicado)
+				item.DuplicadoDe = primeiro
+				report.QuantidadeDuplicados++
+			} else {
+				primeiros[documento] = indice
+			}
+		}
+
+		if item.Valido() {
+			switch item.Tipo {
+			case TipoDocumentoCNPJ:
+				report.QuantidadeCNPJ++
+			case TipoDocumentoCPF:
+				report.QuantidadeCPF++
+			}
+		}
+		report.Itens = append(report.Itens, item)
+	}
+
+	report.ExcedeLimite = report.QuantidadeCNPJ+report.QuantidadeCPF > LimiteDocumentosPorRequisicao
+	return report
+}
+
+// ValidateBatch valida um lote de documentos com as configurações da sessão do cliente,
+// sem realizar nenhuma chamada de rede.
+func (vc *VaduClient) ValidateBatch(cnpjEmpresa string, listaCNPJCPF []string) *BatchValidationReport {
+	return ValidateBatch(cnpjEmpresa, listaCNPJCPF, BatchValidationOptions{
+		AceitaCNPJAlfanumerico: vc.session.AceitaCNPJAlfanumerico,
+	})
+}
+
+// aplicaBatchPolicy valida o lote e retorna os documentos a enviar conforme a política.
+func (vc *VaduClient) aplicaBatchPolicy(policy BatchPolicy, cnpjEmpresa string, listaCNPJCPF []string) (*BatchValidationReport, []Documento, error) {
+	report := vc.ValidateBatch(cnpjEmpresa, listaCNPJCPF)
+	if report.Valido() {
+		return report, report.DocumentosValidos(), nil
+	}
+
+	campos := logrus.Fields{
+		"cnpjEmpresa":          report.CNPJEmpresa,
+		"politica":             policy.String(),
+		"quantidadeItens":      len(report.Itens),
+		"quantidadeVazios":     report.QuantidadeVazios,
+		"quantidadeInvalidos":  report.QuantidadeInvalidos,
+		"quantidadeDuplicados": report.QuantidadeDuplicados,
+	}
+
+	switch policy {
+	case BatchPolicySubmitAnyway:
+		vc.logger.WithFields(campos).WithError(report.ErroCNPJEmpresa).Warn("Lote com problemas enviado por configuração")
+		return report, report.Documentos(), nil
+	case BatchPolicyDropInvalid:
+		// O cnpjEmpresa inválido não pode ser descartado e rejeita o lote
+		if report.ErroCNPJEmpresa != nil {
+			vc.logger.WithFields(campos).WithError(report.ErroCNPJEmpresa).Error("cnpjEmpresa inválido")
+			return report, nil, &BatchValidationError{Report: report, Motivo: report.resumo()}
+		}
+		documentos := report.DocumentosValidos()
+		if len(documentos) == 0 {
+			vc.logger.WithFields(campos).Error("Nenhum documento válido no lote")
+			return report, nil, &BatchValidationError{Report: report, Motivo: "nenhum documento válido"}
+		}
+		vc.logger.WithFields(campos).Warn("Documentos inválidos descartados do lote")
+		return report, documentos, nil
+	default:
+		vc.logger.WithFields(campos).Error("Lote rejeitado na validação")
+		return report, nil, &BatchValidationError{Report: report, Motivo: report.resumo()}
+	}
+}
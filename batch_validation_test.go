@@ -0,0 +1,162 @@
+package vadu_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchValidationTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	ctx    context.Context
+	logger *logrus.Logger
+}
+
+func TestBatchValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchValidationTestSuite))
+}
+
+func (s *BatchValidationTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+}
+
+// TestRelatorio verifica a classificação de cada item do lote
+func (s *BatchValidationTestSuite) TestRelatorio() {
+	report := vadu.ValidateBatch("33.011.770/0001-99", []string{
+		"98960887000164",
+		"98.960.887/0001-64",
+		"529.982.247-25",
+		"98960887000165",
+		"  ",
+		"123",
+	}, vadu.BatchValidationOptions{})
+
+	s.assert.NoError(report.ErroCNPJEmpresa)
+	s.assert.False(report.Valido())
+	s.assert.Equal(1, report.QuantidadeCNPJ)
+	s.assert.Equal(1, report.QuantidadeCPF)
+	s.assert.Equal(1, report.QuantidadeDuplicados)
+	s.assert.Equal(1, report.QuantidadeVazios)
+	s.assert.Equal(2, report.QuantidadeInvalidos)
+
+	s.assert.Equal([]vadu.ProblemaDocumento{vadu.ProblemaDuplicado}, report.Itens[1].Problemas)
+	s.assert.Equal(0, report.Itens[1].DuplicadoDe)
+	s.assert.Equal([]vadu.ProblemaDocumento{vadu.ProblemaDigitoVerificador}, report.Itens[3].Problemas)
+	s.assert.Equal([]vadu.ProblemaDocumento{vadu.ProblemaVazio}, report.Itens[4].Problemas)
+	s.assert.Equal([]vadu.ProblemaDocumento{vadu.ProblemaFormatoInvalido}, report.Itens[5].Problemas)
+	s.assert.Equal([]vadu.Documento{"98960887000164", "52998224725"}, report.DocumentosValidos())
+
+	report = vadu.ValidateBatch("33011770000198", []string{"98960887000164"}, vadu.BatchValidationOptions{})
+	s.assert.ErrorIs(report.ErroCNPJEmpresa, vadu.ErrDigitoVerificador)
+	s.assert.False(report.Valido())
+}
+
+// TestPoliticas verifica o comportamento de cada BatchPolicy no envio
+func (s *BatchValidationTestSuite) TestPoliticas() {
+	lista := []string{"98.960.887/0001-64", "98960887000164", "98960887000165"}
+
+	var enviados int
+	httpClient := mock.EnviaCNPJsParaAnaliseMock()
+	transport := httpClient.Transport.(*mock.MockAuthHTTPClient)
+	doFunc := transport.DoFunc
+	transport.DoFunc = func(req *http.Request) (*http.Response, error) {
+		enviados++
+		return doFunc(req)
+	}
+
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", s.ctx).Return("mocked_token", nil)
+
+	// BatchPolicyFail rejeita o lote sem chamadas de rede
+	session, err := vadu.NewSession(vadu.Config{BatchPolicy: policy(vadu.BatchPolicyFail)})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(httpClient, *session, s.logger)
+	_, err = vaduClient.EnviaCNPJsParaAnalise(s.ctx, "33011770000199", 10802, lista, nil, authentication)
+	s.assert.ErrorIs(err, vadu.ErrBatchInvalido)
+	var validationErr *vadu.BatchValidationError
+	s.assert.ErrorAs(err, &validationErr)
+	s.assert.Equal(1, validationErr.Report.QuantidadeDuplicados)
+	s.assert.Equal(0, enviados)
+	authentication.AssertNotCalled(s.T(), "Token", s.ctx)
+
+	// BatchPolicyDropInvalid envia apenas o documento válido
+	session, err = vadu.NewSession(vadu.Config{BatchPolicy: policy(vadu.BatchPolicyDropInvalid)})
+	s.assert.NoError(err)
+	vaduClient = vadu.NewVaduClient(httpClient, *session, s.logger)
+	_, err = vaduClient.EnviaCNPJsParaAnalise(s.ctx, "33011770000199", 10802, lista, nil, authentication)
+	s.assert.NoError(err)
+	s.assert.Equal(1, enviados)
+
+	// cnpjEmpresa inválido rejeita o lote em BatchPolicyDropInvalid
+	_, err = vaduClient.EnviaCNPJsParaAnalise(s.ctx, "", 10802, lista, nil, authentication)
+	s.assert.ErrorIs(err, vadu.ErrBatchInvalido)
+	s.assert.Equal(1, enviados)
+
+	// BatchPolicySubmitAnyway envia o lote mesmo com problemas, inclusive no cnpjEmpresa
+	session, err = vadu.NewSession(vadu.Config{BatchPolicy: policy(vadu.BatchPolicySubmitAnyway)})
+	s.assert.NoError(err)
+	vaduClient = vadu.NewVaduClient(httpClient, *session, s.logger)
+	_, err = vaduClient.EnviaCNPJsParaAnalise(s.ctx, "33011770000199", 10802, lista, nil, authentication)
+	s.assert.NoError(err)
+	_, err = vaduClient.EnviaCNPJsParaAnalise(s.ctx, "", 10802, lista, nil, authentication)
+	s.assert.NoError(err)
+	s.assert.Equal(3, enviados)
+
+	// Sem configuração, e no valor zero da política, o lote é enviado como antes da validação
+	var zero vadu.BatchPolicy
+	s.assert.Equal(vadu.BatchPolicySubmitAnyway, zero)
+	session, err = vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.BatchPolicySubmitAnyway, session.BatchPolicy)
+	vaduClient = vadu.NewVaduClient(httpClient, *session, s.logger)
+	_, err = vaduClient.EnviaCNPJsParaAnalise(s.ctx, "33011770000199", 10802, lista, nil, authentication)
+	s.assert.NoError(err)
+	s.assert.Equal(4, enviados)
+}
+
+// TestPoliticaPorChamada verifica a substituição da política da sessão em SubmitBatch e Analyze
+func (s *BatchValidationTestSuite) TestPoliticaPorChamada() {
+	lista := []string{"98.960.887/0001-64", "98960887000164", "98960887000165"}
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", s.ctx).Return("mocked_token", nil)
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidorAnalise(rotasAnalise), *session, s.logger)
+
+	_, err = vaduClient.SubmitBatch(s.ctx, "33011770000199", 10802, lista, nil, vadu.BatchOptions{BatchPolicy: policy(vadu.BatchPolicyFail)}, authentication)
+	s.assert.ErrorIs(err, vadu.ErrBatchInvalido)
+
+	// As partes não são validadas novamente com a política da sessão
+	session, err = vadu.NewSession(vadu.Config{BatchPolicy: policy(vadu.BatchPolicyFail)})
+	s.assert.NoError(err)
+	vaduClient = vadu.NewVaduClient(servidorAnalise(rotasAnalise), *session, s.logger)
+	submission, err := vaduClient.SubmitBatch(s.ctx, "33011770000199", 10802, lista, nil, vadu.BatchOptions{BatchPolicy: policy(vadu.BatchPolicySubmitAnyway)}, authentication)
+	s.assert.NoError(err)
+	s.assert.Len(submission.AnaliseIDs(), 1)
+
+	// Analyze registra apenas os documentos efetivamente enviados
+	result, err := vaduClient.Analyze(s.ctx, vadu.AnalysisRequest{
+		CNPJEmpresa:    "33011770000199",
+		IDGrupoAnalise: 10802,
+		ListaCNPJCPF:   lista,
+		BatchPolicy:    policy(vadu.BatchPolicyDropInvalid),
+	}, authentication)
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.Documento("98960887000164"), result.Ordem[0])
+	s.assert.NotContains(result.Ordem, vadu.Documento("98960887000165"))
+}
+
+func policy(p vadu.BatchPolicy) *vadu.BatchPolicy {
+	return &p
+}
@@ -0,0 +1,129 @@
+package vadu
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TrabalhadoresPadrao é a quantidade padrão de análises consultadas em paralelo por FetchMany.
+const TrabalhadoresPadrao = 8
+
+// FetchOptions configura a consulta de várias análises.
+type FetchOptions struct {
+	Trabalhadores int  // Análises consultadas em paralelo (padrão TrabalhadoresPadrao)
+	SemDocumentos bool // Consulta apenas PegaResumoAnalise, sem ListaResumoCNPJs
+}
+
+// normaliza aplica os valores padrão.
+func (o FetchOptions) normaliza() FetchOptions {
+	if o.Trabalhadores <= 0 {
+		o.Trabalhadores = TrabalhadoresPadrao
+	}
+	return o
+}
+
+// FetchResult é o resultado da consulta de uma análise por FetchMany.
+type FetchResult struct {
+	Indice     int // Posição do ID na lista informada
+	AnaliseID  int
+	Resumo     *ResumoAnalise
+	Documentos []ResumoCNPJ
+	Err        error // Erro da consulta desta análise, ou nil
+}
+
+// FetchMany consulta PegaResumoAnalise e ListaResumoCNPJs para cada análise com no máximo
+// opts.Trabalhadores consultas simultâneas, respeitando o RateLimiter da sessão. Os resultados
+// são retornados na ordem dos IDs informados, cada um com o seu próprio erro. Quando o ctx
+// termina, as análises ainda não consultadas recebem o erro do contexto.
+func (vc *VaduClient) FetchMany(ctx context.Context, analiseIDs []int, opts FetchOptions, auth AuthenticationInterface) []FetchResult {
+	resultados := make([]FetchResult, len(analiseIDs))
+	vc.buscaAnalises(ctx, analiseIDs, opts, auth, func(resultado FetchResult) {
+		resultados[resultado.Indice] = resultado
+	})
+	return resultados
+}
+
+// FetchManyStream consulta as análises como FetchMany, mas entrega cada resultado no canal
+// assim que a consulta termina, fora da ordem dos IDs. Como em FetchMany, o canal recebe um
+// resultado por ID: quando o ctx termina, as análises ainda não consultadas são entregues
+// com o erro do contexto. O canal tem capacidade para todos os resultados, portanto as
+// consultas não aguardam a leitura, e é fechado após o último resultado.
+func (vc *VaduClient) FetchManyStream(ctx context.Context, analiseIDs []int, opts FetchOptions, auth AuthenticationInterface) <-chan FetchResult {
+	resultados := make(chan FetchResult, len(analiseIDs))
+	go func() {
+		defer close(resultados)
+		vc.buscaAnalises(ctx, analiseIDs, opts, auth, func(resultado FetchResult) {
+			resultados <- resultado
+		})
+	}()
+	return resultados
+}
+
+// buscaAnalises distribui as consultas entre os trabalhadores e entrega cada resultado.
+// entrega é chamada concorrentemente, exatamente uma vez por análise; quando o ctx termina,
+// as análises não distribuídas são entregues com o erro do contexto.
+func (vc *VaduClient) buscaAnalises(ctx context.Context, analiseIDs []int, opts FetchOptions, auth AuthenticationInterface, entrega func(FetchResult)) {
+	opts = opts.normaliza()
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Trabalhadores && i < len(analiseIDs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for indice := range indices {
+				entrega(vc.buscaAnalise(ctx, indice, analiseIDs[indice], opts, auth))
+			}
+		}()
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"analises":      len(analiseIDs),
+		"trabalhadores": opts.Trabalhadores,
+	}).Info("Consultando análises em paralelo")
+
+	proximo := 0
+distribui:
+	for ; proximo < len(analiseIDs); proximo++ {
+		select {
+		case indices <- proximo:
+		case <-ctx.Done():
+			break distribui
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	for indice := proximo; indice < len(analiseIDs); indice++ {
+		entrega(FetchResult{Indice: indice, AnaliseID: analiseIDs[indice], Err: ctx.Err()})
+	}
+}
+
+// buscaAnalise consulta o resumo e os documentos de uma análise.
+func (vc *VaduClient) buscaAnalise(ctx context.Context, indice, analiseID int, opts FetchOptions, auth AuthenticationInterface) FetchResult {
+	resultado := FetchResult{Indice: indice, AnaliseID: analiseID}
+	if err := ctx.Err(); err != nil {
+		resultado.Err = err
+		return resultado
+	}
+
+	resumo, err := vc.PegaResumoAnalise(ctx, analiseID, auth)
+	if err != nil {
+		resultado.Err = fmt.Errorf("erro ao consultar resumo da análise %d: %w", analiseID, err)
+		return resultado
+	}
+	resultado.Resumo = resumo
+
+	if !opts.SemDocumentos {
+		documentos, err := vc.ListaResumoCNPJs(ctx, analiseID, auth)
+		if err != nil {
+			resultado.Err = fmt.Errorf("erro ao consultar documentos da análise %d: %w", analiseID, err)
+			return resultado
+		}
+		resultado.Documentos = documentos
+	}
+	return resultado
+}
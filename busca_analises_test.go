@@ -0,0 +1,181 @@
+package vadu_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BuscaAnalisesTestSuite struct {
+	suite.Suite
+	assert         *assert.Assertions
+	ctx            context.Context
+	logger         *logrus.Logger
+	authentication *mock.MockAuthentication
+}
+
+func TestBuscaAnalisesTestSuite(t *testing.T) {
+	suite.Run(t, new(BuscaAnalisesTestSuite))
+}
+
+func (s *BuscaAnalisesTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+	s.authentication = new(mock.MockAuthentication)
+	s.authentication.On("Token", testifymock.Anything).Return("mocked_token", nil)
+}
+
+var caminhoAnalise = regexp.MustCompile(`/analise/id/(\d+)(/cnpjcpf)?$`)
+
+// servidorResumos responde o resumo e os documentos de qualquer análise, exceto as em falhar,
+// registrando o máximo de requisições simultâneas.
+type servidorResumos struct {
+	mu          sync.Mutex
+	ativas      int
+	maximo      int
+	requisicoes int
+	falhar      map[int]bool
+}
+
+func (m *servidorResumos) client() *http.Client {
+	return &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			m.mu.Lock()
+			m.ativas++
+			m.requisicoes++
+			if m.ativas > m.maximo {
+				m.maximo = m.ativas
+			}
+			m.mu.Unlock()
+			defer func() {
+				m.mu.Lock()
+				m.ativas--
+				m.mu.Unlock()
+			}()
+			time.Sleep(2 * time.Millisecond)
+
+			partes := caminhoAnalise.FindStringSubmatch(req.URL.Path)
+			id, _ := strconv.Atoi(partes[1])
+			if m.falhar[id] {
+				return &http.Response{StatusCode: http.StatusInternalServerError, Body: ioutil.NopCloser(strings.NewReader("erro"))}, nil
+			}
+			body := fmt.Sprintf(`{"analise_id": %d, "concluido": true}`, id)
+			if partes[2] != "" {
+				body = fmt.Sprintf(`[{"analise_id": %d, "cnpj_cpf": "98960887000164"}]`, id)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		},
+	}}
+}
+
+func (s *BuscaAnalisesTestSuite) client(servidor *servidorResumos, config vadu.Config) *vadu.VaduClient {
+	session, err := vadu.NewSession(config)
+	s.assert.NoError(err)
+	return vadu.NewVaduClient(servidor.client(), *session, s.logger)
+}
+
+func idsAnalise(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+// TestFetchMany verifica a ordem dos resultados, os erros por análise e o limite de concorrência
+func (s *BuscaAnalisesTestSuite) TestFetchMany() {
+	servidor := &servidorResumos{falhar: map[int]bool{13: true}}
+	vaduClient := s.client(servidor, vadu.Config{})
+
+	resultados := vaduClient.FetchMany(s.ctx, idsAnalise(40), vadu.FetchOptions{Trabalhadores: 3}, s.authentication)
+	s.assert.Len(resultados, 40)
+	for i, resultado := range resultados {
+		s.assert.Equal(i, resultado.Indice)
+		s.assert.Equal(i+1, resultado.AnaliseID)
+		if resultado.AnaliseID == 13 {
+			s.assert.Error(resultado.Err)
+			s.assert.Nil(resultado.Resumo)
+			continue
+		}
+		s.assert.NoError(resultado.Err)
+		s.assert.Equal(i+1, resultado.Resumo.AnaliseID)
+		s.assert.Len(resultado.Documentos, 1)
+	}
+	s.assert.LessOrEqual(servidor.maximo, 3)
+	s.assert.Equal(79, servidor.requisicoes)
+
+	// Com o contexto cancelado, nenhuma análise é consultada
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	for _, resultado := range vaduClient.FetchMany(ctx, idsAnalise(5), vadu.FetchOptions{}, s.authentication) {
+		s.assert.ErrorIs(resultado.Err, context.Canceled)
+	}
+	s.assert.Equal(79, servidor.requisicoes)
+}
+
+// TestFetchManyStream verifica a entrega por canal e o encerramento ao cancelar o contexto
+func (s *BuscaAnalisesTestSuite) TestFetchManyStream() {
+	servidor := &servidorResumos{}
+	vaduClient := s.client(servidor, vadu.Config{})
+
+	vistos := map[int]bool{}
+	for resultado := range vaduClient.FetchManyStream(s.ctx, idsAnalise(20), vadu.FetchOptions{SemDocumentos: true}, s.authentication) {
+		s.assert.NoError(resultado.Err)
+		s.assert.Nil(resultado.Documentos)
+		vistos[resultado.AnaliseID] = true
+	}
+	s.assert.Len(vistos, 20)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	resultados := vaduClient.FetchManyStream(ctx, idsAnalise(200), vadu.FetchOptions{Trabalhadores: 2}, s.authentication)
+	<-resultados
+	cancel()
+	// Como em FetchMany, cada análise recebe um resultado; as não consultadas, o erro do contexto
+	entregues, canceladas := 1, 0
+	for resultado := range resultados {
+		entregues++
+		if errors.Is(resultado.Err, context.Canceled) {
+			canceladas++
+		}
+	}
+	s.assert.Equal(200, entregues)
+	s.assert.Greater(canceladas, 190)
+	s.assert.Less(servidor.requisicoes, 20+400)
+}
+
+// TestFetchManyRateLimiter verifica que as consultas respeitam o RateLimiter da sessão
+func (s *BuscaAnalisesTestSuite) TestFetchManyRateLimiter() {
+	servidor := &servidorResumos{}
+	vaduClient := s.client(servidor, vadu.Config{RateLimiter: vadu.NewRateLimiter(200, 1)})
+
+	inicio := time.Now()
+	resultados := vaduClient.FetchMany(s.ctx, idsAnalise(11), vadu.FetchOptions{SemDocumentos: true, Trabalhadores: 11}, s.authentication)
+	s.assert.GreaterOrEqual(time.Since(inicio), 45*time.Millisecond)
+	for _, resultado := range resultados {
+		s.assert.NoError(resultado.Err)
+	}
+
+	// A espera pelo limite termina com o contexto
+	limitador := vadu.NewRateLimiter(0.001, 1)
+	s.assert.NoError(limitador.Wait(s.ctx))
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Millisecond)
+	defer cancel()
+	s.assert.ErrorIs(limitador.Wait(ctx), context.DeadlineExceeded)
+}
@@ -14,20 +14,41 @@ import (
 
 // VaduClient estrutura principal para interagir com a API Vadu.
 type VaduClient struct {
-	httpClient *http.Client
-	session    Session
-	logger     *logrus.Logger
+	httpClient      *http.Client
+	session         Session
+	logger          *logrus.Logger
+	acompanhamentos *hubAnalises
 }
 
 // NewVaduClient cria uma nova instância do cliente da API Vadu.
 func NewVaduClient(httpClient *http.Client, session Session, logger *logrus.Logger) *VaduClient {
 	return &VaduClient{
-		httpClient: httpClient,
-		session:    session,
-		logger:     logger,
+		httpClient:      httpClient,
+		session:         session,
+		logger:          logger,
+		acompanhamentos: newHubAnalises(),
 	}
 }
 
+// RespostaAPIError é retornado quando a API responde com um status HTTP inesperado.
+type RespostaAPIError struct {
+	Operacao   string // Operação que falhou (ex.: "consultar status da análise")
+	StatusCode int    // Status HTTP da resposta
+	Resposta   string // Corpo da resposta
+}
+
+// Error descreve a operação, o status e a resposta da API.
+func (e *RespostaAPIError) Error() string {
+	return fmt.Sprintf("erro ao %s: status %d, resposta: %s", e.Operacao, e.StatusCode, e.Resposta)
+}
+
+// Definitivo informa se repetir a requisição não muda o resultado: respostas 4xx, exceto
+// 408 (Request Timeout) e 429 (Too Many Requests).
+func (e *RespostaAPIError) Definitivo() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusRequestTimeout && e.StatusCode != http.StatusTooManyRequests
+}
+
 // ListaGruposAnalise lista os grupos de análise disponíveis na API do Vadu.
 func (vc *VaduClient) ListaGruposAnalise(ctx context.Context, auth AuthenticationInterface) ([]GrupoAnalise, error) {
 
@@ -50,7 +71,7 @@ func (vc *VaduClient) ListaGruposAnalise(ctx context.Context, auth Authenticatio
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	req.Header.Add("Cookie", vc.session.Cookie)
 
-	resp, err := vc.httpClient.Do(req)
+	resp, err := vc.do(req)
 	if err != nil {
 		vc.logger.WithError(err).Error("Erro de conexão ao tentar listar grupos de análise")
 		return nil, fmt.Errorf("erro de conexão com o servidor: %w", err)
@@ -71,7 +92,7 @@ func (vc *VaduClient) ListaGruposAnalise(ctx context.Context, auth Authenticatio
 			"statusCode": resp.StatusCode,
 			"response":   string(respBody),
 		}).Error("Erro ao listar grupos de análise")
-		return nil, fmt.Errorf("erro ao listar grupos de análise: status %d, resposta: %s", resp.StatusCode, string(respBody))
+		return nil, &RespostaAPIError{Operacao: "listar grupos de análise", StatusCode: resp.StatusCode, Resposta: string(respBody)}
 	}
 
 	// Ler corpo da resposta
@@ -87,6 +108,7 @@ func (vc *VaduClient) ListaGruposAnalise(ctx context.Context, auth Authenticatio
 		vc.logger.WithError(err).Error("Erro ao decodificar JSON da resposta")
 		return nil, fmt.Errorf("erro no formato da resposta da API: %w", err)
 	}
+	vc.verificaSchema("ListaGruposAnalise", respBody, &grupos)
 
 	// Sucesso
 	vc.logger.WithFields(logrus.Fields{
@@ -98,28 +120,48 @@ func (vc *VaduClient) ListaGruposAnalise(ctx context.Context, auth Authenticatio
 
 // EnviaCNPJsParaAnalise envia uma lista de CNPJs para análise com validações e logs.
 func (vc *VaduClient) EnviaCNPJsParaAnalise(ctx context.Context, cnpjEmpresa string, idGrupoAnalise int, listaCNPJCPF []string, postBack *PostBack, auth AuthenticationInterface) (*EnviaCNPJsResponse, error) {
+	response, _, err := vc.enviaCNPJs(ctx, vc.session.BatchPolicy, cnpjEmpresa, idGrupoAnalise, listaCNPJCPF, postBack, auth)
+	return response, err
+}
 
-	// Obtenha o token dinamicamente
-	token, err := auth.Token(ctx)
+// enviaCNPJs implementa EnviaCNPJsParaAnalise com a BatchPolicy informada e retorna também os
+// documentos efetivamente enviados.
+func (vc *VaduClient) enviaCNPJs(ctx context.Context, policy BatchPolicy, cnpjEmpresa string, idGrupoAnalise int, listaCNPJCPF []string, postBack *PostBack, auth AuthenticationInterface) (*EnviaCNPJsResponse, []Documento, error) {
+
+	// Validar o lote antes de qualquer chamada de rede, conforme a BatchPolicy
+	report, documentos, err := vc.aplicaBatchPolicy(policy, cnpjEmpresa, listaCNPJCPF)
 	if err != nil {
-		vc.logger.WithError(err).Error("Erro ao obter token de autenticação")
-		return nil, fmt.Errorf("falha ao autenticar: %w", err)
+		return nil, nil, err
 	}
+
 	// Validar o número de CNPJs
-	if len(listaCNPJCPF) > 2000 {
+	if len(documentos) > LimiteDocumentosPorRequisicao {
 		vc.logger.WithFields(logrus.Fields{
 			"cnpjEmpresa":     cnpjEmpresa,
 			"idGrupoAnalise":  idGrupoAnalise,
-			"quantidadeCNPJs": len(listaCNPJCPF),
+			"quantidadeCNPJs": len(documentos),
 		}).Error("Número máximo de CNPJs excedido")
-		return nil, fmt.Errorf("não é permitido enviar mais de 2000 CNPJs por requisição")
+		return nil, nil, fmt.Errorf("não é permitido enviar mais de 2000 CNPJs por requisição")
 	}
 
-	// Montar o corpo da requisição
+	// Rejeitar CNPJs alfanuméricos quando não habilitados para o cliente
+	if err := vc.verificaCNPJAlfanumerico(documentos...); err != nil {
+		vc.logger.WithError(err).Error("Documento não suportado na requisição")
+		return nil, nil, err
+	}
+
+	// Obtenha o token dinamicamente
+	token, err := auth.Token(ctx)
+	if err != nil {
+		vc.logger.WithError(err).Error("Erro ao obter token de autenticação")
+		return nil, nil, fmt.Errorf("falha ao autenticar: %w", err)
+	}
+
+	// Montar o corpo da requisição com os documentos normalizados
 	requestBody := EnviaCNPJsRequest{
-		CNPJEmpresa:    cnpjEmpresa,
+		CNPJEmpresa:    report.CNPJEmpresa,
 		IDGrupoAnalise: idGrupoAnalise,
-		ListaCNPJCPF:   listaCNPJCPF,
+		ListaCNPJCPF:   documentos,
 		PostBack:       postBack, // postBack pode ser nil
 	}
 
@@ -127,7 +169,7 @@ func (vc *VaduClient) EnviaCNPJsParaAnalise(ctx context.Context, cnpjEmpresa str
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		vc.logger.WithError(err).Error("Erro ao converter o corpo da requisição para JSON")
-		return nil, fmt.Errorf("erro ao preparar o payload: %w", err)
+		return nil, nil, fmt.Errorf("erro ao preparar o payload: %w", err)
 	}
 
 	url := fmt.Sprintf("%s/api-analise-cnpjcpf/v1/erp/analise", vc.session.APIEndpoint)
@@ -142,10 +184,10 @@ func (vc *VaduClient) EnviaCNPJsParaAnalise(ctx context.Context, cnpjEmpresa str
 	maxRetries := 3 // Número máximo de tentativas
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		// Criar a requisição HTTP
-		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			vc.logger.WithError(err).Error("Erro ao criar requisição HTTP")
-			return nil, fmt.Errorf("erro ao criar a requisição: %w", err)
+			return nil, nil, fmt.Errorf("erro ao criar a requisição: %w", err)
 		}
 
 		// Definir os cabeçalhos
@@ -153,14 +195,14 @@ func (vc *VaduClient) EnviaCNPJsParaAnalise(ctx context.Context, cnpjEmpresa str
 		req.Header.Set("Authorization", "Bearer "+token)
 
 		// Enviar a requisição usando o httpClient
-		resp, err = vc.httpClient.Do(req)
+		resp, err = vc.do(req)
 		if err != nil {
 			vc.logger.WithFields(logrus.Fields{
 				"attempt": attempt,
 				"error":   err.Error(),
 			}).Error("Erro ao enviar a requisição")
 			if attempt == maxRetries {
-				return nil, fmt.Errorf("falha ao conectar com o servidor após %d tentativas: %w", maxRetries, err)
+				return nil, nil, fmt.Errorf("falha ao conectar com o servidor após %d tentativas: %w", maxRetries, err)
 			}
 			continue // Tentar novamente
 		}
@@ -183,15 +225,15 @@ func (vc *VaduClient) EnviaCNPJsParaAnalise(ctx context.Context, cnpjEmpresa str
 			"statusCode": resp.StatusCode,
 			"response":   string(respBody),
 		}).Error("Falha ao enviar CNPJs para análise")
-		return nil, fmt.Errorf("erro ao enviar CNPJs para análise: status %d, resposta: %s", resp.StatusCode, string(respBody))
+		return nil, nil, &RespostaAPIError{Operacao: "enviar CNPJs para análise", StatusCode: resp.StatusCode, Resposta: string(respBody)}
 	}
 
 	// Decodificar a resposta
 	var response EnviaCNPJsResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	err = vc.decodificaResposta("EnviaCNPJsParaAnalise", resp.Body, &response)
 	if err != nil {
 		vc.logger.WithError(err).Error("Erro ao decodificar JSON da resposta")
-		return nil, fmt.Errorf("erro no formato da resposta da API: %w", err)
+		return nil, nil, fmt.Errorf("erro no formato da resposta da API: %w", err)
 	}
 
 	// Logar a resposta bem-sucedida
@@ -200,8 +242,11 @@ func (vc *VaduClient) EnviaCNPJsParaAnalise(ctx context.Context, cnpjEmpresa str
 		"response":   response,
 	}).Info("CNPJs enviados para análise com sucesso")
 
+	// Registrar a submissão na trilha de auditoria
+	vc.auditaSubmissao(ctx, requestBody, requestBody.CNPJEmpresa, idGrupoAnalise, requestBody.ListaCNPJCPF, &response)
+
 	// Retornar a resposta da API
-	return &response, nil
+	return &response, documentos, nil
 }
 
 // EnviaCNPJsComDadosParaAnalise envia uma lista de CNPJs com dados detalhados para análise com validações e logs.
@@ -213,23 +258,38 @@ func (vc *VaduClient) EnviaCNPJsComDadosParaAnalise(ctx context.Context, cnpjEmp
 		return nil, fmt.Errorf("falha ao autenticar: %w", err)
 	}
 	// Validar o número de CNPJs
-	if len(listaDados) > 100 {
+	if len(listaDados) > LimiteDadosPorRequisicao {
 		vc.logger.WithFields(logrus.Fields{
 			"cnpjEmpresa":     cnpjEmpresa,
 			"idGrupoAnalise":  idGrupoAnalise,
 			"quantidadeCNPJs": len(listaDados),
 		}).Error("Número máximo de CNPJs excedido")
-		return nil, fmt.Errorf("não é permitido enviar mais de 100 CNPJs por requisição")
+		return nil, fmt.Errorf("não é permitido enviar mais de %d CNPJs por requisição", LimiteDadosPorRequisicao)
 	}
 
 	// Montar o corpo da requisição
 	requestBody := EnviaCNPJsComDadosRequest{
-		CNPJEmpresa:                 cnpjEmpresa,
+		CNPJEmpresa:                 Documento(NormalizaDocumento(cnpjEmpresa)),
 		IDGrupoAnalise:              idGrupoAnalise,
 		ListaCNPJCPFDadosIntegracao: listaDados,
 		PostBack:                    postBack, // postBack pode ser nil
 	}
 
+	// Rejeitar CNPJs alfanuméricos quando não habilitados para o cliente
+	documentos := []Documento{requestBody.CNPJEmpresa}
+	for _, dados := range listaDados {
+		documentos = append(documentos, Documento(NormalizaDocumento(string(dados.CNPJCPF))))
+	}
+	if err := vc.verificaCNPJAlfanumerico(documentos...); err != nil {
+		vc.logger.WithError(err).Error("Documento não suportado na requisição")
+		return nil, err
+	}
+
+	// Rejeitar dados inconsistentes conforme a política configurada
+	if err := vc.aplicaConsistencyPolicy(listaDados); err != nil {
+		return nil, err
+	}
+
 	// Converter o corpo para JSON
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
@@ -249,7 +309,7 @@ func (vc *VaduClient) EnviaCNPJsComDadosParaAnalise(ctx context.Context, cnpjEmp
 	maxRetries := 3 // Número máximo de tentativas
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		// Criar a requisição HTTP
-		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			vc.logger.WithError(err).Error("Erro ao criar requisição HTTP")
 			return nil, fmt.Errorf("erro ao criar a requisição: %w", err)
@@ -260,7 +320,7 @@ func (vc *VaduClient) EnviaCNPJsComDadosParaAnalise(ctx context.Context, cnpjEmp
 		req.Header.Set("Authorization", "Bearer "+token)
 
 		// Enviar a requisição usando o httpClient
-		resp, err = vc.httpClient.Do(req)
+		resp, err = vc.do(req)
 		if err != nil {
 			vc.logger.WithFields(logrus.Fields{
 				"attempt": attempt,
@@ -290,12 +350,12 @@ func (vc *VaduClient) EnviaCNPJsComDadosParaAnalise(ctx context.Context, cnpjEmp
 			"statusCode": resp.StatusCode,
 			"response":   string(respBody),
 		}).Error("Falha ao enviar CNPJs com dados detalhados para análise")
-		return nil, fmt.Errorf("erro ao enviar CNPJs: status %d, resposta: %s", resp.StatusCode, string(respBody))
+		return nil, &RespostaAPIError{Operacao: "enviar CNPJs", StatusCode: resp.StatusCode, Resposta: string(respBody)}
 	}
 
 	// Decodificar a resposta
 	var response EnviaCNPJsResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	err = vc.decodificaResposta("EnviaCNPJsComDadosParaAnalise", resp.Body, &response)
 	if err != nil {
 		vc.logger.WithError(err).Error("Erro ao decodificar JSON da resposta")
 		return nil, fmt.Errorf("erro no formato da resposta da API: %w", err)
@@ -307,6 +367,9 @@ func (vc *VaduClient) EnviaCNPJsComDadosParaAnalise(ctx context.Context, cnpjEmp
 		"response":   response,
 	}).Info("CNPJs enviados para análise detalhada com sucesso")
 
+	// Registrar a submissão na trilha de auditoria
+	vc.auditaSubmissao(ctx, requestBody, requestBody.CNPJEmpresa, idGrupoAnalise, documentos[1:], &response)
+
 	// Retornar a resposta da API
 	return &response, nil
 }
@@ -336,7 +399,7 @@ func (vc *VaduClient) PegaStatusAnalise(ctx context.Context, analiseID int, auth
 	}).Info("Consultando status da análise")
 
 	// Configurar contexto e timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	// Criar a requisição HTTP com o contexto configurado
@@ -353,7 +416,7 @@ func (vc *VaduClient) PegaStatusAnalise(ctx context.Context, analiseID int, auth
 	// Enviar a requisição utilizando o httpClient configurado na struct
 	var resp *http.Response
 	for attempt := 1; attempt <= 3; attempt++ {
-		resp, err = vc.httpClient.Do(req) // Usando httpClient da struct
+		resp, err = vc.do(req) // Usando httpClient da struct, respeitando o RateLimiter
 		if err != nil {
 			vc.logger.WithFields(logrus.Fields{
 				"attempt": attempt,
@@ -382,12 +445,12 @@ func (vc *VaduClient) PegaStatusAnalise(ctx context.Context, analiseID int, auth
 			"statusCode": resp.StatusCode,
 			"response":   string(respBody),
 		}).Error("Falha ao consultar status da análise")
-		return nil, fmt.Errorf("erro ao consultar status da análise: status %d, resposta: %s", resp.StatusCode, string(respBody))
+		return nil, &RespostaAPIError{Operacao: "consultar status da análise", StatusCode: resp.StatusCode, Resposta: string(respBody)}
 	}
 
 	// Decodificar a resposta
 	var status StatusAnalise
-	err = json.NewDecoder(resp.Body).Decode(&status)
+	err = vc.decodificaResposta("PegaStatusAnalise", resp.Body, &status)
 	if err != nil {
 		vc.logger.WithError(err).Error("Erro ao decodificar resposta da API")
 		return nil, fmt.Errorf("erro no formato da resposta da API: %w", err)
@@ -428,7 +491,7 @@ func (vc *VaduClient) PegaResumoAnalise(ctx context.Context, analiseID int, auth
 	}).Info("Consultando resumo da análise")
 
 	// Configurar contexto e timeout para a requisição
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	// Criar a requisição HTTP com o contexto configurado
@@ -444,7 +507,7 @@ func (vc *VaduClient) PegaResumoAnalise(ctx context.Context, analiseID int, auth
 	// Enviar a requisição utilizando o httpClient da struct
 	var resp *http.Response
 	for attempt := 1; attempt <= 3; attempt++ {
-		resp, err = vc.httpClient.Do(req) // Usando httpClient da struct
+		resp, err = vc.do(req) // Usando httpClient da struct, respeitando o RateLimiter
 		if err != nil {
 			vc.logger.WithFields(logrus.Fields{
 				"attempt": attempt,
@@ -475,12 +538,12 @@ func (vc *VaduClient) PegaResumoAnalise(ctx context.Context, analiseID int, auth
 			"statusCode": resp.StatusCode,
 			"response":   string(respBody),
 		}).Error("Falha ao consultar resumo da análise")
-		return nil, fmt.Errorf("erro ao consultar resumo da análise: status %d, resposta: %s", resp.StatusCode, string(respBody))
+		return nil, &RespostaAPIError{Operacao: "consultar resumo da análise", StatusCode: resp.StatusCode, Resposta: string(respBody)}
 	}
 
 	// Decodificar a resposta
 	var resumo ResumoAnalise
-	err = json.NewDecoder(resp.Body).Decode(&resumo)
+	err = vc.decodificaResposta("PegaResumoAnalise", resp.Body, &resumo)
 	if err != nil {
 		vc.logger.WithError(err).Error("Erro ao decodificar resposta da API")
 		return nil, fmt.Errorf("erro no formato da resposta da API: %w", err)
@@ -492,6 +555,15 @@ func (vc *VaduClient) PegaResumoAnalise(ctx context.Context, analiseID int, auth
 		"resumo":    resumo,
 	}).Info("Resumo da análise consultado com sucesso")
 
+	// Registrar o resumo na trilha de auditoria
+	vc.audita(ctx, AuditRecord{
+		Evento:         AuditEventoResumoAnalise,
+		AnaliseID:      resumo.AnaliseID,
+		CNPJEmpresa:    resumo.CNPJEmpresa,
+		IDGrupoAnalise: resumo.IDGrupoAnalise,
+		Usuario:        resumo.Usuario,
+	}, resumo)
+
 	// Retornar o resumo da análise
 	return &resumo, nil
 }
@@ -521,7 +593,7 @@ func (vc *VaduClient) ListaResumoCNPJs(ctx context.Context, analiseID int, auth
 	}).Info("Consultando resumo dos CNPJs para análise")
 
 	// Configurar contexto e timeout para a requisição
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	// Criar a requisição HTTP com o contexto configurado
@@ -537,7 +609,7 @@ func (vc *VaduClient) ListaResumoCNPJs(ctx context.Context, analiseID int, auth
 	// Enviar a requisição utilizando o httpClient da struct
 	var resp *http.Response
 	for attempt := 1; attempt <= 3; attempt++ {
-		resp, err = vc.httpClient.Do(req) // Usando httpClient da struct
+		resp, err = vc.do(req) // Usando httpClient da struct, respeitando o RateLimiter
 		if err != nil {
 			vc.logger.WithFields(logrus.Fields{
 				"attempt": attempt,
@@ -568,12 +640,12 @@ func (vc *VaduClient) ListaResumoCNPJs(ctx context.Context, analiseID int, auth
 			"statusCode": resp.StatusCode,
 			"response":   string(respBody),
 		}).Error("Falha ao consultar resumo dos CNPJs")
-		return nil, fmt.Errorf("erro ao consultar resumo dos CNPJs: status %d, resposta: %s", resp.StatusCode, string(respBody))
+		return nil, &RespostaAPIError{Operacao: "consultar resumo dos CNPJs", StatusCode: resp.StatusCode, Resposta: string(respBody)}
 	}
 
 	// Decodificar a resposta
 	var resumos []ResumoCNPJ
-	err = json.NewDecoder(resp.Body).Decode(&resumos)
+	err = vc.decodificaResposta("ListaResumoCNPJs", resp.Body, &resumos)
 	if err != nil {
 		vc.logger.WithError(err).Error("Erro ao decodificar resposta da API")
 		return nil, fmt.Errorf("erro no formato da resposta da API: %w", err)
@@ -585,14 +657,57 @@ func (vc *VaduClient) ListaResumoCNPJs(ctx context.Context, analiseID int, auth
 		"resumos":   resumos,
 	}).Info("Resumos dos CNPJs consultados com sucesso")
 
+	// Registrar os resultados dos documentos na trilha de auditoria
+	documentos := make([]Documento, 0, len(resumos))
+	for _, resumo := range resumos {
+		documentos = append(documentos, resumo.CNPJCPF)
+	}
+	vc.auditaResultados(ctx, analiseID, documentos, resumos)
+
 	// Retornar os resumos dos CNPJs
 	return resumos, nil
 }
 
 // ListaResumoCNPJsDetalhado busca os resumos detalhados dos CNPJs analisados para uma análise pelo ID fornecido,
 // com validação de entrada, resiliência, e logs detalhados.
-
+// Apenas os documentos com logs de erro ou alerta são retornados, contendo somente esses logs.
 func (vc *VaduClient) ListaResumoCNPJsDetalhado(ctx context.Context, analiseID int, auth AuthenticationInterface) ([]ResumoCNPJDatalhado, error) {
+	resumos, err := vc.buscaResumoCNPJsDetalhado(ctx, analiseID, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filtrar os resumos para retornar apenas logs com erro ou alerta
+	var filteredResumos []ResumoCNPJDatalhado
+	for _, resumo := range resumos {
+		var filteredLogs []LogAnalise
+		for _, log := range resumo.Logs {
+			if log.Erro || log.Alerta {
+				filteredLogs = append(filteredLogs, log)
+			}
+		}
+
+		// Se houver logs filtrados, adicionar o resumo à lista
+		if len(filteredLogs) > 0 {
+			resumo.Logs = filteredLogs
+			filteredResumos = append(filteredResumos, resumo)
+		}
+	}
+
+	// Log de sucesso com os dados filtrados
+	vc.logger.WithFields(logrus.Fields{
+		"analiseID":  analiseID,
+		"resumos":    filteredResumos,
+		"logs_count": len(filteredResumos),
+	}).Info("Consulta dos resumos detalhados bem-sucedida")
+
+	// Retornar os resumos detalhados filtrados
+	return filteredResumos, nil
+}
+
+// buscaResumoCNPJsDetalhado busca os resumos detalhados de todos os documentos da análise,
+// com todos os logs das regras, e os registra na trilha de auditoria.
+func (vc *VaduClient) buscaResumoCNPJsDetalhado(ctx context.Context, analiseID int, auth AuthenticationInterface) ([]ResumoCNPJDatalhado, error) {
 	// Obtenha o token dinamicamente
 	token, err := auth.Token(ctx)
 	if err != nil {
@@ -625,7 +740,7 @@ func (vc *VaduClient) ListaResumoCNPJsDetalhado(ctx context.Context, analiseID i
 	// Tentativas com backoff exponencial
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		// Criar a requisição HTTP
-		req, err := http.NewRequest(http.MethodGet, url, nil)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
 			vc.logger.WithError(err).Error("Erro ao criar requisição HTTP")
 			return nil, fmt.Errorf("erro ao criar requisição: %w", err)
@@ -635,7 +750,7 @@ func (vc *VaduClient) ListaResumoCNPJsDetalhado(ctx context.Context, analiseID i
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 
 		// Enviar a requisição usando o httpClient da struct
-		resp, err = vc.httpClient.Do(req) // Usando httpClient da struct
+		resp, err = vc.do(req) // Usando httpClient da struct, respeitando o RateLimiter
 		if err != nil {
 			vc.logger.WithFields(logrus.Fields{
 				"attempt": attempt,
@@ -669,40 +784,23 @@ func (vc *VaduClient) ListaResumoCNPJsDetalhado(ctx context.Context, analiseID i
 			"statusCode": resp.StatusCode,
 			"response":   string(respBody),
 		}).Error("Falha ao consultar resumo detalhado dos CNPJs")
-		return nil, fmt.Errorf("erro ao consultar resumo detalhado dos CNPJs: status %d, resposta: %s", resp.StatusCode, string(respBody))
+		return nil, &RespostaAPIError{Operacao: "consultar resumo detalhado dos CNPJs", StatusCode: resp.StatusCode, Resposta: string(respBody)}
 	}
 
 	// Decodificar a resposta da API
 	var resumos []ResumoCNPJDatalhado
-	if err := json.NewDecoder(resp.Body).Decode(&resumos); err != nil {
+	if err := vc.decodificaResposta("ListaResumoCNPJsDetalhado", resp.Body, &resumos); err != nil {
 		vc.logger.WithError(err).Error("Erro ao decodificar resposta da API")
 		return nil, fmt.Errorf("erro ao decodificar a resposta da API: %w", err)
 	}
 
-	// Filtrar os resumos para retornar apenas logs com erro ou alerta
-	var filteredResumos []ResumoCNPJDatalhado
+	// Registrar os resultados detalhados na trilha de auditoria
+	documentos := make([]Documento, 0, len(resumos))
 	for _, resumo := range resumos {
-		var filteredLogs []LogAnalise
-		for _, log := range resumo.Logs {
-			if log.Erro || log.Alerta {
-				filteredLogs = append(filteredLogs, log)
-			}
-		}
-
-		// Se houver logs filtrados, adicionar o resumo à lista
-		if len(filteredLogs) > 0 {
-			resumo.Logs = filteredLogs
-			filteredResumos = append(filteredResumos, resumo)
-		}
+		documentos = append(documentos, resumo.CNPJCPF)
 	}
+	vc.auditaResultados(ctx, analiseID, documentos, resumos)
 
-	// Log de sucesso com os dados filtrados
-	vc.logger.WithFields(logrus.Fields{
-		"analiseID":  analiseID,
-		"resumos":    filteredResumos,
-		"logs_count": len(filteredResumos),
-	}).Info("Consulta dos resumos detalhados bem-sucedida")
-
-	// Retornar os resumos detalhados filtrados
-	return filteredResumos, nil
+	// Retornar os resumos detalhados com todos os logs
+	return resumos, nil
 }
@@ -163,6 +163,60 @@ func (s *VaduClientTestSuite) TestEnviaCNPJsParaAnaliseErro() {
 	s.assert.Contains(err.Error(), "erro ao enviar CNPJs para análise")
 }
 
+// dadosIntegracaoExemplo retorna o payload de exemplo da Vadu, com contas e indicadores calculados por ela
+func dadosIntegracaoExemplo() vadu.DadosIntegracaoFloat {
+	return vadu.DadosIntegracaoFloat{
+		CNPJCPF:                       "98960887000164",
+		AtivoTotal:                    824167.11,
+		AtivoCirculante:               575997.67,
+		AtivoNaoCirculante:            248167.44,
+		AtivoRealizavelLongoPrazo:     248167.44,
+		DeducaoReceitaBruta:           744409.08,
+		DepreciacaoBens:               0,
+		Despesas:                      744409.08,
+		DisponivelCaixa:               470525.47,
+		Emprestimo:                    0,
+		EstoqueBalanco:                0,
+		LucroLiquido:                  2953887.03,
+		PassivoCirculante:             771911.23,
+		PassivoNaoCirculante:          0,
+		PassivoTotal:                  771911.23,
+		PatrimonioLiquido:             52255.88,
+		ReceitaLiquida:                4610049.5,
+		ReceitaBruta:                  5054759.41,
+		VendasLiquidas:                4610049.5,
+		ScoreExterno:                  761,
+		ProbabilidadeInadimplencia:    14,
+		DividasBaixasPrejuizo:         0,
+		QuantidadeInstituicoes:        0,
+		LimiteCreditoVencimentoAte360: 0,
+		CreditosVencerAte30Dias:       0,
+		Falencia:                      0,
+		ChequeSemFundos:               0,
+		FaturamentoMedioMensal:        384170.7917,
+		CapitalGiroSCR:                0,
+		CapitalGiroLiquido:            -195913.56,
+		CapitalGiroProprio:            -771909.23,
+		NecessidadeCapitalGiro:        0.16147529,
+		LiquidezCorrente:              0.746196774,
+		LiquidezSeca:                  0.746196774,
+		LiquidezGeral:                 1.067696748,
+		LiquidezImediata:              0.609559042,
+		GrauSolvencia:                 3.826718559,
+		Endividamento:                 0.936595529,
+		DependenciaRecursosTerceiros:  14.77175832,
+		EndividamentoCurtoPrazo:       0.936595529,
+		NivelImobilizacao:             4.749081634,
+		GrauDependenciaBancaria:       0,
+		RetornoPatrimonioLiquidoROE:   56.52736171,
+		GiroAtivo:                     67.12303043,
+		RetornoSobreAtivoRAO:          3.584087492,
+		RetornoSobreVendas:            0.640749526,
+		MargemOperacional:             4310350.33,
+		RatingExterno:                 "A",
+	}
+}
+
 // TestEnviaCNPJsComDadosParaAnalise
 func (s *VaduClientTestSuite) TestEnviaCNPJsComDadosParaAnalise() {
 	// Usando o mock de EnviaCNPJsComDadosParaAnalise
@@ -173,59 +227,12 @@ func (s *VaduClientTestSuite) TestEnviaCNPJsComDadosParaAnalise() {
 	authentication := new(mock.MockAuthentication)
 	authentication.On("Token", s.ctx).Return("mocked_token", nil)
 
-	// Definir os dados para envio (considerando o layout correto)
-	listaDados := []vadu.DadosIntegracao{
-		{
-			CNPJCPF:                       "98960887000164",
-			AtivoTotal:                    824167.11,
-			AtivoCirculante:               575997.67,
-			AtivoNaoCirculante:            248167.44,
-			AtivoRealizavelLongoPrazo:     248167.44,
-			DeducaoReceitaBruta:           744409.08,
-			DepreciacaoBens:               0,
-			Despesas:                      744409.08,
-			DisponivelCaixa:               470525.47,
-			Emprestimo:                    0,
-			EstoqueBalanco:                0,
-			LucroLiquido:                  2953887.03,
-			PassivoCirculante:             771911.23,
-			PassivoNaoCirculante:          0,
-			PassivoTotal:                  771911.23,
-			PatrimonioLiquido:             52255.88,
-			ReceitaLiquida:                4610049.5,
-			ReceitaBruta:                  5054759.41,
-			VendasLiquidas:                4610049.5,
-			ScoreExterno:                  761,
-			ProbabilidadeInadimplencia:    14,
-			DividasBaixasPrejuizo:         0,
-			QuantidadeInstituicoes:        0,
-			LimiteCreditoVencimentoAte360: 0,
-			CreditosVencerAte30Dias:       0,
-			Falencia:                      0,
-			ChequeSemFundos:               0,
-			FaturamentoMedioMensal:        384170.7917,
-			CapitalGiroSCR:                0,
-			CapitalGiroLiquido:            -195913.56,
-			CapitalGiroProprio:            -771909.23,
-			NecessidadeCapitalGiro:        0.16147529,
-			LiquidezCorrente:              0.746196774,
-			LiquidezSeca:                  0.746196774,
-			LiquidezGeral:                 1.067696748,
-			LiquidezImediata:              0.609559042,
-			GrauSolvencia:                 3.826718559,
-			Endividamento:                 0.936595529,
-			DependenciaRecursosTerceiros:  14.77175832,
-			EndividamentoCurtoPrazo:       0.936595529,
-			NivelImobilizacao:             4.749081634,
-			GrauDependenciaBancaria:       0,
-			RetornoPatrimonioLiquidoROE:   56.52736171,
-			GiroAtivo:                     67.12303043,
-			RetornoSobreAtivoRAO:          3.584087492,
-			RetornoSobreVendas:            0.640749526,
-			MargemOperacional:             4310350.33,
-			RatingExterno:                 "A",
-		},
-	}
+	// Definir os dados para envio (considerando o layout correto), convertendo a partir de float64
+	dadosFloat := dadosIntegracaoExemplo()
+	dados, err := dadosFloat.DadosIntegracao()
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.MustDecimal("824167.11"), dados.AtivoTotal)
+	listaDados := []vadu.DadosIntegracao{dados}
 
 	// Chama a função EnviaCNPJsComDadosParaAnalise
 	response, err := s.vaduClient.EnviaCNPJsComDadosParaAnalise(s.ctx, "33011770000199", 10802, listaDados, nil, authentication)
@@ -284,7 +291,7 @@ func (s *VaduClientTestSuite) TestPegaResumoAnalise() {
 	s.assert.NotNil(resumo)
 	s.assert.Equal(4768906, resumo.AnaliseID)
 	s.assert.Equal(1, resumo.QuantidadeCNPJ)
-	s.assert.Equal("33011770000199", resumo.CNPJEmpresa)
+	s.assert.Equal(vadu.Documento("33011770000199"), resumo.CNPJEmpresa)
 	s.assert.True(resumo.Concluido)
 	s.assert.False(resumo.Erro)
 	s.assert.True(resumo.Alerta)
@@ -313,7 +320,7 @@ func (s *VaduClientTestSuite) TestListaResumoCNPJs() {
 	s.assert.Len(resumos, 1) // Verifica que existe um resumo
 	s.assert.Equal(4768906, resumos[0].AnaliseID)
 	s.assert.Equal(28883956, resumos[0].AnaliseCNPJCPFID)
-	s.assert.Equal("98960887000164", resumos[0].CNPJCPF)
+	s.assert.Equal(vadu.Documento("98960887000164"), resumos[0].CNPJCPF)
 	s.assert.Equal("WEBSOLUTIONS LTDA", resumos[0].Nome)
 	s.assert.False(resumos[0].Erro)
 	s.assert.True(resumos[0].Alerta)
@@ -322,7 +329,7 @@ func (s *VaduClientTestSuite) TestListaResumoCNPJs() {
 	s.assert.Equal("B (500)", resumos[0].RatingSigla)
 	s.assert.Equal("CCB--> 40% do faturamento mensal || ANT--> 20% do faturamento mensal", resumos[0].RatingDescricao)
 	s.assert.False(resumos[0].NovaConsultaSerasa)
-	s.assert.Equal("", resumos[0].NovaConsultaSerasaString)
+	s.assert.Equal(vadu.NewNullString(""), resumos[0].NovaConsultaSerasaString)
 	s.assert.Equal("Consulta Serasa", resumos[0].OrigemConsultaSerasaTexto)
 }
 
@@ -346,7 +353,7 @@ func (s *VaduClientTestSuite) TestListaResumoCNPJsDetalhado() {
 	s.assert.NotNil(resumos)
 	s.assert.Len(resumos, 1)
 	s.assert.Equal(4768906, resumos[0].AnaliseID)
-	s.assert.Equal("98960887000164", resumos[0].CNPJCPF)
+	s.assert.Equal(vadu.Documento("98960887000164"), resumos[0].CNPJCPF)
 	s.assert.Equal("WEBSOLUTIONS LTDA", resumos[0].Nome)
 	s.assert.False(resumos[0].Erro)
 	s.assert.True(resumos[0].Alerta)
@@ -355,4 +362,6 @@ func (s *VaduClientTestSuite) TestListaResumoCNPJsDetalhado() {
 	s.assert.Len(resumos[0].Logs, 1)
 	s.assert.Equal("Análise CNPJs", resumos[0].Logs[0].AnaliseDescricao)
 	s.assert.Equal("Cadastro 14 - Análise Sócios - 03", resumos[0].Logs[0].RegraDescricao)
+	s.assert.False(resumos[0].Logs[0].ErroConsulta.Valido)
+	s.assert.False(resumos[0].NovaConsultaSerasaString.Valido)
 }
@@ -0,0 +1,56 @@
+// Comando vadu-audit-verify verifica a integridade de uma trilha de auditoria
+// gravada em arquivo pelo FileAuditSink.
+//
+// Apenas trilhas em arquivo são suportadas. Trilhas gravadas pelo SQLAuditSink devem ser
+// verificadas pela aplicação, que registra o driver do banco, com
+// vadu.VerifyAuditTrailHead(ctx, vadu.NewSQLAuditSink(db, tabela), esperado).
+//
+// A cadeia de hashes não detecta registros removidos do final da trilha. Informe com
+// -registros e -hash o último registro conhecido (vadu.AuditTrail.Head, guardado fora do
+// arquivo) para detectar truncamentos.
+//
+// Uso:
+//
+//	vadu-audit-verify -arquivo auditoria.jsonl [-registros 42 -hash 3f5a...]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/contbank/vadu-sdk"
+)
+
+func main() {
+	arquivo := flag.String("arquivo", "", "caminho do arquivo JSON Lines da trilha de auditoria")
+	registros := flag.Int("registros", 0, "quantidade de registros do último registro conhecido (opcional)")
+	hash := flag.String("hash", "", "hash do último registro conhecido (obrigatório com -registros)")
+	flag.Parse()
+
+	if *arquivo == "" {
+		fmt.Fprintln(os.Stderr, "informe o arquivo da trilha de auditoria com -arquivo")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if (*registros > 0) != (*hash != "") {
+		fmt.Fprintln(os.Stderr, "informe -registros e -hash juntos")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if _, err := os.Stat(*arquivo); err != nil {
+		fmt.Fprintf(os.Stderr, "erro ao abrir a trilha de auditoria: %v\n", err)
+		os.Exit(2)
+	}
+
+	esperado := vadu.AuditHead{Registros: *registros, Hash: *hash}
+	head, err := vadu.VerifyAuditTrailHead(context.Background(), vadu.NewFileAuditSink(*arquivo), esperado)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trilha inválida após %d registros íntegros: %v\n", head.Registros, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("trilha íntegra: %d registros verificados, último hash %s\n", head.Registros, head.Hash)
+}
@@ -0,0 +1,336 @@
+package vadu
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Severidade classifica os achados da validação de consistência.
+type Severidade int
+
+const (
+	// SeveridadeInfo indica uma observação que não compromete a análise.
+	SeveridadeInfo Severidade = iota
+	// SeveridadeAlerta indica um valor suspeito que pode distorcer o rating.
+	SeveridadeAlerta
+	// SeveridadeErro indica dados incoerentes entre si.
+	SeveridadeErro
+)
+
+// String retorna o nome da severidade.
+func (s Severidade) String() string {
+	switch s {
+	case SeveridadeInfo:
+		return "info"
+	case SeveridadeAlerta:
+		return "alerta"
+	case SeveridadeErro:
+		return "erro"
+	default:
+		return fmt.Sprintf("Severidade(%d)", int(s))
+	}
+}
+
+// ConsistencyPolicy define se o cliente rejeita envios com dados inconsistentes.
+type ConsistencyPolicy int
+
+const (
+	// ConsistencyPolicyIgnore envia os dados sem validar a consistência (padrão).
+	ConsistencyPolicyIgnore ConsistencyPolicy = iota
+	// ConsistencyPolicyRejectErrors rejeita o envio quando há achados com SeveridadeErro.
+	ConsistencyPolicyRejectErrors
+	// ConsistencyPolicyRejectWarnings rejeita o envio quando há achados com SeveridadeAlerta ou SeveridadeErro.
+	ConsistencyPolicyRejectWarnings
+)
+
+// String retorna o nome da política.
+func (p ConsistencyPolicy) String() string {
+	switch p {
+	case ConsistencyPolicyIgnore:
+		return "ignore"
+	case ConsistencyPolicyRejectErrors:
+		return "reject_errors"
+	case ConsistencyPolicyRejectWarnings:
+		return "reject_warnings"
+	default:
+		return fmt.Sprintf("ConsistencyPolicy(%d)", int(p))
+	}
+}
+
+// severidadeMinima retorna a menor severidade que causa a rejeição do envio.
+func (p ConsistencyPolicy) severidadeMinima() (Severidade, bool) {
+	switch p {
+	case ConsistencyPolicyRejectErrors:
+		return SeveridadeErro, true
+	case ConsistencyPolicyRejectWarnings:
+		return SeveridadeAlerta, true
+	default:
+		return 0, false
+	}
+}
+
+// ErrDadosInconsistentes indica que os dados de integração foram rejeitados pela validação de consistência.
+var ErrDadosInconsistentes = errors.New("dados de integração inconsistentes")
+
+// ToleranciaConsistenciaPadrao é a diferença relativa aceita entre valores que deveriam coincidir.
+const ToleranciaConsistenciaPadrao = 0.01
+
+// AchadoConsistencia descreve um problema encontrado em um campo de DadosIntegracao.
+type AchadoConsistencia struct {
+	Campo      string     // Nome JSON do campo em DadosIntegracao
+	Severidade Severidade // Gravidade do problema
+	Mensagem   string     // Descrição do problema
+	Informado  Decimal    // Valor presente nos dados
+	Esperado   Decimal    // Valor esperado, quando aplicável
+}
+
+// RelatorioConsistencia reúne os achados da validação de um DadosIntegracao.
+type RelatorioConsistencia struct {
+	CNPJCPF Documento
+	Achados []AchadoConsistencia
+}
+
+// MaiorSeveridade retorna a maior severidade entre os achados e false se não houver achados.
+func (r RelatorioConsistencia) MaiorSeveridade() (Severidade, bool) {
+	if len(r.Achados) == 0 {
+		return 0, false
+	}
+	maior := SeveridadeInfo
+	for _, achado := range r.Achados {
+		if achado.Severidade > maior {
+			maior = achado.Severidade
+		}
+	}
+	return maior, true
+}
+
+// Filtra retorna os achados com severidade igual ou superior à informada.
+func (r RelatorioConsistencia) Filtra(minima Severidade) []AchadoConsistencia {
+	var achados []AchadoConsistencia
+	for _, achado := range r.Achados {
+		if achado.Severidade >= minima {
+			achados = append(achados, achado)
+		}
+	}
+	return achados
+}
+
+// Valido informa se não há achados com SeveridadeErro.
+func (r RelatorioConsistencia) Valido() bool {
+	return len(r.Filtra(SeveridadeErro)) == 0
+}
+
+// ConsistenciaOptions configura a validação de consistência.
+type ConsistenciaOptions struct {
+	Tolerancia float64 // Diferença relativa aceita (padrão ToleranciaConsistenciaPadrao)
+}
+
+// ConsistenciaError é retornado quando o envio é rejeitado pela ConsistencyPolicy.
+type ConsistenciaError struct {
+	Relatorios []RelatorioConsistencia // Relatórios dos documentos rejeitados
+	Minima     Severidade              // Severidade mínima que causou a rejeição
+}
+
+// Error lista os documentos e campos rejeitados.
+func (e *ConsistenciaError) Error() string {
+	var motivos []string
+	for _, relatorio := range e.Relatorios {
+		var campos []string
+		for _, achado := range relatorio.Filtra(e.Minima) {
+			campos = append(campos, achado.Campo)
+		}
+		motivos = append(motivos, fmt.Sprintf("%s [%s]", relatorio.CNPJCPF, strings.Join(campos, ", ")))
+	}
+	return fmt.Sprintf("%s: %s", ErrDadosInconsistentes.Error(), strings.Join(motivos, "; "))
+}
+
+// Is permite comparar o erro com ErrDadosInconsistentes via errors.Is.
+func (e *ConsistenciaError) Is(target error) bool {
+	return target == ErrDadosInconsistentes
+}
+
+// verificadorConsistencia acumula os achados da validação de um DadosIntegracao.
+type verificadorConsistencia struct {
+	tolerancia float64
+	achados    []AchadoConsistencia
+}
+
+func (v *verificadorConsistencia) adiciona(campo string, severidade Severidade, informado, esperado Decimal, formato string, args ...interface{}) {
+	v.achados = append(v.achados, AchadoConsistencia{
+		Campo:      campo,
+		Severidade: severidade,
+		Mensagem:   fmt.Sprintf(formato, args...),
+		Informado:  informado,
+		Esperado:   esperado,
+	})
+}
+
+// coincide compara dois valores com tolerância relativa ao maior deles.
+func (v *verificadorConsistencia) coincide(a, b Decimal) bool {
+	escala := math.Max(math.Abs(a.Float64()), math.Abs(b.Float64()))
+	return math.Abs(a.Sub(b).Float64()) <= v.tolerancia*escala
+}
+
+// naoNegativo registra um erro para contas que não podem ser negativas.
+func (v *verificadorConsistencia) naoNegativo(campo string, valor Decimal) {
+	if valor.Sign() < 0 {
+		v.adiciona(campo, SeveridadeErro, valor, Decimal{}, "%s não pode ser negativo", campo)
+	}
+}
+
+// ValidaDadosIntegracao verifica a coerência interna de um DadosIntegracao: documento, sinais das
+// contas, equação patrimonial, relação entre receitas e a concordância entre os indicadores
+// informados e os recalculados a partir das contas (com as fórmulas de FormulasIndicadores).
+// Contas zeradas são valores válidos; um indicador só deixa de ser verificado quando o denominador é zero.
+func ValidaDadosIntegracao(dados DadosIntegracao, opts ConsistenciaOptions) RelatorioConsistencia {
+	v := &verificadorConsistencia{tolerancia: opts.Tolerancia}
+	if v.tolerancia <= 0 {
+		v.tolerancia = ToleranciaConsistenciaPadrao
+	}
+	documento := Documento(NormalizaDocumento(string(dados.CNPJCPF)))
+
+	if err := documento.Valida(); err != nil {
+		v.adiciona("cnpjcpf", SeveridadeErro, Decimal{}, Decimal{}, "documento inválido: %v", err)
+	}
+
+	// Contas que não podem ser negativas
+	for _, conta := range []struct {
+		campo string
+		valor Decimal
+	}{
+		{"ativoTotal", dados.AtivoTotal},
+		{"ativoCirculante", dados.AtivoCirculante},
+		{"ativoNaoCirculante", dados.AtivoNaoCirculante},
+		{"ativoRealizavelLongoPrazo", dados.AtivoRealizavelLongoPrazo},
+		{"disponivelCaixa", dados.DisponivelCaixa},
+		{"estoqueBalanco", dados.EstoqueBalanco},
+		{"passivoCirculante", dados.PassivoCirculante},
+		{"passivoNaoCirculante", dados.PassivoNaoCirculante},
+		{"passivoTotal", dados.PassivoTotal},
+		{"emprestimo", dados.Emprestimo},
+		{"receitaBruta", dados.ReceitaBruta},
+		{"receitaLiquida", dados.ReceitaLiquida},
+		{"vendasLiquidas", dados.VendasLiquidas},
+		{"deducaoReceitaBruta", dados.DeducaoReceitaBruta},
+		{"faturamentoMedioMensal", dados.FaturamentoMedioMensal},
+	} {
+		v.naoNegativo(conta.campo, conta.valor)
+	}
+
+	// Equação patrimonial
+	if !dados.AtivoTotal.IsZero() && (!dados.AtivoCirculante.IsZero() || !dados.AtivoNaoCirculante.IsZero()) {
+		soma := dados.AtivoCirculante.Add(dados.AtivoNaoCirculante)
+		if !v.coincide(dados.AtivoTotal, soma) {
+			v.adiciona("ativoTotal", SeveridadeErro, dados.AtivoTotal, soma,
+				"ativoTotal difere de ativoCirculante + ativoNaoCirculante")
+		}
+	}
+	if dados.AtivoRealizavelLongoPrazo.Sign() > 0 && dados.AtivoNaoCirculante.Sign() > 0 &&
+		dados.AtivoRealizavelLongoPrazo.Cmp(dados.AtivoNaoCirculante) > 0 && !v.coincide(dados.AtivoRealizavelLongoPrazo, dados.AtivoNaoCirculante) {
+		v.adiciona("ativoRealizavelLongoPrazo", SeveridadeAlerta, dados.AtivoRealizavelLongoPrazo, dados.AtivoNaoCirculante,
+			"ativoRealizavelLongoPrazo maior que ativoNaoCirculante")
+	}
+	if !dados.PassivoTotal.IsZero() && (!dados.PassivoCirculante.IsZero() || !dados.PassivoNaoCirculante.IsZero()) {
+		// O passivo total pode ser informado com ou sem o patrimônio líquido
+		terceiros := dados.PassivoCirculante.Add(dados.PassivoNaoCirculante)
+		comPL := terceiros.Add(dados.PatrimonioLiquido)
+		if !v.coincide(dados.PassivoTotal, terceiros) && !v.coincide(dados.PassivoTotal, comPL) {
+			v.adiciona("passivoTotal", SeveridadeErro, dados.PassivoTotal, comPL,
+				"passivoTotal difere de passivoCirculante + passivoNaoCirculante (com ou sem patrimonioLiquido)")
+		}
+	}
+	if !dados.AtivoTotal.IsZero() && !dados.PatrimonioLiquido.IsZero() &&
+		(!dados.PassivoCirculante.IsZero() || !dados.PassivoNaoCirculante.IsZero()) {
+		passivoMaisPL := dados.PassivoCirculante.Add(dados.PassivoNaoCirculante).Add(dados.PatrimonioLiquido)
+		if !v.coincide(dados.AtivoTotal, passivoMaisPL) {
+			v.adiciona("patrimonioLiquido", SeveridadeErro, dados.AtivoTotal, passivoMaisPL,
+				"ativoTotal difere de passivoCirculante + passivoNaoCirculante + patrimonioLiquido")
+		}
+	}
+
+	// Receitas
+	if dados.ReceitaBruta.Sign() > 0 && dados.ReceitaLiquida.Cmp(dados.ReceitaBruta) > 0 && !v.coincide(dados.ReceitaLiquida, dados.ReceitaBruta) {
+		v.adiciona("receitaLiquida", SeveridadeAlerta, dados.ReceitaLiquida, dados.ReceitaBruta,
+			"receitaLiquida maior que receitaBruta")
+	}
+	if !dados.ReceitaBruta.IsZero() && !dados.DeducaoReceitaBruta.IsZero() && !dados.ReceitaLiquida.IsZero() {
+		esperada := dados.ReceitaBruta.Sub(dados.DeducaoReceitaBruta)
+		if !v.coincide(dados.ReceitaLiquida, esperada) {
+			v.adiciona("receitaLiquida", SeveridadeAlerta, dados.ReceitaLiquida, esperada,
+				"receitaLiquida difere de receitaBruta - deducaoReceitaBruta")
+		}
+	}
+
+	// Indicadores recalculados a partir das contas informadas
+	balanco := Balanco{
+		AtivoTotal:                DecimalPtr(dados.AtivoTotal),
+		AtivoCirculante:           DecimalPtr(dados.AtivoCirculante),
+		AtivoNaoCirculante:        DecimalPtr(dados.AtivoNaoCirculante),
+		AtivoRealizavelLongoPrazo: DecimalPtr(dados.AtivoRealizavelLongoPrazo),
+		DisponivelCaixa:           DecimalPtr(dados.DisponivelCaixa),
+		Estoques:                  DecimalPtr(dados.EstoqueBalanco),
+		PassivoCirculante:         DecimalPtr(dados.PassivoCirculante),
+		PassivoNaoCirculante:      DecimalPtr(dados.PassivoNaoCirculante),
+		PatrimonioLiquido:         DecimalPtr(dados.PatrimonioLiquido),
+		Emprestimos:               DecimalPtr(dados.Emprestimo),
+	}
+	dre := DRE{
+		ReceitaBruta:   DecimalPtr(dados.ReceitaBruta),
+		ReceitaLiquida: DecimalPtr(dados.ReceitaLiquida),
+		Despesas:       DecimalPtr(dados.Despesas),
+		LucroLiquido:   DecimalPtr(dados.LucroLiquido),
+	}
+	copia := dados
+	for _, ind := range indicadores {
+		if ind.campo == "faturamentoMedioMensal" {
+			// O período da DRE não é conhecido
+			continue
+		}
+		c := &calculo{balanco: balanco, dre: dre}
+		esperado := ind.calcula(c)
+		if c.err != nil {
+			// Denominador zero: o indicador não é definido
+			continue
+		}
+		valor := *ind.destino(&copia)
+		switch {
+		case valor.IsZero() && !esperado.IsZero():
+			v.adiciona(ind.campo, SeveridadeInfo, valor, esperado,
+				"%s não informado; valor calculado a partir das contas: %s (%s)", ind.campo, esperado.Round(4), ind.formula)
+		case !v.coincide(valor, esperado):
+			v.adiciona(ind.campo, SeveridadeErro, valor, esperado,
+				"%s difere do valor calculado a partir das contas: %s (%s)", ind.campo, esperado.Round(4), ind.formula)
+		}
+	}
+
+	return RelatorioConsistencia{CNPJCPF: documento, Achados: v.achados}
+}
+
+// aplicaConsistencyPolicy valida os dados de integração conforme a ConsistencyPolicy da sessão.
+func (vc *VaduClient) aplicaConsistencyPolicy(listaDados []DadosIntegracao) error {
+	minima, rejeita := vc.session.ConsistencyPolicy.severidadeMinima()
+	if !rejeita {
+		return nil
+	}
+
+	var rejeitados []RelatorioConsistencia
+	for _, dados := range listaDados {
+		relatorio := ValidaDadosIntegracao(dados, ConsistenciaOptions{})
+		if len(relatorio.Filtra(minima)) > 0 {
+			rejeitados = append(rejeitados, relatorio)
+		}
+	}
+	if len(rejeitados) == 0 {
+		return nil
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"policy":     vc.session.ConsistencyPolicy.String(),
+		"rejeitados": len(rejeitados),
+	}).Error("Dados de integração rejeitados na validação de consistência")
+	return &ConsistenciaError{Relatorios: rejeitados, Minima: minima}
+}
@@ -0,0 +1,145 @@
+package vadu_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConsistenciaTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestConsistenciaTestSuite(t *testing.T) {
+	suite.Run(t, new(ConsistenciaTestSuite))
+}
+
+func (s *ConsistenciaTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+func (s *ConsistenciaTestSuite) dadosConsistentes() vadu.DadosIntegracao {
+	dados, err := vadu.NewDadosIntegracaoBuilder("98960887000164").
+		ComBalanco(balancoCompleto()).
+		ComDRE(dreCompleta()).
+		Build()
+	s.assert.NoError(err)
+	return dados
+}
+
+func campos(achados []vadu.AchadoConsistencia) []string {
+	var nomes []string
+	for _, achado := range achados {
+		nomes = append(nomes, achado.Campo)
+	}
+	return nomes
+}
+
+// TestValidaDadosIntegracao verifica os achados de cada tipo de inconsistência
+func (s *ConsistenciaTestSuite) TestValidaDadosIntegracao() {
+	relatorio := vadu.ValidaDadosIntegracao(s.dadosConsistentes(), vadu.ConsistenciaOptions{})
+	s.assert.Empty(relatorio.Achados)
+	s.assert.True(relatorio.Valido())
+
+	dados := s.dadosConsistentes()
+	dados.AtivoTotal = vadu.MustDecimal("1200")
+	dados.ReceitaBruta = vadu.MustDecimal("-10")
+	dados.ReceitaLiquida = vadu.MustDecimal("2500")
+	dados.LiquidezCorrente = vadu.MustDecimal("3.5")
+	dados.MargemOperacional = vadu.Decimal{}
+	relatorio = vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{})
+	s.assert.False(relatorio.Valido())
+
+	maior, ok := relatorio.MaiorSeveridade()
+	s.assert.True(ok)
+	s.assert.Equal(vadu.SeveridadeErro, maior)
+
+	erros := campos(relatorio.Filtra(vadu.SeveridadeErro))
+	s.assert.Contains(erros, "ativoTotal")
+	s.assert.Contains(erros, "receitaBruta")
+	s.assert.Contains(erros, "patrimonioLiquido")
+	s.assert.Contains(erros, "liquidezCorrente")
+	s.assert.Contains(erros, "endividamento")
+	s.assert.Contains(campos(relatorio.Filtra(vadu.SeveridadeAlerta)), "receitaLiquida")
+	s.assert.NotContains(campos(relatorio.Filtra(vadu.SeveridadeAlerta)), "margemOperacional")
+
+	// Pequenas diferenças de arredondamento são toleradas
+	dados = s.dadosConsistentes()
+	dados.LiquidezSeca = vadu.MustDecimal("1.333")
+	s.assert.Empty(vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{}).Achados)
+	s.assert.NotEmpty(vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{Tolerancia: 0.00001}).Achados)
+
+	dados = s.dadosConsistentes()
+	dados.CNPJCPF = "98960887000100"
+	s.assert.Equal([]string{"cnpjcpf"}, campos(vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{}).Achados))
+}
+
+// TestValidaExemploVadu verifica o payload de exemplo da Vadu, que tem passivoNaoCirculante zerado
+func (s *ConsistenciaTestSuite) TestValidaExemploVadu() {
+	dados, err := dadosIntegracaoExemplo().DadosIntegracao()
+	s.assert.NoError(err)
+
+	relatorio := vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{})
+	s.assert.True(relatorio.Valido())
+	s.assert.Empty(relatorio.Filtra(vadu.SeveridadeErro))
+	// A receita líquida do exemplo não corresponde a receitaBruta - deducaoReceitaBruta
+	s.assert.Equal([]string{"receitaLiquida"}, campos(relatorio.Achados))
+
+	// Contas zeradas continuam participando das verificações
+	dados.GrauSolvencia = vadu.MustDecimal("1")
+	dados.LiquidezGeral = vadu.MustDecimal("1.5")
+	erros := campos(vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{}).Filtra(vadu.SeveridadeErro))
+	s.assert.ElementsMatch([]string{"grauSolvencia", "liquidezGeral"}, erros)
+
+	// Indicadores com denominador zero não são verificados
+	dados.PassivoCirculante = vadu.Decimal{}
+	dados.PassivoTotal = vadu.Decimal{}
+	dados.AtivoTotal = dados.PatrimonioLiquido
+	dados.AtivoCirculante = vadu.Decimal{}
+	dados.AtivoNaoCirculante = dados.PatrimonioLiquido
+	dados.CapitalGiroLiquido = vadu.Decimal{}
+	relatorio = vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{})
+	s.assert.NotContains(campos(relatorio.Achados), "grauSolvencia")
+	s.assert.NotContains(campos(relatorio.Achados), "liquidezGeral")
+	s.assert.NotContains(campos(relatorio.Achados), "liquidezCorrente")
+}
+
+// TestConsistencyPolicy verifica a rejeição do envio conforme a política configurada
+func (s *ConsistenciaTestSuite) TestConsistencyPolicy() {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	ctx := context.Background()
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", ctx).Return("mocked_token", nil)
+
+	inconsistente := s.dadosConsistentes()
+	inconsistente.ReceitaLiquida = vadu.MustDecimal("2500")
+
+	envia := func(policy vadu.ConsistencyPolicy, dados vadu.DadosIntegracao) error {
+		session, err := vadu.NewSession(vadu.Config{ConsistencyPolicy: &policy})
+		s.assert.NoError(err)
+		vaduClient := vadu.NewVaduClient(mock.EnviaCNPJsParaAnaliseMock(), *session, logger)
+		_, err = vaduClient.EnviaCNPJsComDadosParaAnalise(ctx, "33011770000199", 7064, []vadu.DadosIntegracao{dados}, nil, authentication)
+		return err
+	}
+
+	s.assert.NoError(envia(vadu.ConsistencyPolicyIgnore, inconsistente))
+	s.assert.NoError(envia(vadu.ConsistencyPolicyRejectErrors, s.dadosConsistentes()))
+	exemplo, err := dadosIntegracaoExemplo().DadosIntegracao()
+	s.assert.NoError(err)
+	s.assert.NoError(envia(vadu.ConsistencyPolicyRejectErrors, exemplo))
+
+	err = envia(vadu.ConsistencyPolicyRejectWarnings, inconsistente)
+	s.assert.ErrorIs(err, vadu.ErrDadosInconsistentes)
+	var consistenciaErr *vadu.ConsistenciaError
+	s.assert.True(errors.As(err, &consistenciaErr))
+	s.assert.Equal(vadu.Documento("98960887000164"), consistenciaErr.Relatorios[0].CNPJCPF)
+}
@@ -4,3 +4,18 @@ package vadu
 func String(v string) *string {
 	return &v
 }
+
+// Bool returns a pointer to the bool value passed in.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Float64 returns a pointer to the float64 value passed in.
+func Float64(v float64) *float64 {
+	return &v
+}
+
+// DecimalPtr returns a pointer to the Decimal value passed in.
+func DecimalPtr(v Decimal) *Decimal {
+	return &v
+}
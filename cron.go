@@ -0,0 +1,166 @@
+package vadu
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCronInvalido indica uma expressão de agendamento inválida.
+var ErrCronInvalido = errors.New("expressão cron inválida")
+
+// descritoresCron são os atalhos aceitos por ParseCron.
+var descritoresCron = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron é uma expressão de agendamento no formato de cinco campos: minuto (0-59), hora (0-23),
+// dia do mês (1-31), mês (1-12) e dia da semana (0-6, domingo = 0 ou 7).
+type Cron struct {
+	expressao  string
+	minutos    uint64
+	horas      uint64
+	dias       uint64
+	meses      uint64
+	diasSemana uint64
+	// Quando dia do mês e dia da semana são restritos, basta que um deles corresponda
+	diaRestrito, semanaRestrita bool
+}
+
+// ParseCron interpreta uma expressão cron de cinco campos. Cada campo aceita *, valores,
+// intervalos (1-5), listas (1,15) e passos (*/15, 1-31/2). São aceitos também os atalhos
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight e @hourly.
+func ParseCron(expressao string) (*Cron, error) {
+	campos := strings.Fields(expressao)
+	if len(campos) == 1 {
+		if equivalente, ok := descritoresCron[strings.ToLower(campos[0])]; ok {
+			campos = strings.Fields(equivalente)
+		}
+	}
+	if len(campos) != 5 {
+		return nil, fmt.Errorf("%w: %q deve ter 5 campos", ErrCronInvalido, expressao)
+	}
+
+	c := &Cron{expressao: expressao}
+	limites := []struct {
+		destino  *uint64
+		min, max int
+		nome     string
+	}{
+		{&c.minutos, 0, 59, "minuto"},
+		{&c.horas, 0, 23, "hora"},
+		{&c.dias, 1, 31, "dia do mês"},
+		{&c.meses, 1, 12, "mês"},
+		{&c.diasSemana, 0, 7, "dia da semana"},
+	}
+	for i, limite := range limites {
+		bits, err := parseCampoCron(campos[i], limite.min, limite.max)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q, campo %s: %v", ErrCronInvalido, expressao, limite.nome, err)
+		}
+		*limite.destino = bits
+	}
+	// Domingo pode ser informado como 0 ou 7
+	if c.diasSemana&(1<<7) != 0 {
+		c.diasSemana = c.diasSemana&^(1<<7) | 1
+	}
+	c.diaRestrito = campos[2] != "*"
+	c.semanaRestrita = campos[4] != "*"
+	return c, nil
+}
+
+// parseCampoCron converte um campo em um conjunto de bits com os valores aceitos.
+func parseCampoCron(campo string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, parte := range strings.Split(campo, ",") {
+		intervalo, passo := parte, 1
+		if i := strings.Index(parte, "/"); i >= 0 {
+			p, err := strconv.Atoi(parte[i+1:])
+			if err != nil || p <= 0 {
+				return 0, fmt.Errorf("passo inválido em %q", parte)
+			}
+			intervalo, passo = parte[:i], p
+		}
+
+		inicio, fim := min, max
+		switch {
+		case intervalo == "*":
+		case strings.Contains(intervalo, "-"):
+			limites := strings.SplitN(intervalo, "-", 2)
+			var err1, err2 error
+			inicio, err1 = strconv.Atoi(limites[0])
+			fim, err2 = strconv.Atoi(limites[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("intervalo inválido %q", intervalo)
+			}
+		default:
+			valor, err := strconv.Atoi(intervalo)
+			if err != nil {
+				return 0, fmt.Errorf("valor inválido %q", intervalo)
+			}
+			inicio, fim = valor, valor
+			if strings.Contains(parte, "/") {
+				fim = max
+			}
+		}
+		if inicio < min || fim > max || inicio > fim {
+			return 0, fmt.Errorf("%q fora do intervalo %d-%d", parte, min, max)
+		}
+		for v := inicio; v <= fim; v += passo {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// String retorna a expressão original.
+func (c *Cron) String() string {
+	return c.expressao
+}
+
+// diaCorresponde verifica o dia do mês e o dia da semana.
+func (c *Cron) diaCorresponde(t time.Time) bool {
+	dia := c.dias&(1<<uint(t.Day())) != 0
+	semana := c.diasSemana&(1<<uint(t.Weekday())) != 0
+	if c.diaRestrito && c.semanaRestrita {
+		return dia || semana
+	}
+	return dia && semana
+}
+
+// Proximo retorna o primeiro horário agendado estritamente posterior a t, no fuso de t.
+// Retorna o tempo zero se não houver horário nos próximos cinco anos (ex.: 30 de fevereiro).
+func (c *Cron) Proximo(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limite := t.AddDate(5, 0, 0)
+
+	for t.Before(limite) {
+		if c.meses&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.diaCorresponde(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.horas&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if c.minutos&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,67 @@
+package vadu_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CronTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestCronTestSuite(t *testing.T) {
+	suite.Run(t, new(CronTestSuite))
+}
+
+func (s *CronTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestParseCron verifica as expressões aceitas e rejeitadas
+func (s *CronTestSuite) TestParseCron() {
+	for _, expressao := range []string{"* * * * *", "0 6 1 * *", "*/15 8-18 * * 1-5", "0 0 1,15 * *", "30 2 * * 7", "@monthly", "@Weekly"} {
+		_, err := vadu.ParseCron(expressao)
+		s.assert.NoError(err, expressao)
+	}
+	for _, expressao := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@sempre"} {
+		_, err := vadu.ParseCron(expressao)
+		s.assert.ErrorIs(err, vadu.ErrCronInvalido, expressao)
+	}
+}
+
+// TestProximo verifica o cálculo do próximo horário agendado
+func (s *CronTestSuite) TestProximo() {
+	loc := vadu.LocalizacaoSaoPaulo()
+	base := time.Date(2024, time.January, 31, 10, 7, 30, 0, loc) // quarta-feira
+
+	casos := []struct {
+		expressao string
+		esperado  time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 8, 0, 0, loc)},
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 15, 0, 0, loc)},
+		{"0 6 1 * *", time.Date(2024, time.February, 1, 6, 0, 0, 0, loc)},
+		{"@weekly", time.Date(2024, time.February, 4, 0, 0, 0, 0, loc)},
+		{"0 9 * * 1-5", time.Date(2024, time.February, 1, 9, 0, 0, 0, loc)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, loc)},
+		{"0 0 31 * *", time.Date(2024, time.March, 31, 0, 0, 0, 0, loc)},
+		// Dia do mês e da semana restritos: basta um deles
+		{"0 0 15 * 5", time.Date(2024, time.February, 2, 0, 0, 0, 0, loc)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, caso := range casos {
+		cron, err := vadu.ParseCron(caso.expressao)
+		s.assert.NoError(err)
+		s.assert.True(caso.esperado.Equal(cron.Proximo(base)), "%s: %v", caso.expressao, cron.Proximo(base))
+	}
+
+	// O horário exato não é repetido
+	cron, _ := vadu.ParseCron("0 6 1 * *")
+	agendado := time.Date(2024, time.February, 1, 6, 0, 0, 0, loc)
+	s.assert.True(time.Date(2024, time.March, 1, 6, 0, 0, 0, loc).Equal(cron.Proximo(agendado)))
+}
@@ -0,0 +1,406 @@
+package vadu
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrValorAusente indica que uma conta necessária ao cálculo de um indicador não foi informada.
+	ErrValorAusente = errors.New("valor ausente")
+	// ErrDivisaoPorZero indica que o denominador de um indicador é zero.
+	ErrDivisaoPorZero = errors.New("divisão por zero")
+	// ErrIndicadorNaoCalculado indica que um ou mais indicadores não puderam ser calculados.
+	ErrIndicadorNaoCalculado = errors.New("indicadores não calculados")
+)
+
+// Balanco reúne as contas do balanço patrimonial usadas no cálculo dos indicadores.
+// Contas nil são tratadas como não informadas; use DecimalPtr para informar zero explicitamente.
+type Balanco struct {
+	AtivoTotal                *Decimal // Ativo total
+	AtivoCirculante           *Decimal // Ativo circulante
+	AtivoNaoCirculante        *Decimal // Ativo não circulante
+	AtivoRealizavelLongoPrazo *Decimal // Realizável a longo prazo (parte do ativo não circulante)
+	DisponivelCaixa           *Decimal // Caixa e equivalentes de caixa
+	ContasReceber             *Decimal // Clientes / duplicatas a receber (não usado nos indicadores)
+	Estoques                  *Decimal // Estoques
+	PassivoCirculante         *Decimal // Passivo circulante
+	PassivoNaoCirculante      *Decimal // Passivo não circulante
+	PassivoTotal              *Decimal // Passivo total, como informado no balanço
+	PatrimonioLiquido         *Decimal // Patrimônio líquido
+	Fornecedores              *Decimal // Fornecedores (não usado nos indicadores)
+	Emprestimos               *Decimal // Empréstimos e financiamentos (curto e longo prazo)
+}
+
+// DRE reúne as contas da demonstração do resultado usadas no cálculo dos indicadores.
+type DRE struct {
+	Meses               int      // Quantidade de meses do período (padrão 12)
+	ReceitaBruta        *Decimal // Receita operacional bruta
+	DeducaoReceitaBruta *Decimal // Deduções da receita bruta (impostos, devoluções e abatimentos)
+	ReceitaLiquida      *Decimal // Receita operacional líquida (padrão ReceitaBruta - DeducaoReceitaBruta)
+	VendasLiquidas      *Decimal // Vendas líquidas (padrão ReceitaLiquida)
+	Despesas            *Decimal // Despesas operacionais
+	DepreciacaoBens     *Decimal // Depreciação e amortização
+	LucroOperacional    *Decimal // Lucro operacional (EBIT) (não usado nos indicadores)
+	LucroLiquido        *Decimal // Lucro líquido do período
+}
+
+// PendenciaIndicador descreve um indicador que não pôde ser calculado.
+type PendenciaIndicador struct {
+	Campo   string // Nome JSON do campo em DadosIntegracao
+	Formula string // Fórmula utilizada
+	Err     error  // ErrValorAusente ou ErrDivisaoPorZero, com a conta envolvida
+}
+
+// IndicadoresError é retornado quando algum indicador não pôde ser calculado.
+// Os demais campos de DadosIntegracao são preenchidos normalmente.
+type IndicadoresError struct {
+	Pendencias []PendenciaIndicador
+}
+
+// Error lista os indicadores não calculados e os motivos.
+func (e *IndicadoresError) Error() string {
+	motivos := make([]string, 0, len(e.Pendencias))
+	for _, pendencia := range e.Pendencias {
+		motivos = append(motivos, fmt.Sprintf("%s (%v)", pendencia.Campo, pendencia.Err))
+	}
+	return fmt.Sprintf("%s: %s", ErrIndicadorNaoCalculado.Error(), strings.Join(motivos, ", "))
+}
+
+// Is permite comparar o erro com ErrIndicadorNaoCalculado, ErrValorAusente e ErrDivisaoPorZero via errors.Is.
+func (e *IndicadoresError) Is(target error) bool {
+	if target == ErrIndicadorNaoCalculado {
+		return true
+	}
+	for _, pendencia := range e.Pendencias {
+		if errors.Is(pendencia.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// calculo acumula o primeiro erro encontrado ao ler contas e dividir valores, permitindo
+// escrever cada fórmula de forma linear.
+type calculo struct {
+	balanco Balanco
+	dre     DRE
+	err     error
+}
+
+// conta retorna o valor informado ou registra ErrValorAusente.
+func (c *calculo) conta(valor *Decimal, nome string) Decimal {
+	if valor == nil {
+		if c.err == nil {
+			c.err = fmt.Errorf("%w: %s", ErrValorAusente, nome)
+		}
+		return Decimal{}
+	}
+	return *valor
+}
+
+// divide retorna numerador/denominador ou registra ErrDivisaoPorZero.
+// O resultado é arredondado para EscalaIndicadores casas decimais.
+func (c *calculo) divide(numerador, denominador Decimal, nome string) Decimal {
+	if c.err != nil {
+		return Decimal{}
+	}
+	resultado, err := numerador.Div(denominador, EscalaIndicadores)
+	if err != nil {
+		c.err = fmt.Errorf("%w: %s", err, nome)
+		return Decimal{}
+	}
+	return resultado
+}
+
+// Contas usadas em mais de um indicador.
+
+func (c *calculo) ativoCirculante() Decimal {
+	return c.conta(c.balanco.AtivoCirculante, "ativoCirculante")
+}
+
+func (c *calculo) ativoTotal() Decimal {
+	return c.conta(c.balanco.AtivoTotal, "ativoTotal")
+}
+
+func (c *calculo) passivoCirculante() Decimal {
+	return c.conta(c.balanco.PassivoCirculante, "passivoCirculante")
+}
+
+func (c *calculo) passivoNaoCirculante() Decimal {
+	return c.conta(c.balanco.PassivoNaoCirculante, "passivoNaoCirculante")
+}
+
+func (c *calculo) patrimonioLiquido() Decimal {
+	return c.conta(c.balanco.PatrimonioLiquido, "patrimonioLiquido")
+}
+
+func (c *calculo) lucroLiquido() Decimal {
+	return c.conta(c.dre.LucroLiquido, "lucroLiquido")
+}
+
+func (c *calculo) receitaLiquida() Decimal {
+	return c.conta(c.dre.ReceitaLiquida, "receitaLiquida")
+}
+
+// capitalTerceiros retorna PC + PNC.
+func (c *calculo) capitalTerceiros() Decimal {
+	return c.passivoCirculante().Add(c.passivoNaoCirculante())
+}
+
+// indicador associa um campo calculado de DadosIntegracao à sua fórmula.
+type indicador struct {
+	campo   string
+	formula string
+	destino func(d *DadosIntegracao) *Decimal
+	calcula func(c *calculo) Decimal
+}
+
+// indicadores lista os campos de DadosIntegracao derivados do balanço e da DRE, com as fórmulas
+// usadas pela Vadu (conferidas com o payload de exemplo da API). Indicadores de rentabilidade são
+// razões (0,15 = 15%), não percentuais; margemOperacional é um valor em reais.
+// capitalGiroProprio, necessidadeCapitalGiro e giroAtivo não são derivados porque a definição
+// usada pela Vadu não é conhecida; informe-os via ComDadosBase.
+var indicadores = []indicador{
+	{
+		campo:   "faturamentoMedioMensal",
+		formula: "receitaLiquida / meses",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.FaturamentoMedioMensal },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.receitaLiquida(), DecimalFromInt(int64(c.dre.Meses)), "meses")
+		},
+	},
+	{
+		campo:   "capitalGiroLiquido",
+		formula: "ativoCirculante - passivoCirculante",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.CapitalGiroLiquido },
+		calcula: func(c *calculo) Decimal { return c.ativoCirculante().Sub(c.passivoCirculante()) },
+	},
+	{
+		campo:   "liquidezCorrente",
+		formula: "ativoCirculante / passivoCirculante",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.LiquidezCorrente },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.ativoCirculante(), c.passivoCirculante(), "passivoCirculante")
+		},
+	},
+	{
+		campo:   "liquidezSeca",
+		formula: "(ativoCirculante - estoques) / passivoCirculante",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.LiquidezSeca },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.ativoCirculante().Sub(c.conta(c.balanco.Estoques, "estoques")), c.passivoCirculante(), "passivoCirculante")
+		},
+	},
+	{
+		campo:   "liquidezGeral",
+		formula: "ativoTotal / (passivoCirculante + passivoNaoCirculante)",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.LiquidezGeral },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.ativoTotal(), c.capitalTerceiros(), "passivoCirculante + passivoNaoCirculante")
+		},
+	},
+	{
+		campo:   "liquidezImediata",
+		formula: "disponivelCaixa / passivoCirculante",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.LiquidezImediata },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.conta(c.balanco.DisponivelCaixa, "disponivelCaixa"), c.passivoCirculante(), "passivoCirculante")
+		},
+	},
+	{
+		campo:   "grauSolvencia",
+		formula: "lucroLiquido / (passivoCirculante + passivoNaoCirculante)",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.GrauSolvencia },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.lucroLiquido(), c.capitalTerceiros(), "passivoCirculante + passivoNaoCirculante")
+		},
+	},
+	{
+		campo:   "endividamento",
+		formula: "(passivoCirculante + passivoNaoCirculante) / ativoTotal",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.Endividamento },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.capitalTerceiros(), c.ativoTotal(), "ativoTotal")
+		},
+	},
+	{
+		campo:   "dependeciaRecursosTerceiros",
+		formula: "(passivoCirculante + passivoNaoCirculante) / patrimonioLiquido",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.DependenciaRecursosTerceiros },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.capitalTerceiros(), c.patrimonioLiquido(), "patrimonioLiquido")
+		},
+	},
+	{
+		campo:   "endividamentoCurtoPrazo",
+		formula: "passivoCirculante / ativoTotal",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.EndividamentoCurtoPrazo },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.passivoCirculante(), c.ativoTotal(), "ativoTotal")
+		},
+	},
+	{
+		campo:   "nivelImobilizacao",
+		formula: "ativoNaoCirculante / patrimonioLiquido",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.NivelImobilizacao },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.conta(c.balanco.AtivoNaoCirculante, "ativoNaoCirculante"), c.patrimonioLiquido(), "patrimonioLiquido")
+		},
+	},
+	{
+		campo:   "grauDependenciaBancaria",
+		formula: "emprestimos / ativoTotal",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.GrauDependenciaBancaria },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.conta(c.balanco.Emprestimos, "emprestimos"), c.ativoTotal(), "ativoTotal")
+		},
+	},
+	{
+		campo:   "retornoPatrimonioLiquidoROE",
+		formula: "lucroLiquido / patrimonioLiquido",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.RetornoPatrimonioLiquidoROE },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.lucroLiquido(), c.patrimonioLiquido(), "patrimonioLiquido")
+		},
+	},
+	{
+		campo:   "retornoSobreAtivoRAO",
+		formula: "lucroLiquido / ativoTotal",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.RetornoSobreAtivoRAO },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.lucroLiquido(), c.ativoTotal(), "ativoTotal")
+		},
+	},
+	{
+		campo:   "retornoSobreVendas",
+		formula: "lucroLiquido / receitaLiquida",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.RetornoSobreVendas },
+		calcula: func(c *calculo) Decimal {
+			return c.divide(c.lucroLiquido(), c.receitaLiquida(), "receitaLiquida")
+		},
+	},
+	{
+		campo:   "margemOperacional",
+		formula: "receitaBruta - despesas",
+		destino: func(d *DadosIntegracao) *Decimal { return &d.MargemOperacional },
+		calcula: func(c *calculo) Decimal {
+			return c.conta(c.dre.ReceitaBruta, "receitaBruta").Sub(c.conta(c.dre.Despesas, "despesas"))
+		},
+	},
+}
+
+// FormulasIndicadores retorna a fórmula de cada campo de DadosIntegracao calculado pelo builder,
+// indexada pelo nome JSON do campo.
+func FormulasIndicadores() map[string]string {
+	formulas := make(map[string]string, len(indicadores))
+	for _, ind := range indicadores {
+		formulas[ind.campo] = ind.formula
+	}
+	return formulas
+}
+
+// DadosIntegracaoBuilder monta um DadosIntegracao a partir do balanço e da DRE,
+// derivando os indicadores financeiros.
+type DadosIntegracaoBuilder struct {
+	base    DadosIntegracao
+	balanco Balanco
+	dre     DRE
+}
+
+// NewDadosIntegracaoBuilder cria um builder para o documento informado.
+func NewDadosIntegracaoBuilder(cnpjcpf string) *DadosIntegracaoBuilder {
+	return &DadosIntegracaoBuilder{base: DadosIntegracao{CNPJCPF: Documento(NormalizaDocumento(cnpjcpf))}}
+}
+
+// ComDadosBase define os campos que não derivam das demonstrações (ex.: SCR, score externo e
+// restritivos) e os indicadores que não são derivados (capitalGiroProprio, necessidadeCapitalGiro
+// e giroAtivo). As contas informadas e os indicadores derivados são sobrescritos pelo Build.
+func (b *DadosIntegracaoBuilder) ComDadosBase(dados DadosIntegracao) *DadosIntegracaoBuilder {
+	documento := b.base.CNPJCPF
+	b.base = dados
+	if b.base.CNPJCPF == "" {
+		b.base.CNPJCPF = documento
+	}
+	return b
+}
+
+// ComBalanco define as contas do balanço patrimonial.
+func (b *DadosIntegracaoBuilder) ComBalanco(balanco Balanco) *DadosIntegracaoBuilder {
+	b.balanco = balanco
+	return b
+}
+
+// ComDRE define as contas da demonstração do resultado.
+func (b *DadosIntegracaoBuilder) ComDRE(dre DRE) *DadosIntegracaoBuilder {
+	b.dre = dre
+	return b
+}
+
+// Build copia as contas informadas para DadosIntegracao e calcula os indicadores. Indicadores
+// com contas ausentes ou denominador zero ficam zerados e são listados em um *IndicadoresError;
+// os demais campos são retornados preenchidos mesmo nesse caso.
+func (b *DadosIntegracaoBuilder) Build() (DadosIntegracao, error) {
+	dados := b.base
+	if err := dados.CNPJCPF.Valida(); err != nil {
+		return dados, err
+	}
+
+	balanco, dre := b.balanco, b.dre
+	if dre.Meses == 0 {
+		dre.Meses = 12
+	}
+	if dre.ReceitaLiquida == nil && dre.ReceitaBruta != nil && dre.DeducaoReceitaBruta != nil {
+		dre.ReceitaLiquida = DecimalPtr(dre.ReceitaBruta.Sub(*dre.DeducaoReceitaBruta))
+	}
+	if dre.VendasLiquidas == nil {
+		dre.VendasLiquidas = dre.ReceitaLiquida
+	}
+
+	contas := []struct {
+		valor   *Decimal
+		destino *Decimal
+	}{
+		{balanco.AtivoTotal, &dados.AtivoTotal},
+		{balanco.AtivoCirculante, &dados.AtivoCirculante},
+		{balanco.AtivoNaoCirculante, &dados.AtivoNaoCirculante},
+		{balanco.AtivoRealizavelLongoPrazo, &dados.AtivoRealizavelLongoPrazo},
+		{balanco.DisponivelCaixa, &dados.DisponivelCaixa},
+		{balanco.Emprestimos, &dados.Emprestimo},
+		{balanco.Estoques, &dados.EstoqueBalanco},
+		{balanco.PassivoCirculante, &dados.PassivoCirculante},
+		{balanco.PassivoNaoCirculante, &dados.PassivoNaoCirculante},
+		{balanco.PassivoTotal, &dados.PassivoTotal},
+		{balanco.PatrimonioLiquido, &dados.PatrimonioLiquido},
+		{dre.ReceitaBruta, &dados.ReceitaBruta},
+		{dre.DeducaoReceitaBruta, &dados.DeducaoReceitaBruta},
+		{dre.ReceitaLiquida, &dados.ReceitaLiquida},
+		{dre.VendasLiquidas, &dados.VendasLiquidas},
+		{dre.Despesas, &dados.Despesas},
+		{dre.DepreciacaoBens, &dados.DepreciacaoBens},
+		{dre.LucroLiquido, &dados.LucroLiquido},
+	}
+	for _, conta := range contas {
+		if conta.valor != nil {
+			*conta.destino = *conta.valor
+		}
+	}
+
+	var pendencias []PendenciaIndicador
+	for _, ind := range indicadores {
+		c := &calculo{balanco: balanco, dre: dre}
+		valor := ind.calcula(c)
+		if c.err != nil {
+			*ind.destino(&dados) = Decimal{}
+			pendencias = append(pendencias, PendenciaIndicador{Campo: ind.campo, Formula: ind.formula, Err: c.err})
+			continue
+		}
+		*ind.destino(&dados) = valor
+	}
+
+	if len(pendencias) > 0 {
+		return dados, &IndicadoresError{Pendencias: pendencias}
+	}
+	return dados, nil
+}
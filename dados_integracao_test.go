@@ -0,0 +1,178 @@
+package vadu_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DadosIntegracaoTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestDadosIntegracaoTestSuite(t *testing.T) {
+	suite.Run(t, new(DadosIntegracaoTestSuite))
+}
+
+func (s *DadosIntegracaoTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+func decimalPtr(valor string) *vadu.Decimal {
+	return vadu.DecimalPtr(vadu.MustDecimal(valor))
+}
+
+func balancoCompleto() vadu.Balanco {
+	return vadu.Balanco{
+		AtivoTotal:                decimalPtr("1000"),
+		AtivoCirculante:           decimalPtr("600"),
+		AtivoNaoCirculante:        decimalPtr("400"),
+		AtivoRealizavelLongoPrazo: decimalPtr("100"),
+		DisponivelCaixa:           decimalPtr("150"),
+		ContasReceber:             decimalPtr("250"),
+		Estoques:                  decimalPtr("200"),
+		PassivoCirculante:         decimalPtr("300"),
+		PassivoNaoCirculante:      decimalPtr("200"),
+		PassivoTotal:              decimalPtr("1000"),
+		PatrimonioLiquido:         decimalPtr("500"),
+		Fornecedores:              decimalPtr("120"),
+		Emprestimos:               decimalPtr("180"),
+	}
+}
+
+func dreCompleta() vadu.DRE {
+	return vadu.DRE{
+		ReceitaBruta:        decimalPtr("2800"),
+		DeducaoReceitaBruta: decimalPtr("400"),
+		Despesas:            decimalPtr("1700"),
+		LucroLiquido:        decimalPtr("100"),
+	}
+}
+
+// TestBuildIndicadores verifica o cálculo de todos os indicadores
+func (s *DadosIntegracaoTestSuite) TestBuildIndicadores() {
+	dados, err := vadu.NewDadosIntegracaoBuilder("98.960.887/0001-64").
+		ComDadosBase(vadu.DadosIntegracao{ScoreExterno: 750, GiroAtivo: vadu.MustDecimal("2.5")}).
+		ComBalanco(balancoCompleto()).
+		ComDRE(dreCompleta()).
+		Build()
+	s.assert.NoError(err)
+
+	s.assert.Equal(vadu.Documento("98960887000164"), dados.CNPJCPF)
+	s.assert.Equal(750, dados.ScoreExterno)
+	s.assert.Equal(vadu.MustDecimal("2400"), dados.ReceitaLiquida)
+	s.assert.Equal(vadu.MustDecimal("2400"), dados.VendasLiquidas)
+	s.assert.Equal(vadu.MustDecimal("180"), dados.Emprestimo)
+	s.assert.Equal(vadu.MustDecimal("200"), dados.FaturamentoMedioMensal)
+	s.assert.Equal(vadu.MustDecimal("300"), dados.CapitalGiroLiquido)
+	s.assert.Equal(vadu.MustDecimal("2"), dados.LiquidezCorrente)
+	s.assert.Equal(vadu.MustDecimal("1.3333333333"), dados.LiquidezSeca)
+	s.assert.Equal(vadu.MustDecimal("2"), dados.LiquidezGeral)
+	s.assert.Equal(vadu.MustDecimal("0.5"), dados.LiquidezImediata)
+	s.assert.Equal(vadu.MustDecimal("0.2"), dados.GrauSolvencia)
+	s.assert.Equal(vadu.MustDecimal("0.5"), dados.Endividamento)
+	s.assert.Equal(vadu.MustDecimal("1"), dados.DependenciaRecursosTerceiros)
+	s.assert.Equal(vadu.MustDecimal("0.3"), dados.EndividamentoCurtoPrazo)
+	s.assert.Equal(vadu.MustDecimal("0.8"), dados.NivelImobilizacao)
+	s.assert.Equal(vadu.MustDecimal("0.18"), dados.GrauDependenciaBancaria)
+	s.assert.Equal(vadu.MustDecimal("0.2"), dados.RetornoPatrimonioLiquidoROE)
+	s.assert.Equal(vadu.MustDecimal("0.1"), dados.RetornoSobreAtivoRAO)
+	s.assert.Equal(vadu.MustDecimal("0.0416666667"), dados.RetornoSobreVendas)
+	s.assert.Equal(vadu.MustDecimal("1100"), dados.MargemOperacional)
+
+	// Indicadores sem definição conhecida vêm dos dados base
+	s.assert.Equal(vadu.MustDecimal("2.5"), dados.GiroAtivo)
+	s.assert.True(dados.CapitalGiroProprio.IsZero())
+	s.assert.True(dados.NecessidadeCapitalGiro.IsZero())
+
+	s.assert.Len(vadu.FormulasIndicadores(), 16)
+	s.assert.NotContains(vadu.FormulasIndicadores(), "giroAtivo")
+}
+
+// TestBuildExemploVadu verifica que os indicadores derivados coincidem com os do payload de exemplo da Vadu
+func (s *DadosIntegracaoTestSuite) TestBuildExemploVadu() {
+	exemplo, err := dadosIntegracaoExemplo().DadosIntegracao()
+	s.assert.NoError(err)
+
+	dados, err := vadu.NewDadosIntegracaoBuilder(string(exemplo.CNPJCPF)).
+		ComBalanco(vadu.Balanco{
+			AtivoTotal:                vadu.DecimalPtr(exemplo.AtivoTotal),
+			AtivoCirculante:           vadu.DecimalPtr(exemplo.AtivoCirculante),
+			AtivoNaoCirculante:        vadu.DecimalPtr(exemplo.AtivoNaoCirculante),
+			AtivoRealizavelLongoPrazo: vadu.DecimalPtr(exemplo.AtivoRealizavelLongoPrazo),
+			DisponivelCaixa:           vadu.DecimalPtr(exemplo.DisponivelCaixa),
+			Estoques:                  vadu.DecimalPtr(exemplo.EstoqueBalanco),
+			PassivoCirculante:         vadu.DecimalPtr(exemplo.PassivoCirculante),
+			PassivoNaoCirculante:      vadu.DecimalPtr(exemplo.PassivoNaoCirculante),
+			PassivoTotal:              vadu.DecimalPtr(exemplo.PassivoTotal),
+			PatrimonioLiquido:         vadu.DecimalPtr(exemplo.PatrimonioLiquido),
+			Emprestimos:               vadu.DecimalPtr(exemplo.Emprestimo),
+		}).
+		ComDRE(vadu.DRE{
+			ReceitaBruta:        vadu.DecimalPtr(exemplo.ReceitaBruta),
+			DeducaoReceitaBruta: vadu.DecimalPtr(exemplo.DeducaoReceitaBruta),
+			ReceitaLiquida:      vadu.DecimalPtr(exemplo.ReceitaLiquida),
+			Despesas:            vadu.DecimalPtr(exemplo.Despesas),
+			LucroLiquido:        vadu.DecimalPtr(exemplo.LucroLiquido),
+		}).
+		Build()
+	s.assert.NoError(err)
+
+	s.assert.Equal(exemplo.CapitalGiroLiquido, dados.CapitalGiroLiquido)
+	s.assert.Equal(exemplo.MargemOperacional, dados.MargemOperacional)
+	for campo, valores := range map[string][2]vadu.Decimal{
+		"faturamentoMedioMensal":      {exemplo.FaturamentoMedioMensal, dados.FaturamentoMedioMensal},
+		"liquidezCorrente":            {exemplo.LiquidezCorrente, dados.LiquidezCorrente},
+		"liquidezSeca":                {exemplo.LiquidezSeca, dados.LiquidezSeca},
+		"liquidezGeral":               {exemplo.LiquidezGeral, dados.LiquidezGeral},
+		"liquidezImediata":            {exemplo.LiquidezImediata, dados.LiquidezImediata},
+		"grauSolvencia":               {exemplo.GrauSolvencia, dados.GrauSolvencia},
+		"endividamento":               {exemplo.Endividamento, dados.Endividamento},
+		"dependeciaRecursosTerceiros": {exemplo.DependenciaRecursosTerceiros, dados.DependenciaRecursosTerceiros},
+		"endividamentoCurtoPrazo":     {exemplo.EndividamentoCurtoPrazo, dados.EndividamentoCurtoPrazo},
+		"nivelImobilizacao":           {exemplo.NivelImobilizacao, dados.NivelImobilizacao},
+		"grauDependenciaBancaria":     {exemplo.GrauDependenciaBancaria, dados.GrauDependenciaBancaria},
+		"retornoPatrimonioLiquidoROE": {exemplo.RetornoPatrimonioLiquidoROE, dados.RetornoPatrimonioLiquidoROE},
+		"retornoSobreAtivoRAO":        {exemplo.RetornoSobreAtivoRAO, dados.RetornoSobreAtivoRAO},
+		"retornoSobreVendas":          {exemplo.RetornoSobreVendas, dados.RetornoSobreVendas},
+	} {
+		s.assert.InDelta(valores[0].Float64(), valores[1].Float64(), 0.0001, campo)
+	}
+}
+
+// TestBuildPendencias verifica o tratamento de contas ausentes e divisões por zero
+func (s *DadosIntegracaoTestSuite) TestBuildPendencias() {
+	balanco := balancoCompleto()
+	balanco.PatrimonioLiquido = decimalPtr("0")
+	balanco.Estoques = nil
+
+	dados, err := vadu.NewDadosIntegracaoBuilder("98960887000164").
+		ComBalanco(balanco).
+		ComDRE(dreCompleta()).
+		Build()
+	s.assert.ErrorIs(err, vadu.ErrIndicadorNaoCalculado)
+	s.assert.ErrorIs(err, vadu.ErrValorAusente)
+	s.assert.ErrorIs(err, vadu.ErrDivisaoPorZero)
+
+	var indicadoresErr *vadu.IndicadoresError
+	s.assert.True(errors.As(err, &indicadoresErr))
+	campos := map[string]error{}
+	for _, pendencia := range indicadoresErr.Pendencias {
+		campos[pendencia.Campo] = pendencia.Err
+	}
+	s.assert.ErrorIs(campos["liquidezSeca"], vadu.ErrValorAusente)
+	s.assert.ErrorIs(campos["retornoPatrimonioLiquidoROE"], vadu.ErrDivisaoPorZero)
+	s.assert.ErrorIs(campos["nivelImobilizacao"], vadu.ErrDivisaoPorZero)
+	s.assert.ErrorIs(campos["dependeciaRecursosTerceiros"], vadu.ErrDivisaoPorZero)
+	s.assert.Len(campos, 4)
+
+	s.assert.True(dados.LiquidezSeca.IsZero())
+	s.assert.Equal(vadu.MustDecimal("2"), dados.LiquidezCorrente)
+
+	_, err = vadu.NewDadosIntegracaoBuilder("123").Build()
+	s.assert.ErrorIs(err, vadu.ErrDocumentoInvalido)
+}
@@ -0,0 +1,112 @@
+package vadu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DataHora representa uma data/hora retornada pela API do Vadu. A decodificação
+// aceita as variações observadas nas respostas: com ou sem milissegundos e com
+// sufixo Z, com offset (-03:00 ou -0300) ou sem indicação de fuso. Valores sem
+// fuso são interpretados no horário de Brasília (America/Sao_Paulo).
+type DataHora struct {
+	time.Time
+}
+
+// layoutsDataHora lista os formatos aceitos, do mais para o menos específico.
+// Frações de segundo são aceitas em qualquer um deles pelo pacote time.
+var layoutsDataHora = []struct {
+	layout  string
+	comFuso bool
+}{
+	{time.RFC3339Nano, true},
+	{"2006-01-02T15:04:05Z0700", true},
+	{"2006-01-02 15:04:05Z07:00", true},
+	{"2006-01-02 15:04:05Z0700", true},
+	{"2006-01-02T15:04:05", false},
+	{"2006-01-02 15:04:05", false},
+	{"2006-01-02", false},
+}
+
+var (
+	saoPauloOnce sync.Once
+	saoPaulo     *time.Location
+)
+
+// LocalizacaoSaoPaulo retorna o fuso America/Sao_Paulo. Se a base de fusos
+// horários não estiver disponível no sistema, é utilizado o offset fixo -03:00,
+// vigente desde o fim do horário de verão em 2019.
+func LocalizacaoSaoPaulo() *time.Location {
+	saoPauloOnce.Do(func() {
+		loc, err := time.LoadLocation("America/Sao_Paulo")
+		if err != nil {
+			loc = time.FixedZone("-03", -3*60*60)
+		}
+		saoPaulo = loc
+	})
+	return saoPaulo
+}
+
+// ParseDataHora interpreta uma data/hora em qualquer um dos formatos aceitos pela API.
+func ParseDataHora(valor string) (DataHora, error) {
+	valor = strings.TrimSpace(valor)
+	if valor == "" {
+		return DataHora{}, nil
+	}
+	for _, formato := range layoutsDataHora {
+		var t time.Time
+		var err error
+		if formato.comFuso {
+			t, err = time.Parse(formato.layout, valor)
+		} else {
+			t, err = time.ParseInLocation(formato.layout, valor, LocalizacaoSaoPaulo())
+		}
+		if err == nil {
+			return DataHora{Time: t}, nil
+		}
+	}
+	return DataHora{}, fmt.Errorf("formato de data/hora não reconhecido: %q", valor)
+}
+
+// SaoPaulo retorna a data/hora convertida para o fuso America/Sao_Paulo.
+func (d DataHora) SaoPaulo() time.Time {
+	return d.Time.In(LocalizacaoSaoPaulo())
+}
+
+// MarshalJSON serializa a data/hora no formato RFC 3339 ou como null quando vazia.
+func (d DataHora) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Time.Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON decodifica a data/hora. Os valores null e "" resultam em uma data/hora vazia.
+func (d *DataHora) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = DataHora{}
+		return nil
+	}
+	var valor string
+	if err := json.Unmarshal(data, &valor); err != nil {
+		return err
+	}
+	parsed, err := ParseDataHora(valor)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// DuracaoAteConclusao retorna o tempo entre o envio e a conclusão da análise.
+// O segundo retorno é false se a análise não estiver concluída ou se alguma das datas estiver ausente.
+func (r ResumoAnalise) DuracaoAteConclusao() (time.Duration, bool) {
+	if !r.Concluido || r.DataHoraEnvio.IsZero() || r.DataHoraConclusao.IsZero() {
+		return 0, false
+	}
+	return r.DataHoraConclusao.Sub(r.DataHoraEnvio.Time), true
+}
@@ -0,0 +1,75 @@
+package vadu_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DataHoraTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestDataHoraTestSuite(t *testing.T) {
+	suite.Run(t, new(DataHoraTestSuite))
+}
+
+func (s *DataHoraTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestVariacoesDeFormato verifica os formatos de data/hora aceitos
+func (s *DataHoraTestSuite) TestVariacoesDeFormato() {
+	esperado := time.Date(2024, 11, 25, 18, 28, 44, 0, time.UTC)
+
+	for _, valor := range []string{
+		"2024-11-25T18:28:44Z",
+		"2024-11-25T18:28:44.000Z",
+		"2024-11-25T15:28:44-03:00",
+		"2024-11-25T15:28:44.000-0300",
+		"2024-11-25T15:28:44",
+		"2024-11-25 15:28:44.000",
+	} {
+		dataHora, err := vadu.ParseDataHora(valor)
+		s.assert.NoError(err, valor)
+		s.assert.True(esperado.Equal(dataHora.Time), valor)
+	}
+
+	dataHora, err := vadu.ParseDataHora("2024-11-25T18:28:44.523Z")
+	s.assert.NoError(err)
+	s.assert.Equal(523*time.Millisecond, time.Duration(dataHora.Nanosecond()))
+
+	_, err = vadu.ParseDataHora("25/11/2024")
+	s.assert.Error(err)
+}
+
+// TestResumoAnalise verifica a decodificação e os auxiliares do resumo da análise
+func (s *DataHoraTestSuite) TestResumoAnalise() {
+	var resumo vadu.ResumoAnalise
+	err := json.Unmarshal([]byte(`{
+		"concluido": true,
+		"data_hora_envio": "2024-11-25T18:28:44.523Z",
+		"data_hora_conclusao": "2024-11-25T18:28:50.483Z"
+	}`), &resumo)
+	s.assert.NoError(err)
+
+	duracao, ok := resumo.DuracaoAteConclusao()
+	s.assert.True(ok)
+	s.assert.Equal(5960*time.Millisecond, duracao)
+	s.assert.Equal("2024-11-25T15:28:44-03:00", resumo.DataHoraEnvio.SaoPaulo().Format(time.RFC3339))
+
+	err = json.Unmarshal([]byte(`{"concluido": false, "data_hora_conclusao": null}`), &resumo)
+	s.assert.NoError(err)
+	s.assert.True(resumo.DataHoraConclusao.IsZero())
+	_, ok = resumo.DuracaoAteConclusao()
+	s.assert.False(ok)
+
+	data, err := json.Marshal(resumo.DataHoraConclusao)
+	s.assert.NoError(err)
+	s.assert.Equal("null", string(data))
+}
@@ -0,0 +1,453 @@
+package vadu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrDecimalInvalido indica um texto que não representa um número decimal.
+	ErrDecimalInvalido = errors.New("decimal inválido")
+	// ErrDecimalOverflow indica um texto com mais de 100 dígitos na parte inteira.
+	ErrDecimalOverflow = errors.New("decimal fora do intervalo suportado")
+)
+
+// EscalaIndicadores é a quantidade de casas decimais usada na divisão de indicadores financeiros.
+const EscalaIndicadores = 10
+
+// escalaMaxima é a maior quantidade de casas decimais representável.
+const escalaMaxima = 18
+
+// digitosMaximos limita a parte inteira dos textos interpretados, para rejeitar entradas
+// como "1e9999". As operações aritméticas não têm limite.
+const digitosMaximos = 100
+
+// Decimal é um número decimal exato, usado nos campos monetários e nos indicadores de
+// DadosIntegracao. O valor é coef / 10^escala, com coef de precisão arbitrária e até 18 casas
+// decimais; resultados com mais casas são arredondados (meio para longe do zero). As operações
+// não alteram os operandos e não têm limite de magnitude. A representação é normalizada e
+// comparável: valores iguais são iguais também com ==, inclusive em structs como DadosIntegracao.
+// O valor zero é 0.
+type Decimal struct {
+	coef   string // Dígitos decimais do coeficiente, com sinal; vazio quando o valor é zero
+	escala uint8
+}
+
+var (
+	dez            = big.NewInt(10)
+	formatoDecimal = regexp.MustCompile(`^([+-]?)(\d*)(?:\.(\d*))?(?:[eE]([+-]?\d{1,4}))?$`)
+)
+
+// NewDecimal cria o Decimal coef / 10^escala.
+func NewDecimal(coef int64, escala int) Decimal {
+	return novoDecimal(big.NewInt(coef), escala)
+}
+
+// DecimalFromInt cria um Decimal inteiro.
+func DecimalFromInt(valor int64) Decimal {
+	return NewDecimal(valor, 0)
+}
+
+// DecimalFromFloat converte um float64 usando a menor representação decimal que o identifica
+// (ex.: 824167.11 resulta exatamente em 824167.11). Artefatos já presentes no float, como
+// 1234.5600000000002, são preservados; use Round para descartá-los.
+func DecimalFromFloat(valor float64) (Decimal, error) {
+	if math.IsNaN(valor) || math.IsInf(valor, 0) {
+		return Decimal{}, fmt.Errorf("%w: %v", ErrDecimalInvalido, valor)
+	}
+	return ParseDecimal(strconv.FormatFloat(valor, 'g', -1, 64))
+}
+
+// ParseDecimal interpreta um número com ponto decimal, como em JSON ("-1234.56", "1e3").
+func ParseDecimal(valor string) (Decimal, error) {
+	partes := formatoDecimal.FindStringSubmatch(strings.TrimSpace(valor))
+	if partes == nil || partes[2]+partes[3] == "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrDecimalInvalido, valor)
+	}
+
+	escala := len(partes[3])
+	if partes[4] != "" {
+		expoente, _ := strconv.Atoi(partes[4])
+		escala -= expoente
+	}
+	digitos := strings.TrimLeft(partes[2]+partes[3], "0")
+	if len(digitos)-escala > digitosMaximos {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrDecimalOverflow, valor)
+	}
+
+	coef, _ := new(big.Int).SetString(partes[2]+partes[3], 10)
+	if partes[1] == "-" {
+		coef.Neg(coef)
+	}
+	return novoDecimal(coef, escala), nil
+}
+
+// ParseDecimalBR interpreta um número no formato brasileiro, com as mesmas regras de ParseNumeroBR.
+func ParseDecimalBR(valor string) (Decimal, error) {
+	texto, err := normalizaNumeroBR(valor)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrDecimalInvalido, valor)
+	}
+	return ParseDecimal(texto)
+}
+
+// MustDecimal interpreta um número com ParseDecimal e entra em pânico se ele for inválido.
+// Destina-se a literais no código.
+func MustDecimal(valor string) Decimal {
+	d, err := ParseDecimal(valor)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// novoDecimal arredonda coef / 10^escala para no máximo escalaMaxima casas e normaliza o
+// resultado. coef não é alterado.
+func novoDecimal(coef *big.Int, escala int) Decimal {
+	c := new(big.Int).Set(coef)
+	if escala < 0 {
+		c.Mul(c, potenciaDez(-escala))
+		escala = 0
+	}
+	if escala > escalaMaxima {
+		c = divideArredondando(c, potenciaDez(escala-escalaMaxima))
+		escala = escalaMaxima
+	}
+	if c.Sign() == 0 {
+		return Decimal{}
+	}
+
+	quociente, resto := new(big.Int), new(big.Int)
+	for escala > 0 {
+		quociente.QuoRem(c, dez, resto)
+		if resto.Sign() != 0 {
+			break
+		}
+		c, quociente = quociente, c
+		escala--
+	}
+	return Decimal{coef: c.String(), escala: uint8(escala)}
+}
+
+// divideArredondando divide arredondando o resultado meio para longe do zero.
+func divideArredondando(numerador, denominador *big.Int) *big.Int {
+	quociente, resto := new(big.Int).QuoRem(numerador, denominador, new(big.Int))
+	resto.Abs(resto).Mul(resto, big.NewInt(2))
+	if resto.Cmp(new(big.Int).Abs(denominador)) >= 0 {
+		if numerador.Sign()*denominador.Sign() < 0 {
+			quociente.Sub(quociente, big.NewInt(1))
+		} else {
+			quociente.Add(quociente, big.NewInt(1))
+		}
+	}
+	return quociente
+}
+
+func potenciaDez(n int) *big.Int {
+	return new(big.Int).Exp(dez, big.NewInt(int64(n)), nil)
+}
+
+// coeficiente retorna o coeficiente de d como um novo big.Int.
+func (d Decimal) coeficiente() *big.Int {
+	coef, _ := new(big.Int).SetString(d.coef, 10)
+	if coef == nil {
+		return new(big.Int)
+	}
+	return coef
+}
+
+// alinha retorna cópias dos coeficientes de d e o na mesma escala.
+func (d Decimal) alinha(o Decimal) (*big.Int, *big.Int, int) {
+	escala := int(d.escala)
+	if int(o.escala) > escala {
+		escala = int(o.escala)
+	}
+	a := d.coeficiente()
+	a.Mul(a, potenciaDez(escala-int(d.escala)))
+	b := o.coeficiente()
+	b.Mul(b, potenciaDez(escala-int(o.escala)))
+	return a, b, escala
+}
+
+// Add retorna d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	a, b, escala := d.alinha(o)
+	return novoDecimal(a.Add(a, b), escala)
+}
+
+// Sub retorna d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	a, b, escala := d.alinha(o)
+	return novoDecimal(a.Sub(a, b), escala)
+}
+
+// Mul retorna d × o, arredondado para escalaMaxima casas.
+func (d Decimal) Mul(o Decimal) Decimal {
+	produto := d.coeficiente()
+	produto.Mul(produto, o.coeficiente())
+	return novoDecimal(produto, int(d.escala)+int(o.escala))
+}
+
+// Div retorna d / o arredondado para a quantidade de casas informada. Retorna
+// ErrDivisaoPorZero quando o é zero.
+func (d Decimal) Div(o Decimal, casas int) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, ErrDivisaoPorZero
+	}
+	if casas < 0 || casas > escalaMaxima {
+		casas = escalaMaxima
+	}
+	// d/o = (coef_d × 10^(escala_o + casas)) / (coef_o × 10^escala_d), em unidades de 10^-casas
+	numerador := d.coeficiente()
+	numerador.Mul(numerador, potenciaDez(int(o.escala)+casas))
+	denominador := o.coeficiente()
+	denominador.Mul(denominador, potenciaDez(int(d.escala)))
+	return novoDecimal(divideArredondando(numerador, denominador), casas), nil
+}
+
+// Round arredonda para a quantidade de casas informada (meio para longe do zero).
+func (d Decimal) Round(casas int) Decimal {
+	if casas < 0 || int(d.escala) <= casas {
+		return d
+	}
+	return novoDecimal(divideArredondando(d.coeficiente(), potenciaDez(int(d.escala)-casas)), casas)
+}
+
+// Neg retorna -d.
+func (d Decimal) Neg() Decimal {
+	coef := d.coeficiente()
+	return novoDecimal(coef.Neg(coef), int(d.escala))
+}
+
+// Abs retorna o valor absoluto de d.
+func (d Decimal) Abs() Decimal {
+	if d.Sign() < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Sign retorna -1, 0 ou 1 conforme o sinal de d.
+func (d Decimal) Sign() int {
+	switch {
+	case d.coef == "":
+		return 0
+	case d.coef[0] == '-':
+		return -1
+	default:
+		return 1
+	}
+}
+
+// IsZero informa se d é zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compara d com o, retornando -1, 0 ou 1.
+func (d Decimal) Cmp(o Decimal) int {
+	a, b, _ := d.alinha(o)
+	return a.Cmp(b)
+}
+
+// Equal informa se d e o têm o mesmo valor.
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+// Float64 retorna a aproximação de d em float64.
+func (d Decimal) Float64() float64 {
+	valor, _ := strconv.ParseFloat(d.String(), 64)
+	return valor
+}
+
+// String retorna o valor com ponto decimal e sem zeros à direita (ex.: "-1234.56").
+func (d Decimal) String() string {
+	digitos := d.coef
+	if digitos == "" {
+		return "0"
+	}
+	sinal := ""
+	if d.Sign() < 0 {
+		sinal, digitos = "-", digitos[1:]
+	}
+	if d.escala == 0 {
+		return sinal + digitos
+	}
+	escala := int(d.escala)
+	if len(digitos) <= escala {
+		digitos = strings.Repeat("0", escala-len(digitos)+1) + digitos
+	}
+	return sinal + digitos[:len(digitos)-escala] + "." + digitos[len(digitos)-escala:]
+}
+
+// FormatBR formata o valor no padrão brasileiro ("1.234,56"). Com casas >= 0, o valor é
+// arredondado e completado com zeros até a quantidade de casas; com casas < 0, todas as
+// casas significativas são exibidas.
+func (d Decimal) FormatBR(casas int) string {
+	texto := d.Round(casas).String()
+	sinal := ""
+	if strings.HasPrefix(texto, "-") {
+		sinal, texto = "-", texto[1:]
+	}
+	inteiro, fracao := texto, ""
+	if i := strings.IndexByte(texto, '.'); i >= 0 {
+		inteiro, fracao = texto[:i], texto[i+1:]
+	}
+	if casas >= 0 && len(fracao) < casas {
+		fracao += strings.Repeat("0", casas-len(fracao))
+	}
+
+	var resultado strings.Builder
+	resultado.WriteString(sinal)
+	for i, digito := range inteiro {
+		if i > 0 && (len(inteiro)-i)%3 == 0 {
+			resultado.WriteByte('.')
+		}
+		resultado.WriteRune(digito)
+	}
+	if fracao != "" {
+		resultado.WriteByte(',')
+		resultado.WriteString(fracao)
+	}
+	return resultado.String()
+}
+
+// MarshalJSON serializa o valor como número JSON exato.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON aceita números JSON, textos e null (zero). Textos seguem as regras de
+// ParseDecimalBR, as mesmas das planilhas: "1.500" é 1500 e "1234.56" é 1234,56.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var texto string
+		if err := json.Unmarshal(data, &texto); err != nil {
+			return err
+		}
+		if strings.TrimSpace(texto) == "" {
+			*d = Decimal{}
+			return nil
+		}
+		valor, err := ParseDecimalBR(texto)
+		if err != nil {
+			return err
+		}
+		*d = valor
+		return nil
+	}
+
+	valor, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = valor
+	return nil
+}
+
+// DadosIntegracaoFloat espelha DadosIntegracao com os campos monetários e indicadores em float64,
+// para a migração de código que ainda monta os dados com float64. Converta com DadosIntegracao
+// antes de enviar; valores como 1234.5600000000002 devem ser arredondados na origem.
+//
+// Deprecated: use DadosIntegracao, cujos campos são Decimal.
+type DadosIntegracaoFloat struct {
+	CNPJCPF                       Documento `json:"cnpjcpf"`
+	AtivoTotal                    float64   `json:"ativoTotal"`
+	AtivoCirculante               float64   `json:"ativoCirculante"`
+	AtivoNaoCirculante            float64   `json:"ativoNaoCirculante"`
+	AtivoRealizavelLongoPrazo     float64   `json:"ativoRealizavelLongoPrazo"`
+	DeducaoReceitaBruta           float64   `json:"deducaoReceitaBruta"`
+	DepreciacaoBens               float64   `json:"depreciacaoBens"`
+	Despesas                      float64   `json:"despesas"`
+	DisponivelCaixa               float64   `json:"disponivelCaixa"`
+	Emprestimo                    float64   `json:"emprestimo"`
+	EstoqueBalanco                float64   `json:"estoqueBalanco"`
+	LucroLiquido                  float64   `json:"lucroLiquido"`
+	PassivoCirculante             float64   `json:"passivoCirculante"`
+	PassivoNaoCirculante          float64   `json:"passivoNaoCirculante"`
+	PassivoTotal                  float64   `json:"passivoTotal"`
+	PatrimonioLiquido             float64   `json:"patrimonioLiquido"`
+	ReceitaLiquida                float64   `json:"receitaLiquida"`
+	ReceitaBruta                  float64   `json:"receitaBruta"`
+	VendasLiquidas                float64   `json:"vendasLiquidas"`
+	ScoreExterno                  int       `json:"scoreExterno"`
+	ProbabilidadeInadimplencia    int       `json:"probabilidadeInadimplencia"`
+	DividasBaixasPrejuizo         float64   `json:"dividasBaixasPrejuizo"`
+	QuantidadeInstituicoes        int       `json:"quantidadeInstituicoes"`
+	LimiteCreditoVencimentoAte360 float64   `json:"limiteCreditoVencimentoAte360Dias"`
+	CreditosVencerAte30Dias       float64   `json:"creditosVencerAte30Dias"`
+	Falencia                      int       `json:"falencia"`
+	ChequeSemFundos               int       `json:"chequeSemFundos"`
+	FaturamentoMedioMensal        float64   `json:"faturamentoMedioMensal"`
+	CapitalGiroSCR                float64   `json:"capitalGiroSCR"`
+	CapitalGiroLiquido            float64   `json:"capitalGiroLiquido"`
+	CapitalGiroProprio            float64   `json:"capitalGiroProprio"`
+	NecessidadeCapitalGiro        float64   `json:"necessidadeCapitalGiro"`
+	LiquidezCorrente              float64   `json:"liquidezCorrente"`
+	LiquidezSeca                  float64   `json:"liquidezSeca"`
+	LiquidezGeral                 float64   `json:"liquidezGeral"`
+	LiquidezImediata              float64   `json:"liquidezImediata"`
+	GrauSolvencia                 float64   `json:"grauSolvencia"`
+	Endividamento                 float64   `json:"endividamento"`
+	DependenciaRecursosTerceiros  float64   `json:"dependeciaRecursosTerceiros"`
+	EndividamentoCurtoPrazo       float64   `json:"endividamentoCurtoPrazo"`
+	NivelImobilizacao             float64   `json:"nivelImobilizacao"`
+	GrauDependenciaBancaria       float64   `json:"grauDependenciaBancaria"`
+	RetornoPatrimonioLiquidoROE   float64   `json:"retornoPatrimonioLiquidoROE"`
+	GiroAtivo                     float64   `json:"giroAtivo"`
+	RetornoSobreAtivoRAO          float64   `json:"retornoSobreAtivoRAO"`
+	RetornoSobreVendas            float64   `json:"retornoSobreVendas"`
+	MargemOperacional             float64   `json:"margemOperacional"`
+	RatingExterno                 string    `json:"ratingExterno"`
+}
+
+// DadosIntegracao converte os campos float64 para Decimal. Retorna ErrDecimalInvalido para NaN
+// ou infinito e ErrDecimalOverflow para valores com mais de 100 dígitos na parte inteira.
+func (f DadosIntegracaoFloat) DadosIntegracao() (DadosIntegracao, error) {
+	var dados DadosIntegracao
+	origem, destino := reflect.ValueOf(f), reflect.ValueOf(&dados).Elem()
+	for i := 0; i < origem.NumField(); i++ {
+		nome := origem.Type().Field(i).Name
+		campo := destino.FieldByName(nome)
+		if campo.Type() != tipoDecimal {
+			campo.Set(origem.Field(i))
+			continue
+		}
+		valor, err := DecimalFromFloat(origem.Field(i).Float())
+		if err != nil {
+			return DadosIntegracao{}, fmt.Errorf("%s: %w", nome, err)
+		}
+		campo.Set(reflect.ValueOf(valor))
+	}
+	return dados, nil
+}
+
+// Float converte os campos Decimal para float64, para código que ainda consome DadosIntegracaoFloat.
+func (d DadosIntegracao) Float() DadosIntegracaoFloat {
+	var f DadosIntegracaoFloat
+	origem, destino := reflect.ValueOf(d), reflect.ValueOf(&f).Elem()
+	for i := 0; i < origem.NumField(); i++ {
+		campo := destino.FieldByName(origem.Type().Field(i).Name)
+		if valor, ok := origem.Field(i).Interface().(Decimal); ok {
+			campo.SetFloat(valor.Float64())
+			continue
+		}
+		campo.Set(origem.Field(i))
+	}
+	return f
+}
@@ -0,0 +1,199 @@
+package vadu_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DecimalTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestDecimalTestSuite(t *testing.T) {
+	suite.Run(t, new(DecimalTestSuite))
+}
+
+func (s *DecimalTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestParseDecimal verifica a leitura nos formatos com ponto decimal e brasileiro
+func (s *DecimalTestSuite) TestParseDecimal() {
+	casos := map[string]string{
+		"1234.56":  "1234.56",
+		"-0.50":    "-0.5",
+		"1e3":      "1000",
+		"1.5E-2":   "0.015",
+		".25":      "0.25",
+		"00100.00": "100",
+	}
+	for texto, esperado := range casos {
+		valor, err := vadu.ParseDecimal(texto)
+		s.assert.NoError(err, texto)
+		s.assert.Equal(esperado, valor.String(), texto)
+	}
+
+	for _, texto := range []string{"", "abc", "1,5", "1.2.3", "-"} {
+		_, err := vadu.ParseDecimal(texto)
+		s.assert.ErrorIs(err, vadu.ErrDecimalInvalido, texto)
+	}
+	valor, err := vadu.ParseDecimal("1e30")
+	s.assert.NoError(err)
+	s.assert.Equal("1"+strings.Repeat("0", 30), valor.String())
+	_, err = vadu.ParseDecimal("1e200")
+	s.assert.ErrorIs(err, vadu.ErrDecimalOverflow)
+
+	brasileiros := map[string]string{
+		"1.234,56":     "1234.56",
+		"R$ 1.234,00":  "1234",
+		"(1.234,56)":   "-1234.56",
+		"12,5%":        "12.5",
+		"1.234.567":    "1234567",
+		"1.500":        "1500",
+		"12.345":       "12345",
+		"R$ 1.234":     "1234",
+		"0,1":          "0.1",
+		"-824.167,11":  "-824167.11",
+		"1234.5600000": "1234.56",
+	}
+	for texto, esperado := range brasileiros {
+		valor, err := vadu.ParseDecimalBR(texto)
+		s.assert.NoError(err, texto)
+		s.assert.Equal(vadu.MustDecimal(esperado), valor, texto)
+	}
+	_, err = vadu.ParseDecimalBR("1,2,3")
+	s.assert.ErrorIs(err, vadu.ErrDecimalInvalido)
+}
+
+// TestAritmetica verifica que as operações são exatas e o arredondamento nas divisões
+func (s *DecimalTestSuite) TestAritmetica() {
+	s.assert.Equal("0.3", vadu.MustDecimal("0.1").Add(vadu.MustDecimal("0.2")).String())
+	s.assert.Equal("-0.1", vadu.MustDecimal("0.1").Sub(vadu.MustDecimal("0.2")).String())
+	s.assert.Equal("1524.175425", vadu.MustDecimal("1234.5").Mul(vadu.MustDecimal("1.23465")).String())
+	s.assert.Equal(vadu.MustDecimal("1234.56"), vadu.NewDecimal(123456, 2))
+	s.assert.Equal(vadu.DecimalFromInt(2), vadu.MustDecimal("2.000"))
+
+	valor, err := vadu.DecimalFromInt(2).Div(vadu.DecimalFromInt(3), 4)
+	s.assert.NoError(err)
+	s.assert.Equal("0.6667", valor.String())
+	valor, err = vadu.DecimalFromInt(-2).Div(vadu.DecimalFromInt(3), 4)
+	s.assert.NoError(err)
+	s.assert.Equal("-0.6667", valor.String())
+	valor, err = vadu.MustDecimal("0.5").Div(vadu.MustDecimal("0.25"), vadu.EscalaIndicadores)
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.DecimalFromInt(2), valor)
+	_, err = vadu.DecimalFromInt(1).Div(vadu.Decimal{}, 2)
+	s.assert.ErrorIs(err, vadu.ErrDivisaoPorZero)
+
+	s.assert.Equal("2.35", vadu.MustDecimal("2.345").Round(2).String())
+	s.assert.Equal("-2.35", vadu.MustDecimal("-2.345").Round(2).String())
+	s.assert.Equal("2.3", vadu.MustDecimal("2.3").Round(4).String())
+
+	s.assert.Equal(-1, vadu.MustDecimal("-1.5").Sign())
+	s.assert.Equal(vadu.MustDecimal("1.5"), vadu.MustDecimal("-1.5").Abs())
+	s.assert.Equal(1, vadu.MustDecimal("10.01").Cmp(vadu.MustDecimal("10.001")))
+	s.assert.True(vadu.Decimal{}.IsZero())
+	s.assert.True(vadu.MustDecimal("1.50").Equal(vadu.NewDecimal(15, 1)))
+}
+
+// TestComparavel verifica que valores iguais são iguais com ==, inclusive dentro de DadosIntegracao
+func (s *DecimalTestSuite) TestComparavel() {
+	s.assert.True(vadu.MustDecimal("1.50") == vadu.NewDecimal(15, 1))
+	s.assert.True(vadu.MustDecimal("0.000") == vadu.Decimal{})
+	s.assert.True(vadu.MustDecimal("-2").Neg() == vadu.DecimalFromInt(2))
+	s.assert.False(vadu.MustDecimal("1.5") == vadu.MustDecimal("-1.5"))
+
+	a := vadu.DadosIntegracao{CNPJCPF: "98960887000164", AtivoTotal: vadu.MustDecimal("824167.11")}
+	b := vadu.DadosIntegracao{CNPJCPF: "98960887000164", AtivoTotal: vadu.MustDecimal("824167.10").Add(vadu.MustDecimal("0.01"))}
+	s.assert.True(a == b)
+	b.AtivoTotal = vadu.MustDecimal("824167.12")
+	s.assert.False(a == b)
+}
+
+// TestSemLimiteDeMagnitude verifica que as operações não transbordam nem perdem casas ao alinhar escalas
+func (s *DecimalTestSuite) TestSemLimiteDeMagnitude() {
+	s.assert.Equal("18000000000000000000", vadu.MustDecimal("9000000000000000000").Add(vadu.MustDecimal("9000000000000000000")).String())
+	s.assert.Equal("5000000.000000000000000001", vadu.MustDecimal("5000000").Add(vadu.MustDecimal("0.000000000000000001")).String())
+	s.assert.Equal("1000000000000000000000000000000", vadu.MustDecimal("1e15").Mul(vadu.MustDecimal("1e15")).String())
+	s.assert.Equal("-9223372036854775808000", vadu.NewDecimal(math.MinInt64, -3).String())
+	s.assert.Equal(vadu.MustDecimal("9223372036854775808"), vadu.NewDecimal(math.MinInt64, 0).Neg())
+
+	// Operandos não são alterados
+	a, b := vadu.MustDecimal("1.5"), vadu.MustDecimal("2.25")
+	a.Add(b)
+	a.Mul(b).Neg()
+	s.assert.Equal("1.5", a.String())
+	s.assert.Equal("2.25", b.String())
+}
+
+// TestFormatBR verifica a formatação no padrão brasileiro
+func (s *DecimalTestSuite) TestFormatBR() {
+	s.assert.Equal("1.234,56", vadu.MustDecimal("1234.56").FormatBR(2))
+	s.assert.Equal("1.234.567,00", vadu.DecimalFromInt(1234567).FormatBR(2))
+	s.assert.Equal("-824.167,1", vadu.MustDecimal("-824167.1").FormatBR(-1))
+	s.assert.Equal("0,67", vadu.MustDecimal("0.665").FormatBR(2))
+	s.assert.Equal("100", vadu.DecimalFromInt(100).FormatBR(0))
+}
+
+// TestDecimalJSON verifica a serialização exata e a leitura de números, textos e null
+func (s *DecimalTestSuite) TestDecimalJSON() {
+	dados := vadu.DadosIntegracao{AtivoTotal: vadu.MustDecimal("1234.56"), LiquidezSeca: vadu.MustDecimal("1.3333333333")}
+	data, err := json.Marshal(dados)
+	s.assert.NoError(err)
+	s.assert.Contains(string(data), `"ativoTotal":1234.56`)
+	s.assert.Contains(string(data), `"liquidezSeca":1.3333333333`)
+	s.assert.Contains(string(data), `"passivoTotal":0`)
+
+	var lido vadu.DadosIntegracao
+	s.assert.NoError(json.Unmarshal(data, &lido))
+	s.assert.Equal(dados, lido)
+
+	s.assert.NoError(json.Unmarshal([]byte(`{"ativoTotal":"1.234,56","passivoTotal":"99.5","emprestimo":null}`), &lido))
+	s.assert.Equal(vadu.MustDecimal("1234.56"), lido.AtivoTotal)
+	s.assert.Equal(vadu.MustDecimal("99.5"), lido.PassivoTotal)
+	s.assert.True(lido.Emprestimo.IsZero())
+
+	// Textos seguem as mesmas regras das planilhas
+	s.assert.NoError(json.Unmarshal([]byte(`{"ativoTotal":"1.500","passivoTotal":"1234.567","emprestimo":"1e3"}`), &lido))
+	s.assert.Equal(vadu.DecimalFromInt(1500), lido.AtivoTotal)
+	s.assert.Equal(vadu.MustDecimal("1234.567"), lido.PassivoTotal)
+	s.assert.Equal(vadu.DecimalFromInt(1000), lido.Emprestimo)
+
+	s.assert.Error(json.Unmarshal([]byte(`{"ativoTotal":"abc"}`), &lido))
+}
+
+// TestMigracaoFloat verifica a conversão entre DadosIntegracaoFloat e DadosIntegracao
+func (s *DecimalTestSuite) TestMigracaoFloat() {
+	antigo := vadu.DadosIntegracaoFloat{
+		CNPJCPF:          "98960887000164",
+		AtivoTotal:       824167.11,
+		LiquidezSeca:     1234.5600000000002,
+		ScoreExterno:     750,
+		RatingExterno:    "A",
+		LucroLiquido:     -30,
+		LiquidezCorrente: 2,
+	}
+	dados, err := antigo.DadosIntegracao()
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.Documento("98960887000164"), dados.CNPJCPF)
+	s.assert.Equal(vadu.MustDecimal("824167.11"), dados.AtivoTotal)
+	s.assert.Equal(vadu.MustDecimal("1234.5600000000002"), dados.LiquidezSeca)
+	s.assert.Equal(vadu.MustDecimal("1234.56"), dados.LiquidezSeca.Round(2))
+	s.assert.Equal(vadu.MustDecimal("-30"), dados.LucroLiquido)
+	s.assert.Equal(750, dados.ScoreExterno)
+	s.assert.Equal("A", dados.RatingExterno)
+
+	s.assert.Equal(824167.11, dados.Float().AtivoTotal)
+	s.assert.Equal(750, dados.Float().ScoreExterno)
+
+	antigo.Despesas = math.NaN()
+	_, err = antigo.DadosIntegracao()
+	s.assert.ErrorIs(err, vadu.ErrDecimalInvalido)
+}
@@ -0,0 +1,376 @@
+package vadu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TipoDocumento classifica um documento como CPF ou CNPJ.
+type TipoDocumento int
+
+const (
+	// TipoDocumentoDesconhecido indica um documento que não tem formato de CPF nem de CNPJ.
+	TipoDocumentoDesconhecido TipoDocumento = iota
+	// TipoDocumentoCPF indica um CPF (11 dígitos).
+	TipoDocumentoCPF
+	// TipoDocumentoCNPJ indica um CNPJ (14 posições).
+	TipoDocumentoCNPJ
+)
+
+// String retorna o nome do tipo de documento.
+func (t TipoDocumento) String() string {
+	switch t {
+	case TipoDocumentoCPF:
+		return "CPF"
+	case TipoDocumentoCNPJ:
+		return "CNPJ"
+	default:
+		return "desconhecido"
+	}
+}
+
+var (
+	// ErrDocumentoInvalido indica um documento com tamanho ou caracteres inválidos.
+	ErrDocumentoInvalido = errors.New("documento inválido")
+	// ErrDigitoVerificador indica um documento cujos dígitos verificadores não conferem.
+	ErrDigitoVerificador = errors.New("dígito verificador inválido")
+)
+
+// NormalizaDocumento remove a máscara de um CPF ou CNPJ (pontos, barras, hífens e espaços)
+// e converte letras para maiúsculas, como exigido pelo CNPJ alfanumérico.
+// Nenhuma validação é feita; utilize NewDocumento, NewCNPJ ou NewCPF para validar.
+func NormalizaDocumento(valor string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '/', '-', ' ', '\t':
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, strings.TrimSpace(valor))
+}
+
+// somenteDigitos informa se o valor contém apenas dígitos de 0 a 9.
+func somenteDigitos(valor string) bool {
+	for _, r := range valor {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// somenteAlfanumericos informa se o valor contém apenas dígitos e letras maiúsculas de A a Z.
+func somenteAlfanumericos(valor string) bool {
+	for _, r := range valor {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// formatoCNPJ informa se o valor tem o formato de um CNPJ numérico ou alfanumérico:
+// 12 posições alfanuméricas seguidas de 2 dígitos verificadores numéricos.
+func formatoCNPJ(valor string) bool {
+	return len(valor) == 14 && somenteAlfanumericos(valor[:12]) && somenteDigitos(valor[12:])
+}
+
+// digitoVerificador calcula o dígito verificador módulo 11 para os pesos informados.
+// Cada caractere vale o seu código ASCII menos 48, o que mantém o cálculo
+// tradicional para dígitos e atribui os valores 17 a 42 às letras de A a Z,
+// conforme a regra do CNPJ alfanumérico.
+func digitoVerificador(valor string, pesos []int) byte {
+	soma := 0
+	for i, peso := range pesos {
+		soma += int(valor[i]-'0') * peso
+	}
+	resto := soma % 11
+	if resto < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - resto)
+}
+
+// todosIguais informa se todos os caracteres do valor são iguais (ex.: 000.000.000-00).
+func todosIguais(valor string) bool {
+	return strings.Count(valor, valor[:1]) == len(valor)
+}
+
+var (
+	pesosCNPJ1 = []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	pesosCNPJ2 = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	pesosCPF1  = []int{10, 9, 8, 7, 6, 5, 4, 3, 2}
+	pesosCPF2  = []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+// CNPJ representa um CNPJ normalizado, sem máscara. São aceitos tanto o formato
+// numérico quanto o alfanumérico (letras nas 12 primeiras posições).
+type CNPJ string
+
+// NewCNPJ normaliza e valida um CNPJ informado com ou sem máscara.
+func NewCNPJ(valor string) (CNPJ, error) {
+	cnpj := CNPJ(NormalizaDocumento(valor))
+	if err := cnpj.Valida(); err != nil {
+		return "", err
+	}
+	return cnpj, nil
+}
+
+// Valida verifica o tamanho, os caracteres e os dígitos verificadores do CNPJ.
+func (c CNPJ) Valida() error {
+	valor := string(c)
+	if !formatoCNPJ(valor) {
+		return fmt.Errorf("%w: CNPJ deve conter 12 posições alfanuméricas e 2 dígitos verificadores: %q", ErrDocumentoInvalido, valor)
+	}
+	if todosIguais(valor) {
+		return fmt.Errorf("%w: CNPJ com dígitos repetidos: %q", ErrDocumentoInvalido, valor)
+	}
+	dv1 := digitoVerificador(valor, pesosCNPJ1)
+	dv2 := digitoVerificador(valor[:12]+string(dv1), pesosCNPJ2)
+	if valor[12] != dv1 || valor[13] != dv2 {
+		return fmt.Errorf("%w: CNPJ %q", ErrDigitoVerificador, valor)
+	}
+	return nil
+}
+
+// Alfanumerico informa se o CNPJ utiliza o formato alfanumérico.
+func (c CNPJ) Alfanumerico() bool {
+	return formatoCNPJ(string(c)) && !somenteDigitos(string(c))
+}
+
+// String retorna o CNPJ sem máscara.
+func (c CNPJ) String() string {
+	return string(c)
+}
+
+// Formatado retorna o CNPJ no formato 00.000.000/0000-00, também aplicado ao
+// formato alfanumérico. Valores com tamanho inválido são retornados sem alteração.
+func (c CNPJ) Formatado() string {
+	v := string(c)
+	if len(v) != 14 {
+		return v
+	}
+	return v[:2] + "." + v[2:5] + "." + v[5:8] + "/" + v[8:12] + "-" + v[12:]
+}
+
+// MarshalJSON serializa o CNPJ sem máscara.
+func (c CNPJ) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c))
+}
+
+// UnmarshalJSON aceita o CNPJ com ou sem máscara e rejeita valores inválidos.
+func (c *CNPJ) UnmarshalJSON(data []byte) error {
+	var valor string
+	if err := json.Unmarshal(data, &valor); err != nil {
+		return err
+	}
+	cnpj, err := NewCNPJ(valor)
+	if err != nil {
+		return err
+	}
+	*c = cnpj
+	return nil
+}
+
+// CPF representa um CPF normalizado, sem máscara.
+type CPF string
+
+// NewCPF normaliza e valida um CPF informado com ou sem máscara.
+func NewCPF(valor string) (CPF, error) {
+	cpf := CPF(NormalizaDocumento(valor))
+	if err := cpf.Valida(); err != nil {
+		return "", err
+	}
+	return cpf, nil
+}
+
+// Valida verifica o tamanho, os caracteres e os dígitos verificadores do CPF.
+func (c CPF) Valida() error {
+	valor := string(c)
+	if len(valor) != 11 || !somenteDigitos(valor) {
+		return fmt.Errorf("%w: CPF deve conter 11 dígitos: %q", ErrDocumentoInvalido, valor)
+	}
+	if todosIguais(valor) {
+		return fmt.Errorf("%w: CPF com dígitos repetidos: %q", ErrDocumentoInvalido, valor)
+	}
+	dv1 := digitoVerificador(valor, pesosCPF1)
+	dv2 := digitoVerificador(valor[:9]+string(dv1), pesosCPF2)
+	if valor[9] != dv1 || valor[10] != dv2 {
+		return fmt.Errorf("%w: CPF %q", ErrDigitoVerificador, valor)
+	}
+	return nil
+}
+
+// String retorna o CPF sem máscara.
+func (c CPF) String() string {
+	return string(c)
+}
+
+// Formatado retorna o CPF no formato 000.000.000-00. Valores com tamanho
+// inválido são retornados sem alteração.
+func (c CPF) Formatado() string {
+	v := string(c)
+	if len(v) != 11 {
+		return v
+	}
+	return v[:3] + "." + v[3:6] + "." + v[6:9] + "-" + v[9:]
+}
+
+// MarshalJSON serializa o CPF sem máscara.
+func (c CPF) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c))
+}
+
+// UnmarshalJSON aceita o CPF com ou sem máscara e rejeita valores inválidos.
+func (c *CPF) UnmarshalJSON(data []byte) error {
+	var valor string
+	if err := json.Unmarshal(data, &valor); err != nil {
+		return err
+	}
+	cpf, err := NewCPF(valor)
+	if err != nil {
+		return err
+	}
+	*c = cpf
+	return nil
+}
+
+// Documento representa um CPF ou um CNPJ normalizado. O tipo é identificado
+// pelo formato do valor (veja Tipo). Diferente de CNPJ e CPF, a decodificação
+// JSON de um Documento apenas normaliza o valor, sem rejeitá-lo, para que
+// respostas da API com documentos inesperados continuem legíveis.
+type Documento string
+
+// NewDocumento normaliza, classifica e valida um CPF ou CNPJ.
+func NewDocumento(valor string) (Documento, error) {
+	documento := Documento(NormalizaDocumento(valor))
+	if err := documento.Valida(); err != nil {
+		return "", err
+	}
+	return documento, nil
+}
+
+// Tipo classifica o documento como CPF ou CNPJ a partir do seu formato.
+func (d Documento) Tipo() TipoDocumento {
+	valor := string(d)
+	switch {
+	case len(valor) == 11 && somenteDigitos(valor):
+		return TipoDocumentoCPF
+	case formatoCNPJ(valor):
+		return TipoDocumentoCNPJ
+	default:
+		return TipoDocumentoDesconhecido
+	}
+}
+
+// CNPJ retorna o documento como CNPJ, caso ele tenha formato de CNPJ.
+func (d Documento) CNPJ() (CNPJ, bool) {
+	if d.Tipo() != TipoDocumentoCNPJ {
+		return "", false
+	}
+	return CNPJ(d), true
+}
+
+// CPF retorna o documento como CPF, caso ele tenha formato de CPF.
+func (d Documento) CPF() (CPF, bool) {
+	if d.Tipo() != TipoDocumentoCPF {
+		return "", false
+	}
+	return CPF(d), true
+}
+
+// Valida verifica se o documento é um CPF ou um CNPJ válido.
+func (d Documento) Valida() error {
+	switch d.Tipo() {
+	case TipoDocumentoCPF:
+		return CPF(d).Valida()
+	case TipoDocumentoCNPJ:
+		return CNPJ(d).Valida()
+	default:
+		return fmt.Errorf("%w: %q não é um CPF nem um CNPJ", ErrDocumentoInvalido, string(d))
+	}
+}
+
+// String retorna o documento sem máscara.
+func (d Documento) String() string {
+	return string(d)
+}
+
+// Formatado retorna o documento com a máscara de CPF ou CNPJ.
+func (d Documento) Formatado() string {
+	switch d.Tipo() {
+	case TipoDocumentoCPF:
+		return CPF(d).Formatado()
+	case TipoDocumentoCNPJ:
+		return CNPJ(d).Formatado()
+	default:
+		return string(d)
+	}
+}
+
+// MarshalJSON serializa o documento sem máscara, mesmo que ele tenha sido
+// construído diretamente a partir de um valor mascarado.
+func (d Documento) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NormalizaDocumento(string(d)))
+}
+
+// UnmarshalJSON normaliza o documento recebido. O valor null é decodificado como vazio.
+func (d *Documento) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = ""
+		return nil
+	}
+	var valor string
+	if err := json.Unmarshal(data, &valor); err != nil {
+		return err
+	}
+	*d = Documento(NormalizaDocumento(valor))
+	return nil
+}
+
+// NormalizaDocumentos converte uma lista de documentos em texto, com ou sem máscara, em Documentos.
+func NormalizaDocumentos(valores []string) []Documento {
+	documentos := make([]Documento, 0, len(valores))
+	for _, valor := range valores {
+		documentos = append(documentos, Documento(NormalizaDocumento(valor)))
+	}
+	return documentos
+}
+
+// ErrCNPJAlfanumericoNaoSuportado indica que o cliente não está configurado para aceitar CNPJs alfanuméricos.
+var ErrCNPJAlfanumericoNaoSuportado = errors.New("CNPJ alfanumérico não habilitado para este cliente")
+
+// Alfanumerico informa se o documento é um CNPJ no formato alfanumérico.
+func (d Documento) Alfanumerico() bool {
+	cnpj, ok := d.CNPJ()
+	return ok && cnpj.Alfanumerico()
+}
+
+// ValidaDocumento normaliza e valida um CPF ou CNPJ respeitando a configuração
+// AceitaCNPJAlfanumerico da sessão do cliente.
+func (vc *VaduClient) ValidaDocumento(valor string) (Documento, error) {
+	documento, err := NewDocumento(valor)
+	if err != nil {
+		return "", err
+	}
+	if documento.Alfanumerico() && !vc.session.AceitaCNPJAlfanumerico {
+		return "", fmt.Errorf("%w: %q", ErrCNPJAlfanumericoNaoSuportado, documento.String())
+	}
+	return documento, nil
+}
+
+// verificaCNPJAlfanumerico rejeita CNPJs alfanuméricos quando a sessão não os aceita.
+func (vc *VaduClient) verificaCNPJAlfanumerico(documentos ...Documento) error {
+	if vc.session.AceitaCNPJAlfanumerico {
+		return nil
+	}
+	for _, documento := range documentos {
+		if documento.Alfanumerico() {
+			return fmt.Errorf("%w: %q", ErrCNPJAlfanumericoNaoSuportado, documento.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package vadu_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DocumentoTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestDocumentoTestSuite(t *testing.T) {
+	suite.Run(t, new(DocumentoTestSuite))
+}
+
+func (s *DocumentoTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestCNPJ verifica normalização, validação e formatação de CNPJs
+func (s *DocumentoTestSuite) TestCNPJ() {
+	cnpj, err := vadu.NewCNPJ("98.960.887/0001-64")
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.CNPJ("98960887000164"), cnpj)
+	s.assert.Equal("98.960.887/0001-64", cnpj.Formatado())
+
+	_, err = vadu.NewCNPJ("98960887000165")
+	s.assert.ErrorIs(err, vadu.ErrDigitoVerificador)
+
+	_, err = vadu.NewCNPJ("11111111111111")
+	s.assert.ErrorIs(err, vadu.ErrDocumentoInvalido)
+
+	_, err = vadu.NewCNPJ("9896088700016")
+	s.assert.ErrorIs(err, vadu.ErrDocumentoInvalido)
+}
+
+// TestCPF verifica normalização, validação e formatação de CPFs
+func (s *DocumentoTestSuite) TestCPF() {
+	cpf, err := vadu.NewCPF("529.982.247-25")
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.CPF("52998224725"), cpf)
+	s.assert.Equal("529.982.247-25", cpf.Formatado())
+
+	_, err = vadu.NewCPF("52998224724")
+	s.assert.ErrorIs(err, vadu.ErrDigitoVerificador)
+}
+
+// TestDocumentoClassificacao verifica a classificação entre CPF e CNPJ
+func (s *DocumentoTestSuite) TestDocumentoClassificacao() {
+	documento, err := vadu.NewDocumento("98.960.887/0001-64")
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.TipoDocumentoCNPJ, documento.Tipo())
+	cnpj, ok := documento.CNPJ()
+	s.assert.True(ok)
+	s.assert.Equal(vadu.CNPJ("98960887000164"), cnpj)
+	_, ok = documento.CPF()
+	s.assert.False(ok)
+
+	documento, err = vadu.NewDocumento("52998224725")
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.TipoDocumentoCPF, documento.Tipo())
+	s.assert.Equal("529.982.247-25", documento.Formatado())
+
+	_, err = vadu.NewDocumento("123")
+	s.assert.ErrorIs(err, vadu.ErrDocumentoInvalido)
+	s.assert.Equal(vadu.TipoDocumentoDesconhecido, vadu.Documento("123").Tipo())
+}
+
+// TestDocumentoJSON verifica a serialização e a decodificação tolerante de documentos
+func (s *DocumentoTestSuite) TestDocumentoJSON() {
+	var resumo vadu.ResumoCNPJ
+	err := json.Unmarshal([]byte(`{"cnpj_cpf": "98.960.887/0001-64"}`), &resumo)
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.Documento("98960887000164"), resumo.CNPJCPF)
+
+	err = json.Unmarshal([]byte(`{"cnpj_cpf": null}`), &resumo)
+	s.assert.NoError(err)
+	s.assert.Empty(resumo.CNPJCPF)
+
+	data, err := json.Marshal(vadu.EnviaCNPJsRequest{ListaCNPJCPF: []vadu.Documento{"52998224725"}})
+	s.assert.NoError(err)
+	s.assert.Contains(string(data), `"lista_cnpj_cpf":["52998224725"]`)
+
+	var cnpj vadu.CNPJ
+	s.assert.NoError(json.Unmarshal([]byte(`"98.960.887/0001-64"`), &cnpj))
+	s.assert.Equal(vadu.CNPJ("98960887000164"), cnpj)
+	s.assert.ErrorIs(json.Unmarshal([]byte(`"98960887000165"`), &cnpj), vadu.ErrDigitoVerificador)
+}
+
+// TestCNPJAlfanumerico verifica o novo formato de CNPJ alfanumérico
+func (s *DocumentoTestSuite) TestCNPJAlfanumerico() {
+	cnpj, err := vadu.NewCNPJ("12.abc.345/01de-35")
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.CNPJ("12ABC34501DE35"), cnpj)
+	s.assert.True(cnpj.Alfanumerico())
+	s.assert.Equal("12.ABC.345/01DE-35", cnpj.Formatado())
+
+	_, err = vadu.NewCNPJ("12ABC34501DE36")
+	s.assert.ErrorIs(err, vadu.ErrDigitoVerificador)
+
+	// Os dígitos verificadores continuam numéricos
+	_, err = vadu.NewCNPJ("12ABC34501DE3A")
+	s.assert.ErrorIs(err, vadu.ErrDocumentoInvalido)
+
+	documento := vadu.Documento("12ABC34501DE35")
+	s.assert.Equal(vadu.TipoDocumentoCNPJ, documento.Tipo())
+	s.assert.True(documento.Alfanumerico())
+	s.assert.False(vadu.Documento("98960887000164").Alfanumerico())
+}
+
+// TestCNPJAlfanumericoPorCliente verifica a habilitação do formato alfanumérico por cliente
+func (s *DocumentoTestSuite) TestCNPJAlfanumericoPorCliente() {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	session, err := vadu.NewSession(vadu.Config{AceitaCNPJAlfanumerico: vadu.Bool(false)})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(mock.EnviaCNPJsParaAnaliseMock(), *session, logger)
+
+	_, err = vaduClient.ValidaDocumento("12.ABC.345/01DE-35")
+	s.assert.ErrorIs(err, vadu.ErrCNPJAlfanumericoNaoSuportado)
+
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", context.Background()).Return("mocked_token", nil)
+	_, err = vaduClient.EnviaCNPJsParaAnalise(context.Background(), "33011770000199", 10802, []string{"12ABC34501DE35"}, nil, authentication)
+	s.assert.ErrorIs(err, vadu.ErrCNPJAlfanumericoNaoSuportado)
+
+	session, err = vadu.NewSession(vadu.Config{AceitaCNPJAlfanumerico: vadu.Bool(true)})
+	s.assert.NoError(err)
+	vaduClient = vadu.NewVaduClient(mock.EnviaCNPJsParaAnaliseMock(), *session, logger)
+
+	documento, err := vaduClient.ValidaDocumento("12.ABC.345/01DE-35")
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.Documento("12ABC34501DE35"), documento)
+
+	_, err = vaduClient.EnviaCNPJsParaAnalise(context.Background(), "33011770000199", 10802, []string{"12ABC34501DE35"}, nil, authentication)
+	s.assert.NoError(err)
+}
@@ -0,0 +1,122 @@
+package vadu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TipoDadosRetorno é o código do conteúdo enviado pelo Vadu ao PostBack quando a análise é
+// concluída. Os códigos são definidos pela API e não são documentados no SDK, portanto não há
+// constantes nomeadas; os exemplos e testes do SDK utilizam o código 1.
+type TipoDadosRetorno int
+
+// String retorna o código em texto.
+func (t TipoDadosRetorno) String() string {
+	return strconv.Itoa(int(t))
+}
+
+// Valido informa se o código foi informado, ou seja, se é positivo.
+func (t TipoDadosRetorno) Valido() bool {
+	return t > 0
+}
+
+// UnmarshalJSON aceita números, números entre aspas e null. Códigos desconhecidos são preservados.
+func (t *TipoDadosRetorno) UnmarshalJSON(data []byte) error {
+	valor, err := decodificaCodigo(data)
+	if err != nil {
+		return fmt.Errorf("tipoDadosRetorno: %w", err)
+	}
+	*t = TipoDadosRetorno(valor)
+	return nil
+}
+
+// OrigemConsultaSerasa é o código da origem dos dados do Serasa utilizados na análise de um
+// documento. Os códigos são definidos pela API e não são documentados no SDK, portanto não há
+// constantes nomeadas; a descrição vem em ResumoCNPJBase.OrigemConsultaSerasaTexto (ver
+// OrigemConsultaSerasaDescricao).
+type OrigemConsultaSerasa int
+
+// String retorna o código em texto.
+func (o OrigemConsultaSerasa) String() string {
+	return strconv.Itoa(int(o))
+}
+
+// UnmarshalJSON aceita números, números entre aspas e null. Códigos desconhecidos são preservados.
+func (o *OrigemConsultaSerasa) UnmarshalJSON(data []byte) error {
+	valor, err := decodificaCodigo(data)
+	if err != nil {
+		return fmt.Errorf("origem_consulta_serasa: %w", err)
+	}
+	*o = OrigemConsultaSerasa(valor)
+	return nil
+}
+
+// decodificaCodigo decodifica um código numérico de forma tolerante: null e texto vazio
+// resultam em 0 e números, inclusive com casas decimais zeradas ou entre aspas, são preservados.
+func decodificaCodigo(data []byte) (int, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return 0, nil
+	}
+
+	texto := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &texto); err != nil {
+			return 0, err
+		}
+		texto = strings.TrimSpace(texto)
+		if texto == "" {
+			return 0, nil
+		}
+	}
+
+	if valor, err := strconv.Atoi(texto); err == nil {
+		return valor, nil
+	}
+	if valor, err := strconv.ParseFloat(texto, 64); err == nil && valor == float64(int(valor)) {
+		return int(valor), nil
+	}
+	return 0, fmt.Errorf("valor não reconhecido: %s", string(data))
+}
+
+// ErrPostBackInvalido indica um PostBack com URL ou tipo de retorno inválido.
+var ErrPostBackInvalido = errors.New("postBack inválido")
+
+// NewPostBack cria um PostBack validando a URL e o tipo de retorno.
+func NewPostBack(urlPostBack, token string, tipo TipoDadosRetorno) (*PostBack, error) {
+	postBack := &PostBack{
+		URL:              urlPostBack,
+		Token:            token,
+		TipoDadosRetorno: tipo,
+	}
+	if err := postBack.Valida(); err != nil {
+		return nil, err
+	}
+	return postBack, nil
+}
+
+// Valida verifica se a URL é absoluta com esquema http ou https e se o tipo de retorno foi
+// informado. É aplicada por NewPostBack; os métodos de envio não validam o PostBack.
+func (p PostBack) Valida() error {
+	endereco, err := url.Parse(p.URL)
+	if err != nil || endereco.Host == "" || (endereco.Scheme != "http" && endereco.Scheme != "https") {
+		return fmt.Errorf("%w: URL deve ser absoluta com esquema http ou https: %q", ErrPostBackInvalido, p.URL)
+	}
+	if !p.TipoDadosRetorno.Valido() {
+		return fmt.Errorf("%w: tipo de dados de retorno inválido: %s", ErrPostBackInvalido, p.TipoDadosRetorno)
+	}
+	return nil
+}
+
+// OrigemConsultaSerasaDescricao retorna o texto da origem enviado pela API ou, na ausência dele, o código.
+func (r ResumoCNPJBase) OrigemConsultaSerasaDescricao() string {
+	if r.OrigemConsultaSerasaTexto != "" {
+		return r.OrigemConsultaSerasaTexto
+	}
+	return r.OrigemConsultaSerasa.String()
+}
@@ -0,0 +1,86 @@
+package vadu_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnumsTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestEnumsTestSuite(t *testing.T) {
+	suite.Run(t, new(EnumsTestSuite))
+}
+
+func (s *EnumsTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestNewPostBack verifica a validação do PostBack
+func (s *EnumsTestSuite) TestNewPostBack() {
+	postBack, err := vadu.NewPostBack("https://webhook.site/4c8e0993", "Bearer token", vadu.TipoDadosRetorno(1))
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.TipoDadosRetorno(1), postBack.TipoDadosRetorno)
+
+	data, err := json.Marshal(postBack)
+	s.assert.NoError(err)
+	s.assert.Contains(string(data), `"tipoDadosRetorno":1`)
+
+	_, err = vadu.NewPostBack("webhook.site/4c8e0993", "", vadu.TipoDadosRetorno(1))
+	s.assert.ErrorIs(err, vadu.ErrPostBackInvalido)
+
+	_, err = vadu.NewPostBack("https://webhook.site/4c8e0993", "", 0)
+	s.assert.ErrorIs(err, vadu.ErrPostBackInvalido)
+
+	_, err = vadu.NewPostBack("https://webhook.site/4c8e0993", "", vadu.TipoDadosRetorno(-1))
+	s.assert.ErrorIs(err, vadu.ErrPostBackInvalido)
+}
+
+// TestEnvioNaoValidaPostBack verifica que o envio mantém o PostBack informado sem validá-lo
+func (s *EnumsTestSuite) TestEnvioNaoValidaPostBack() {
+	ctx := context.Background()
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", ctx).Return("mocked_token", nil)
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(mock.EnviaCNPJsParaAnaliseMock(), *session, logger)
+
+	postBack := &vadu.PostBack{URL: "webhook.site/4c8e0993"}
+	_, err = vaduClient.EnviaCNPJsParaAnalise(ctx, "33011770000199", 10802, []string{"98960887000164"}, postBack, authentication)
+	s.assert.NoError(err)
+}
+
+// TestDecodificacaoTolerante verifica a decodificação de códigos conhecidos, desconhecidos e nulos
+func (s *EnumsTestSuite) TestDecodificacaoTolerante() {
+	var resumo vadu.ResumoCNPJ
+	s.assert.NoError(json.Unmarshal([]byte(`{"origem_consulta_serasa": 0, "origem_consulta_serasa_texto": "Consulta Serasa"}`), &resumo))
+	s.assert.Equal(vadu.OrigemConsultaSerasa(0), resumo.OrigemConsultaSerasa)
+	s.assert.Equal("Consulta Serasa", resumo.OrigemConsultaSerasaDescricao())
+
+	s.assert.NoError(json.Unmarshal([]byte(`{"origem_consulta_serasa": "7", "origem_consulta_serasa_texto": ""}`), &resumo))
+	s.assert.Equal(vadu.OrigemConsultaSerasa(7), resumo.OrigemConsultaSerasa)
+	s.assert.Equal("7", resumo.OrigemConsultaSerasaDescricao())
+
+	s.assert.NoError(json.Unmarshal([]byte(`{"origem_consulta_serasa": null}`), &resumo))
+	s.assert.Equal(vadu.OrigemConsultaSerasa(0), resumo.OrigemConsultaSerasa)
+
+	var postBack vadu.PostBack
+	s.assert.NoError(json.Unmarshal([]byte(`{"tipoDadosRetorno": "2"}`), &postBack))
+	s.assert.Equal(vadu.TipoDadosRetorno(2), postBack.TipoDadosRetorno)
+	s.assert.Equal("2", postBack.TipoDadosRetorno.String())
+	s.assert.NoError(json.Unmarshal([]byte(`{"tipoDadosRetorno": 1.0}`), &postBack))
+	s.assert.Equal(vadu.TipoDadosRetorno(1), postBack.TipoDadosRetorno)
+	s.assert.Error(json.Unmarshal([]byte(`{"tipoDadosRetorno": "outro"}`), &postBack))
+}
@@ -0,0 +1,660 @@
+package vadu
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EstadoJob identifica o estado de um job de análise.
+type EstadoJob string
+
+const (
+	// EstadoPendente indica que o job foi persistido e o envio à API está em andamento. Um job
+	// pendente após um reinício teve o envio interrompido: a análise pode ter sido criada sem
+	// que o ID fosse registrado; verifique na API antes de reenviar.
+	EstadoPendente EstadoJob = "pendente"
+	// EstadoEnviado indica que os documentos foram aceitos pela API e a análise ainda não foi acompanhada.
+	EstadoEnviado EstadoJob = "enviado"
+	// EstadoProcessando indica que a análise está em andamento. Falhas transitórias no
+	// acompanhamento mantêm o job neste estado, com o último erro em Job.Erro.
+	EstadoProcessando EstadoJob = "processando"
+	// EstadoConcluido indica que a análise foi concluída e o resultado registrado.
+	EstadoConcluido EstadoJob = "concluido"
+	// EstadoFalhou indica que o envio foi recusado ou que a API respondeu com um erro
+	// definitivo (RespostaAPIError.Definitivo) no acompanhamento ou na coleta do resultado.
+	EstadoFalhou EstadoJob = "falhou"
+)
+
+// Final informa se o estado é definitivo.
+func (e EstadoJob) Final() bool {
+	return e == EstadoConcluido || e == EstadoFalhou
+}
+
+// ErrJobNaoEncontrado indica que o job não existe no JobStore.
+var ErrJobNaoEncontrado = errors.New("job não encontrado")
+
+// Job registra uma submissão de análise gerenciada pelo JobManager.
+type Job struct {
+	ID                  string          `json:"id"`
+	AnaliseID           int             `json:"analise_id,omitempty"`
+	CNPJEmpresa         Documento       `json:"cnpj_empresa"`
+	IDGrupoAnalise      int             `json:"id_grupo_analise"`
+	Documentos          []Documento     `json:"documentos"`
+	Estado              EstadoJob       `json:"estado"`
+	PercentualConcluido int             `json:"percentual_concluido"`
+	Resultado           *AnalysisResult `json:"resultado,omitempty"` // Preenchido quando concluído
+	Erro                string          `json:"erro,omitempty"`      // Motivo da falha ou último erro transitório do acompanhamento
+	CriadoEm            time.Time       `json:"criado_em"`
+	AtualizadoEm        time.Time       `json:"atualizado_em"`
+}
+
+// JobStore persiste os jobs do JobManager.
+type JobStore interface {
+	// Save grava o job, substituindo a versão anterior com o mesmo ID.
+	Save(ctx context.Context, job Job) error
+	// Get retorna o job pelo ID ou ErrJobNaoEncontrado.
+	Get(ctx context.Context, id string) (*Job, error)
+	// List retorna os jobs nos estados informados (todos, se nenhum for informado), ordenados pela criação.
+	List(ctx context.Context, estados ...EstadoJob) ([]Job, error)
+}
+
+// filtraJobs retorna os jobs nos estados informados, ordenados pela criação.
+func filtraJobs(jobs []Job, estados []EstadoJob) []Job {
+	var filtrados []Job
+	for _, job := range jobs {
+		if len(estados) == 0 {
+			filtrados = append(filtrados, job)
+			continue
+		}
+		for _, estado := range estados {
+			if job.Estado == estado {
+				filtrados = append(filtrados, job)
+				break
+			}
+		}
+	}
+	sort.SliceStable(filtrados, func(i, j int) bool { return filtrados[i].CriadoEm.Before(filtrados[j].CriadoEm) })
+	return filtrados
+}
+
+// MemoryJobStore mantém os jobs em memória. Não sobrevive a reinícios; útil para testes.
+type MemoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+// NewMemoryJobStore cria um JobStore em memória.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]Job)}
+}
+
+// Save grava o job.
+func (m *MemoryJobStore) Save(ctx context.Context, job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = job
+	return nil
+}
+
+// Get retorna o job pelo ID.
+func (m *MemoryJobStore) Get(ctx context.Context, id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNaoEncontrado, id)
+	}
+	return &job, nil
+}
+
+// List retorna os jobs nos estados informados.
+func (m *MemoryJobStore) List(ctx context.Context, estados ...EstadoJob) ([]Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	return filtraJobs(jobs, estados), nil
+}
+
+// FileJobStore grava os jobs em um arquivo JSON Lines. Cada Save acrescenta a versão do job
+// ao final do arquivo; na leitura prevalece a última versão de cada ID. O arquivo cresce a cada
+// atualização de progresso e Get e List o leem por inteiro; chame Compacta periodicamente (por
+// exemplo, na inicialização, antes de Resume) para manter apenas a última versão de cada job.
+type FileJobStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileJobStore cria um JobStore que grava no arquivo informado.
+func NewFileJobStore(path string) *FileJobStore {
+	return &FileJobStore{path: path}
+}
+
+// Save acrescenta a versão do job ao final do arquivo e força a gravação em disco.
+func (f *FileJobStore) Save(ctx context.Context, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Get retorna a última versão do job pelo ID.
+func (f *FileJobStore) Get(ctx context.Context, id string) (*Job, error) {
+	jobs, err := f.carrega()
+	if err != nil {
+		return nil, err
+	}
+	job, ok := jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNaoEncontrado, id)
+	}
+	return &job, nil
+}
+
+// List retorna a última versão dos jobs nos estados informados.
+func (f *FileJobStore) List(ctx context.Context, estados ...EstadoJob) ([]Job, error) {
+	jobs, err := f.carrega()
+	if err != nil {
+		return nil, err
+	}
+	lista := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		lista = append(lista, job)
+	}
+	return filtraJobs(lista, estados), nil
+}
+
+// Compacta reescreve o arquivo apenas com a última versão de cada job, ordenada pela criação.
+// O novo conteúdo é gravado em um arquivo temporário que substitui o original.
+func (f *FileJobStore) Compacta(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	jobs, err := f.leArquivo()
+	if err != nil {
+		return err
+	}
+	lista := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		lista = append(lista, job)
+	}
+
+	temporario := f.path + ".tmp"
+	file, err := os.OpenFile(temporario, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	for _, job := range filtraJobs(lista, nil) {
+		data, err := json.Marshal(job)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		writer.Write(append(data, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporario, f.path)
+}
+
+// carrega lê o arquivo e retorna a última versão de cada job.
+func (f *FileJobStore) carrega() (map[string]Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.leArquivo()
+}
+
+// leArquivo lê o arquivo e retorna a última versão de cada job. Deve ser chamado com f.mu travado.
+func (f *FileJobStore) leArquivo() (map[string]Job, error) {
+	jobs := make(map[string]Job)
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return jobs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for linha := 1; scanner.Scan(); linha++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(scanner.Bytes(), &job); err != nil {
+			return nil, fmt.Errorf("job inválido na linha %d: %w", linha, err)
+		}
+		jobs[job.ID] = job
+	}
+	return jobs, scanner.Err()
+}
+
+// SQLJobStore grava os jobs em uma tabela acessada via database/sql.
+// A tabela pode ser criada com CreateTable.
+type SQLJobStore struct {
+	db    *sql.DB
+	table string
+
+	// Placeholder gera o marcador do n-ésimo parâmetro (iniciando em 1).
+	// O padrão é "?"; para PostgreSQL utilize, por exemplo, func(n int) string { return fmt.Sprintf("$%d", n) }.
+	Placeholder func(n int) string
+}
+
+// NewSQLJobStore cria um JobStore que grava na tabela informada.
+func NewSQLJobStore(db *sql.DB, table string) *SQLJobStore {
+	if db == nil {
+		panic("sql.DB não pode ser nulo")
+	}
+	return &SQLJobStore{
+		db:          db,
+		table:       table,
+		Placeholder: func(int) string { return "?" },
+	}
+}
+
+// CreateTable cria a tabela de jobs caso ela ainda não exista.
+func (s *SQLJobStore) CreateTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id VARCHAR(64) PRIMARY KEY,
+		analise_id BIGINT,
+		estado VARCHAR(32) NOT NULL,
+		criado_em VARCHAR(64) NOT NULL,
+		job TEXT NOT NULL
+	)`, s.table)
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
+
+// Save atualiza o job ou o insere, caso ainda não exista.
+func (s *SQLJobStore) Save(ctx context.Context, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET analise_id = %s, estado = %s, job = %s WHERE id = %s",
+		s.table, s.Placeholder(1), s.Placeholder(2), s.Placeholder(3), s.Placeholder(4))
+	result, err := s.db.ExecContext(ctx, update, job.AnaliseID, string(job.Estado), string(data), job.ID)
+	if err != nil {
+		return err
+	}
+	if linhas, err := result.RowsAffected(); err != nil || linhas > 0 {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (id, analise_id, estado, criado_em, job) VALUES (%s, %s, %s, %s, %s)",
+		s.table, s.Placeholder(1), s.Placeholder(2), s.Placeholder(3), s.Placeholder(4), s.Placeholder(5))
+	_, err = s.db.ExecContext(ctx, insert, job.ID, job.AnaliseID, string(job.Estado), job.CriadoEm.UTC().Format(time.RFC3339Nano), string(data))
+	return err
+}
+
+// Get retorna o job pelo ID.
+func (s *SQLJobStore) Get(ctx context.Context, id string) (*Job, error) {
+	query := fmt.Sprintf("SELECT job FROM %s WHERE id = %s", s.table, s.Placeholder(1))
+	var data string
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrJobNaoEncontrado, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List retorna os jobs nos estados informados, ordenados pela criação.
+func (s *SQLJobStore) List(ctx context.Context, estados ...EstadoJob) ([]Job, error) {
+	query := fmt.Sprintf("SELECT job FROM %s ORDER BY criado_em", s.table)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return filtraJobs(jobs, estados), nil
+}
+
+// JobManagerOptions configura o JobManager.
+type JobManagerOptions struct {
+	Espera WaitOptions // Configuração do acompanhamento de cada análise
+}
+
+// JobManager envia análises, persiste cada submissão em um JobStore e acompanha as análises
+// até a conclusão, registrando o resultado consolidado. Após um reinício, Resume retoma o
+// acompanhamento dos jobs que não chegaram a um estado final.
+type JobManager struct {
+	vc    *VaduClient
+	store JobStore
+	auth  AuthenticationInterface
+	opts  JobManagerOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	ativos map[string]bool
+	wg     sync.WaitGroup
+}
+
+// NewJobManager cria um JobManager que usa o cliente e a autenticação informados.
+func NewJobManager(vc *VaduClient, store JobStore, auth AuthenticationInterface, opts JobManagerOptions) *JobManager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobManager{
+		vc:     vc,
+		store:  store,
+		auth:   auth,
+		opts:   opts,
+		ctx:    ctx,
+		cancel: cancel,
+		ativos: make(map[string]bool),
+	}
+}
+
+// novoIDJob gera um identificador aleatório para o job.
+func novoIDJob() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// documentosRequisicao retorna os documentos da requisição normalizados, sem validação.
+func documentosRequisicao(req AnalysisRequest) []Documento {
+	var documentos []Documento
+	if len(req.ListaDados) > 0 {
+		for _, dados := range req.ListaDados {
+			documentos = append(documentos, Documento(NormalizaDocumento(string(dados.CNPJCPF))))
+		}
+		return documentos
+	}
+	for _, documento := range req.ListaCNPJCPF {
+		documentos = append(documentos, Documento(NormalizaDocumento(documento)))
+	}
+	return documentos
+}
+
+// Submit persiste o job em EstadoPendente, envia a análise e atualiza o job com o ID da
+// análise. Se o job não puder ser persistido antes do envio, nada é enviado e o erro é
+// retornado. Submissões recusadas são persistidas em EstadoFalhou e o erro do envio é
+// retornado junto com o job. Se a atualização após um envio aceito falhar, o job é
+// acompanhado mesmo assim e a gravação é repetida a cada consulta de status; o erro é
+// apenas registrado no log, para que a análise não seja reenviada. O acompanhamento
+// continua em segundo plano até a conclusão ou o Close.
+func (m *JobManager) Submit(ctx context.Context, req AnalysisRequest) (*Job, error) {
+	id, err := novoIDJob()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar ID do job: %w", err)
+	}
+
+	agora := time.Now()
+	job := Job{
+		ID:             id,
+		CNPJEmpresa:    Documento(NormalizaDocumento(req.CNPJEmpresa)),
+		IDGrupoAnalise: req.IDGrupoAnalise,
+		Documentos:     documentosRequisicao(req),
+		Estado:         EstadoPendente,
+		CriadoEm:       agora,
+		AtualizadoEm:   agora,
+	}
+	if err := m.store.Save(ctx, job); err != nil {
+		m.vc.logger.WithField("job", job.ID).WithError(err).Error("Erro ao persistir job antes do envio")
+		return nil, fmt.Errorf("job não persistido, análise não enviada: %w", err)
+	}
+
+	result := novoAnalysisResult()
+	errEnvio := m.vc.enviaAnalise(ctx, req, result, m.auth)
+
+	job.AtualizadoEm = time.Now()
+	if errEnvio != nil {
+		job.Estado, job.Erro = EstadoFalhou, errEnvio.Error()
+	} else {
+		job.Estado, job.AnaliseID, job.Documentos = EstadoEnviado, result.AnaliseID, result.Ordem
+	}
+
+	// A gravação não usa ctx para registrar o resultado do envio mesmo com o contexto cancelado
+	persistido := true
+	if err := m.store.Save(context.Background(), job); err != nil {
+		persistido = false
+		m.vc.logger.WithFields(logrus.Fields{
+			"job":       job.ID,
+			"analiseID": job.AnaliseID,
+			"estado":    job.Estado,
+		}).WithError(err).Error("Erro ao persistir job após o envio")
+	}
+	if errEnvio != nil {
+		return &job, errEnvio
+	}
+
+	m.vc.logger.WithFields(logrus.Fields{
+		"job":       job.ID,
+		"analiseID": job.AnaliseID,
+	}).Info("Job de análise criado")
+	m.acompanha(job, persistido)
+	return &job, nil
+}
+
+// Resume retoma o acompanhamento dos jobs persistidos que não estão em um estado final,
+// como após um reinício do serviço. Retorna a quantidade de jobs retomados. Jobs em
+// EstadoPendente não têm ID de análise e não são retomados; apenas são registrados no log.
+func (m *JobManager) Resume(ctx context.Context) (int, error) {
+	jobs, err := m.store.List(ctx, EstadoEnviado, EstadoProcessando)
+	if err != nil {
+		return 0, err
+	}
+	retomados := 0
+	for _, job := range jobs {
+		if m.acompanha(job, true) {
+			retomados++
+		}
+	}
+	m.vc.logger.WithField("jobs", retomados).Info("Acompanhamento de jobs retomado")
+
+	pendentes, err := m.store.List(ctx, EstadoPendente)
+	if err != nil {
+		return retomados, err
+	}
+	for _, job := range pendentes {
+		m.vc.logger.WithFields(logrus.Fields{
+			"job":        job.ID,
+			"criadoEm":   job.CriadoEm,
+			"documentos": len(job.Documentos),
+		}).Warn("Job com envio interrompido: verifique se a análise foi criada antes de reenviar")
+	}
+	return retomados, nil
+}
+
+// Job retorna o estado atual do job.
+func (m *JobManager) Job(ctx context.Context, id string) (*Job, error) {
+	return m.store.Get(ctx, id)
+}
+
+// Jobs retorna os jobs nos estados informados (todos, se nenhum for informado).
+func (m *JobManager) Jobs(ctx context.Context, estados ...EstadoJob) ([]Job, error) {
+	return m.store.List(ctx, estados...)
+}
+
+// Close interrompe os acompanhamentos em andamento e aguarda o seu término. Os jobs
+// interrompidos mantêm o último estado persistido e podem ser retomados com Resume.
+func (m *JobManager) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// acompanha inicia o acompanhamento do job, se ainda não estiver em andamento. persistido
+// informa se a versão atual do job está gravada no JobStore.
+func (m *JobManager) acompanha(job Job, persistido bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ativos[job.ID] || m.ctx.Err() != nil {
+		return false
+	}
+	m.ativos[job.ID] = true
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.executa(job, persistido)
+		m.mu.Lock()
+		delete(m.ativos, job.ID)
+		m.mu.Unlock()
+	}()
+	return true
+}
+
+// executa aguarda a conclusão da análise do job e registra o resultado. Enquanto a versão
+// atual do job não estiver persistida, a gravação é repetida a cada consulta de status.
+// Falhas transitórias na espera ou na coleta do resultado mantêm o job em EstadoProcessando,
+// com o erro em Job.Erro, e o acompanhamento é repetido com intervalo crescente até
+// opts.Espera.IntervaloMaximo. O job só passa a EstadoFalhou quando a API responde com um
+// erro definitivo.
+func (m *JobManager) executa(job Job, persistido bool) {
+	// A gravação não usa m.ctx para que o estado final seja persistido mesmo durante o Close
+	salva := func() {
+		job.AtualizadoEm = time.Now()
+		err := m.store.Save(context.Background(), job)
+		persistido = err == nil
+		if err != nil {
+			m.vc.logger.WithFields(logrus.Fields{
+				"job":       job.ID,
+				"analiseID": job.AnaliseID,
+				"estado":    job.Estado,
+			}).WithError(err).Error("Erro ao persistir job")
+		}
+	}
+
+	espera := m.opts.Espera
+	progresso := espera.Progresso
+	espera.Progresso = func(p ProgressoAnalise) {
+		if !p.Status.Concluido && (!persistido || job.Estado != EstadoProcessando ||
+			p.PercentualConcluido != job.PercentualConcluido || job.Erro != "") {
+			job.Estado, job.PercentualConcluido, job.Erro = EstadoProcessando, p.PercentualConcluido, ""
+			salva()
+		}
+		if progresso != nil {
+			progresso(p)
+		}
+	}
+
+	intervalo := espera.normaliza().IntervaloInicial
+	for {
+		result, status, err := m.aguardaResultado(job, espera)
+		if m.ctx.Err() != nil {
+			// Encerramento do JobManager: o job será retomado por Resume
+			return
+		}
+
+		var respostaErr *RespostaAPIError
+		switch {
+		case err == nil:
+			job.Estado, job.PercentualConcluido, job.Resultado, job.Erro = EstadoConcluido, status.PercentualConcluido, result, ""
+			m.vc.logger.WithFields(logrus.Fields{
+				"job":       job.ID,
+				"analiseID": job.AnaliseID,
+			}).Info("Job de análise concluído")
+			salva()
+			return
+		case errors.As(err, &respostaErr) && respostaErr.Definitivo():
+			job.Estado, job.Erro = EstadoFalhou, err.Error()
+			m.vc.logger.WithFields(logrus.Fields{
+				"job":       job.ID,
+				"analiseID": job.AnaliseID,
+			}).WithError(err).Error("Job de análise falhou")
+			salva()
+			return
+		}
+
+		job.Estado, job.Erro = EstadoProcessando, err.Error()
+		m.vc.logger.WithFields(logrus.Fields{
+			"job":       job.ID,
+			"analiseID": job.AnaliseID,
+			"intervalo": intervalo,
+		}).WithError(err).Warn("Falha transitória no acompanhamento do job; nova tentativa agendada")
+		salva()
+
+		timer := time.NewTimer(intervalo)
+		select {
+		case <-m.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		intervalo = espera.normaliza().proximo(intervalo, false)
+	}
+}
+
+// aguardaResultado aguarda a conclusão da análise do job e coleta o resultado.
+func (m *JobManager) aguardaResultado(job Job, espera WaitOptions) (*AnalysisResult, *StatusAnalise, error) {
+	result := novoAnalysisResult()
+	result.AnaliseID = job.AnaliseID
+	for _, documento := range job.Documentos {
+		result.resultado(documento)
+	}
+
+	status, err := m.vc.WaitForAnalise(m.ctx, job.AnaliseID, espera, m.auth)
+	if err != nil {
+		return nil, nil, &AnalysisError{Etapa: EtapaEspera, AnaliseID: job.AnaliseID, Err: err}
+	}
+	result.Status = status
+	if etapa, err := m.vc.coletaResultado(m.ctx, result, m.auth); err != nil {
+		return nil, nil, &AnalysisError{Etapa: etapa, AnaliseID: job.AnaliseID, Err: err}
+	}
+	return result, status, nil
+}
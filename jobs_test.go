@@ -0,0 +1,274 @@
+package vadu_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobsTestSuite struct {
+	suite.Suite
+	assert         *assert.Assertions
+	ctx            context.Context
+	logger         *logrus.Logger
+	authentication *mock.MockAuthentication
+}
+
+func TestJobsTestSuite(t *testing.T) {
+	suite.Run(t, new(JobsTestSuite))
+}
+
+func (s *JobsTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+	s.authentication = new(mock.MockAuthentication)
+	s.authentication.On("Token", testifymock.Anything).Return("mocked_token", nil)
+}
+
+// clientJobs cria um cliente cujo status da análise é concluído apenas quando concluida é verdadeiro.
+func (s *JobsTestSuite) clientJobs(concluida *atomic.Bool) *vadu.VaduClient {
+	return s.clientJobsComEnvio(concluida, nil)
+}
+
+// clientJobsComEnvio cria um cliente como clientJobs que chama aoEnviar a cada envio de análise.
+func (s *JobsTestSuite) clientJobsComEnvio(concluida *atomic.Bool, aoEnviar func()) *vadu.VaduClient {
+	httpClient := servidorAnalise(rotasAnalise)
+	transporte := httpClient.Transport.(*mock.MockAuthHTTPClient)
+	roteia := transporte.DoFunc
+	transporte.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost && aoEnviar != nil {
+			aoEnviar()
+		}
+		if strings.Contains(req.URL.Path, "/status/") && !concluida.Load() {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(statusMetade))}, nil
+		}
+		return roteia(req)
+	}
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	return vadu.NewVaduClient(httpClient, *session, s.logger)
+}
+
+// aguardaEstado consulta o job até que ele atinja o estado esperado.
+func (s *JobsTestSuite) aguardaEstado(manager *vadu.JobManager, id string, estado vadu.EstadoJob) *vadu.Job {
+	var job *vadu.Job
+	s.Eventually(func() bool {
+		var err error
+		job, err = manager.Job(s.ctx, id)
+		return err == nil && job.Estado == estado
+	}, 2*time.Second, time.Millisecond)
+	return job
+}
+
+// TestJobManagerRetomada verifica a persistência em arquivo e a retomada após um reinício
+func (s *JobsTestSuite) TestJobManagerRetomada() {
+	store := vadu.NewFileJobStore(filepath.Join(s.T().TempDir(), "jobs.jsonl"))
+	opts := vadu.JobManagerOptions{Espera: vadu.WaitOptions{IntervaloInicial: time.Millisecond, IntervaloMaximo: time.Millisecond}}
+
+	var concluida atomic.Bool
+	manager := vadu.NewJobManager(s.clientJobs(&concluida), store, s.authentication, opts)
+	job, err := manager.Submit(s.ctx, vadu.AnalysisRequest{
+		CNPJEmpresa:    "33011770000199",
+		IDGrupoAnalise: 10802,
+		ListaCNPJCPF:   []string{"98960887000164", "52998224725"},
+	})
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.EstadoEnviado, job.Estado)
+	s.assert.Equal(4768906, job.AnaliseID)
+
+	processando := s.aguardaEstado(manager, job.ID, vadu.EstadoProcessando)
+	s.assert.Equal(50, processando.PercentualConcluido)
+	manager.Close()
+
+	// Um novo JobManager com o mesmo arquivo retoma o acompanhamento
+	concluida.Store(true)
+	manager = vadu.NewJobManager(s.clientJobs(&concluida), store, s.authentication, opts)
+	defer manager.Close()
+	retomados, err := manager.Resume(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Equal(1, retomados)
+
+	concluido := s.aguardaEstado(manager, job.ID, vadu.EstadoConcluido)
+	s.assert.Equal(100, concluido.PercentualConcluido)
+	s.assert.Equal("B (500)", concluido.Resultado.Resumo.RatingSigla)
+	empresa, ok := concluido.Resultado.Documento("98960887000164")
+	s.assert.True(ok)
+	s.assert.Len(empresa.Logs, 2)
+
+	// Jobs em estado final não são retomados
+	retomados, err = manager.Resume(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Zero(retomados)
+}
+
+// TestJobManagerFalhas verifica a persistência de envios recusados e a consulta por estado
+func (s *JobsTestSuite) TestJobManagerFalhas() {
+	var concluida atomic.Bool
+	concluida.Store(true)
+	manager := vadu.NewJobManager(s.clientJobs(&concluida), vadu.NewMemoryJobStore(), s.authentication, vadu.JobManagerOptions{})
+	defer manager.Close()
+
+	job, err := manager.Submit(s.ctx, vadu.AnalysisRequest{CNPJEmpresa: "", IDGrupoAnalise: 10802, ListaCNPJCPF: []string{"98960887000164"}, BatchPolicy: policy(vadu.BatchPolicyFail)})
+	s.assert.ErrorIs(err, vadu.ErrBatchInvalido)
+	s.assert.Equal(vadu.EstadoFalhou, job.Estado)
+	s.assert.NotEmpty(job.Erro)
+
+	falhos, err := manager.Jobs(s.ctx, vadu.EstadoFalhou)
+	s.assert.NoError(err)
+	s.assert.Len(falhos, 1)
+	s.assert.Equal(job.ID, falhos[0].ID)
+
+	_, err = manager.Job(s.ctx, "inexistente")
+	s.assert.ErrorIs(err, vadu.ErrJobNaoEncontrado)
+}
+
+// storeInstavel é um JobStore em memória cujas próximas gravações falham enquanto falhas > 0.
+type storeInstavel struct {
+	*vadu.MemoryJobStore
+	falhas atomic.Int32
+}
+
+func (s *storeInstavel) Save(ctx context.Context, job vadu.Job) error {
+	if s.falhas.Add(-1) >= 0 {
+		return errors.New("store indisponível")
+	}
+	return s.MemoryJobStore.Save(ctx, job)
+}
+
+// TestJobManagerPersistencia verifica a gravação antes do envio e a repetição de gravações com falha
+func (s *JobsTestSuite) TestJobManagerPersistencia() {
+	store := &storeInstavel{MemoryJobStore: vadu.NewMemoryJobStore()}
+	var concluida atomic.Bool
+	var envios int
+	var pendentesNoEnvio []vadu.Job
+	vaduClient := s.clientJobsComEnvio(&concluida, func() {
+		envios++
+		pendentesNoEnvio, _ = store.List(s.ctx, vadu.EstadoPendente)
+		// A gravação após o envio falha
+		store.falhas.Store(1)
+	})
+	opts := vadu.JobManagerOptions{Espera: vadu.WaitOptions{IntervaloInicial: time.Millisecond, IntervaloMaximo: time.Millisecond}}
+	req := vadu.AnalysisRequest{CNPJEmpresa: "33011770000199", IDGrupoAnalise: 10802, ListaCNPJCPF: []string{"98.960.887/0001-64"}}
+	manager := vadu.NewJobManager(vaduClient, store, s.authentication, opts)
+	defer manager.Close()
+
+	// Sem a gravação do job pendente, nada é enviado
+	store.falhas.Store(1)
+	job, err := manager.Submit(s.ctx, req)
+	s.assert.Error(err)
+	s.assert.Nil(job)
+	s.assert.Zero(envios)
+
+	// O job é gravado como pendente antes do envio; a falha na gravação seguinte não
+	// interrompe o acompanhamento e a gravação é repetida na consulta de status
+	job, err = manager.Submit(s.ctx, req)
+	s.assert.NoError(err)
+	s.assert.Equal(1, envios)
+	s.assert.Len(pendentesNoEnvio, 1)
+	s.assert.Equal([]vadu.Documento{"98960887000164"}, pendentesNoEnvio[0].Documentos)
+	s.assert.Equal(vadu.EstadoEnviado, job.Estado)
+
+	processando := s.aguardaEstado(manager, job.ID, vadu.EstadoProcessando)
+	s.assert.Equal(4768906, processando.AnaliseID)
+	concluida.Store(true)
+	s.aguardaEstado(manager, job.ID, vadu.EstadoConcluido)
+
+	// Um job pendente após um reinício não é retomado
+	s.assert.NoError(store.MemoryJobStore.Save(s.ctx, vadu.Job{ID: "interrompido", Estado: vadu.EstadoPendente, CriadoEm: time.Now()}))
+	reiniciado := vadu.NewJobManager(vaduClient, store, s.authentication, opts)
+	defer reiniciado.Close()
+	retomados, err := reiniciado.Resume(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Zero(retomados)
+	pendentes, err := reiniciado.Jobs(s.ctx, vadu.EstadoPendente)
+	s.assert.NoError(err)
+	s.assert.Len(pendentes, 1)
+}
+
+// TestJobManagerErrosDeStatus verifica que erros transitórios mantêm o job em acompanhamento
+// e que apenas erros definitivos da API o encerram como falho
+func (s *JobsTestSuite) TestJobManagerErrosDeStatus() {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	var consultas atomic.Int32
+	httpClient := servidorAnalise(rotasAnalise)
+	transporte := httpClient.Transport.(*mock.MockAuthHTTPClient)
+	roteia := transporte.DoFunc
+	transporte.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if codigo := int(status.Load()); strings.Contains(req.URL.Path, "/status/") && codigo != http.StatusOK {
+			consultas.Add(1)
+			return &http.Response{StatusCode: codigo, Body: ioutil.NopCloser(strings.NewReader(`{"message":"erro"}`))}, nil
+		}
+		return roteia(req)
+	}
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	opts := vadu.JobManagerOptions{Espera: vadu.WaitOptions{IntervaloInicial: time.Millisecond, IntervaloMaximo: time.Millisecond, MaximoErrosConsecutivos: 1}}
+	manager := vadu.NewJobManager(vadu.NewVaduClient(httpClient, *session, s.logger), vadu.NewMemoryJobStore(), s.authentication, opts)
+	defer manager.Close()
+	req := vadu.AnalysisRequest{CNPJEmpresa: "33011770000199", IDGrupoAnalise: 10802, ListaCNPJCPF: []string{"98960887000164"}}
+
+	// Após esgotar as falhas toleradas por WaitForAnalise, o job continua em processamento
+	job, err := manager.Submit(s.ctx, req)
+	s.assert.NoError(err)
+	processando := s.aguardaEstado(manager, job.ID, vadu.EstadoProcessando)
+	s.assert.Contains(processando.Erro, "status 503")
+	s.Eventually(func() bool { return consultas.Load() > 4 }, 2*time.Second, time.Millisecond)
+	atual, err := manager.Job(s.ctx, job.ID)
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.EstadoProcessando, atual.Estado)
+
+	// Quando a API se recupera, o job é concluído e o erro é descartado
+	status.Store(http.StatusOK)
+	concluido := s.aguardaEstado(manager, job.ID, vadu.EstadoConcluido)
+	s.assert.Empty(concluido.Erro)
+
+	// Um erro definitivo encerra o job
+	status.Store(http.StatusNotFound)
+	job, err = manager.Submit(s.ctx, req)
+	s.assert.NoError(err)
+	falhou := s.aguardaEstado(manager, job.ID, vadu.EstadoFalhou)
+	s.assert.Contains(falhou.Erro, "status 404")
+}
+
+// TestFileJobStoreCompacta verifica que a compactação mantém apenas a última versão de cada job
+func (s *JobsTestSuite) TestFileJobStoreCompacta() {
+	path := filepath.Join(s.T().TempDir(), "jobs.jsonl")
+	store := vadu.NewFileJobStore(path)
+	s.assert.NoError(store.Compacta(s.ctx))
+
+	agora := time.Now()
+	for i := 0; i <= 50; i += 10 {
+		s.assert.NoError(store.Save(s.ctx, vadu.Job{ID: "a", Estado: vadu.EstadoProcessando, PercentualConcluido: i, CriadoEm: agora}))
+	}
+	s.assert.NoError(store.Save(s.ctx, vadu.Job{ID: "b", Estado: vadu.EstadoConcluido, CriadoEm: agora.Add(time.Second)}))
+	antes, err := store.List(s.ctx)
+	s.assert.NoError(err)
+
+	s.assert.NoError(store.Compacta(s.ctx))
+	data, err := os.ReadFile(path)
+	s.assert.NoError(err)
+	s.assert.Equal(2, strings.Count(string(data), "\n"))
+
+	depois, err := store.List(s.ctx)
+	s.assert.NoError(err)
+	s.assert.Equal(len(antes), len(depois))
+	s.assert.Equal(50, depois[0].PercentualConcluido)
+	s.assert.Equal("b", depois[1].ID)
+}
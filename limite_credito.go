@@ -0,0 +1,117 @@
+package vadu
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BaseLimite identifica a métrica sobre a qual o percentual de um produto é aplicado.
+type BaseLimite string
+
+const (
+	// BaseFaturamentoMensal aplica o percentual sobre DadosIntegracao.FaturamentoMedioMensal.
+	BaseFaturamentoMensal BaseLimite = "faturamento mensal"
+	// BaseFaturamentoAnual aplica o percentual sobre 12 vezes o FaturamentoMedioMensal.
+	BaseFaturamentoAnual BaseLimite = "faturamento anual"
+)
+
+var (
+	// ErrFormatoLimiteDesconhecido indica um trecho da descrição do rating que não segue o formato esperado.
+	ErrFormatoLimiteDesconhecido = errors.New("formato de limite de produto desconhecido")
+	// ErrBaseLimiteDesconhecida indica uma métrica base que o calculador não sabe obter de DadosIntegracao.
+	ErrBaseLimiteDesconhecida = errors.New("base de limite desconhecida")
+)
+
+// LimiteProduto representa a política de limite de um produto descrita no rating,
+// como "CCB--> 40% do faturamento mensal".
+type LimiteProduto struct {
+	Produto    string     // Código do produto (ex.: "CCB", "ANT")
+	Percentual Decimal    // Percentual aplicado sobre a base (ex.: 40 para 40%)
+	Base       BaseLimite // Métrica base normalizada
+	Texto      string     // Trecho original da descrição
+}
+
+// LimiteCreditoSugerido é o limite calculado para um produto a partir dos dados da empresa.
+type LimiteCreditoSugerido struct {
+	Produto    string
+	Percentual Decimal
+	Base       BaseLimite
+	ValorBase  Decimal // Valor da métrica base obtido de DadosIntegracao
+	Limite     Decimal // ValorBase multiplicado pelo percentual, arredondado para centavos
+}
+
+// separadorLimites separa os produtos na descrição do rating.
+const separadorLimites = "||"
+
+// trechoLimite reconhece trechos no formato "CCB--> 40% do faturamento mensal",
+// aceitando vírgula ou ponto decimal e variações de espaçamento e da seta.
+var trechoLimite = regexp.MustCompile(`^\s*([\p{L}\d_ ]+?)\s*-+>\s*(\d+(?:[.,]\d+)?)\s*%\s*(?:(?:d[oa]s?|sobre\s+o|sobre\s+a)\s+)?(.+?)\s*$`)
+
+// ParseLimitesProduto interpreta a descrição de um rating (RatingDescricao) e
+// retorna os limites de cada produto. Descrições vazias resultam em uma lista vazia.
+func ParseLimitesProduto(descricao string) ([]LimiteProduto, error) {
+	var limites []LimiteProduto
+	if strings.TrimSpace(descricao) == "" {
+		return limites, nil
+	}
+
+	for _, trecho := range strings.Split(descricao, separadorLimites) {
+		if strings.TrimSpace(trecho) == "" {
+			continue
+		}
+		partes := trechoLimite.FindStringSubmatch(trecho)
+		if partes == nil {
+			return nil, fmt.Errorf("%w: %q", ErrFormatoLimiteDesconhecido, strings.TrimSpace(trecho))
+		}
+		percentual, err := ParseDecimal(strings.Replace(partes[2], ",", ".", 1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: percentual inválido em %q", ErrFormatoLimiteDesconhecido, strings.TrimSpace(trecho))
+		}
+		limites = append(limites, LimiteProduto{
+			Produto:    strings.ToUpper(strings.TrimSpace(partes[1])),
+			Percentual: percentual,
+			Base:       BaseLimite(strings.ToLower(strings.Join(strings.Fields(partes[3]), " "))),
+			Texto:      strings.TrimSpace(trecho),
+		})
+	}
+	return limites, nil
+}
+
+// LimitesProduto interpreta a descrição do rating como limites de produtos.
+func (r Rating) LimitesProduto() ([]LimiteProduto, error) {
+	return ParseLimitesProduto(r.Descricao)
+}
+
+// valorBase obtém de DadosIntegracao o valor da métrica base do limite.
+func (b BaseLimite) valorBase(dados DadosIntegracao) (Decimal, error) {
+	switch b {
+	case BaseFaturamentoMensal:
+		return dados.FaturamentoMedioMensal, nil
+	case BaseFaturamentoAnual:
+		return dados.FaturamentoMedioMensal.Mul(DecimalFromInt(12)), nil
+	default:
+		return Decimal{}, fmt.Errorf("%w: %q", ErrBaseLimiteDesconhecida, string(b))
+	}
+}
+
+// CalculaLimitesCredito aplica os limites de cada produto ao faturamento da empresa
+// informado em DadosIntegracao, retornando o limite sugerido por produto.
+func CalculaLimitesCredito(limites []LimiteProduto, dados DadosIntegracao) ([]LimiteCreditoSugerido, error) {
+	sugeridos := make([]LimiteCreditoSugerido, 0, len(limites))
+	for _, limite := range limites {
+		valorBase, err := limite.Base.valorBase(dados)
+		if err != nil {
+			return nil, fmt.Errorf("produto %s: %w", limite.Produto, err)
+		}
+		sugeridos = append(sugeridos, LimiteCreditoSugerido{
+			Produto:    limite.Produto,
+			Percentual: limite.Percentual,
+			Base:       limite.Base,
+			ValorBase:  valorBase,
+			Limite:     valorBase.Mul(limite.Percentual).Mul(NewDecimal(1, 2)).Round(2),
+		})
+	}
+	return sugeridos, nil
+}
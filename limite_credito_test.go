@@ -0,0 +1,72 @@
+package vadu_test
+
+import (
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimiteCreditoTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestLimiteCreditoTestSuite(t *testing.T) {
+	suite.Run(t, new(LimiteCreditoTestSuite))
+}
+
+func (s *LimiteCreditoTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestParseLimitesProduto verifica a interpretação da descrição do rating
+func (s *LimiteCreditoTestSuite) TestParseLimitesProduto() {
+	limites, err := vadu.ParseLimitesProduto("CCB--> 40% do faturamento mensal || ANT--> 20% do faturamento mensal")
+	s.assert.NoError(err)
+	s.assert.Len(limites, 2)
+	s.assert.Equal("CCB", limites[0].Produto)
+	s.assert.Equal(vadu.DecimalFromInt(40), limites[0].Percentual)
+	s.assert.Equal(vadu.BaseFaturamentoMensal, limites[0].Base)
+	s.assert.Equal("ANT", limites[1].Produto)
+	s.assert.Equal(vadu.DecimalFromInt(20), limites[1].Percentual)
+
+	limites, err = vadu.ParseLimitesProduto("cessao -> 12,5% do Faturamento  Mensal")
+	s.assert.NoError(err)
+	s.assert.Equal("CESSAO", limites[0].Produto)
+	s.assert.Equal(vadu.NewDecimal(125, 1), limites[0].Percentual)
+	s.assert.Equal(vadu.BaseFaturamentoMensal, limites[0].Base)
+
+	limites, err = vadu.ParseLimitesProduto("")
+	s.assert.NoError(err)
+	s.assert.Empty(limites)
+
+	_, err = vadu.ParseLimitesProduto("CCB--> 40% do faturamento mensal || Rating fora de faixa")
+	s.assert.ErrorIs(err, vadu.ErrFormatoLimiteDesconhecido)
+}
+
+// TestCalculaLimitesCredito verifica o cálculo dos limites sugeridos
+func (s *LimiteCreditoTestSuite) TestCalculaLimitesCredito() {
+	rating := vadu.NewRating(500, "B (500)", "CCB--> 40% do faturamento mensal || ANT--> 20% do faturamento mensal")
+	limites, err := rating.LimitesProduto()
+	s.assert.NoError(err)
+
+	dados := vadu.DadosIntegracao{FaturamentoMedioMensal: vadu.MustDecimal("384170.7917")}
+	sugeridos, err := vadu.CalculaLimitesCredito(limites, dados)
+	s.assert.NoError(err)
+	s.assert.Len(sugeridos, 2)
+	s.assert.Equal(vadu.MustDecimal("153668.32"), sugeridos[0].Limite)
+	s.assert.Equal(vadu.MustDecimal("76834.16"), sugeridos[1].Limite)
+
+	// Percentuais sem representação exata em float64 não introduzem erro de arredondamento
+	limites, err = vadu.ParseLimitesProduto("CCB--> 33,335% do faturamento mensal")
+	s.assert.NoError(err)
+	s.assert.Equal("33.335", limites[0].Percentual.String())
+	sugeridos, err = vadu.CalculaLimitesCredito(limites, vadu.DadosIntegracao{FaturamentoMedioMensal: vadu.DecimalFromInt(100)})
+	s.assert.NoError(err)
+	s.assert.Equal("33.34", sugeridos[0].Limite.String())
+
+	_, err = vadu.CalculaLimitesCredito([]vadu.LimiteProduto{{Produto: "CCB", Percentual: vadu.DecimalFromInt(10), Base: "patrimônio líquido"}}, dados)
+	s.assert.ErrorIs(err, vadu.ErrBaseLimiteDesconhecida)
+}
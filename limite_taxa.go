@@ -0,0 +1,87 @@
+package vadu
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter limita a taxa de requisições do cliente à API. Wait bloqueia até que uma
+// requisição possa ser feita ou até o fim do ctx.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket é um RateLimiter que permite Taxa requisições por segundo, com rajadas de até
+// Rajada requisições.
+type TokenBucket struct {
+	mu     sync.Mutex
+	taxa   float64
+	rajada float64
+	tokens float64
+	ultimo time.Time
+}
+
+// NewRateLimiter cria um TokenBucket com a taxa, em requisições por segundo, e a rajada
+// informadas. Rajadas menores que 1 são tratadas como 1.
+func NewRateLimiter(porSegundo float64, rajada int) *TokenBucket {
+	if porSegundo <= 0 {
+		panic("a taxa de requisições deve ser positiva")
+	}
+	if rajada < 1 {
+		rajada = 1
+	}
+	return &TokenBucket{
+		taxa:   porSegundo,
+		rajada: float64(rajada),
+		tokens: float64(rajada),
+		ultimo: time.Now(),
+	}
+}
+
+// Wait reserva uma requisição e aguarda até que ela esteja disponível. Se o ctx terminar
+// antes, a reserva é devolvida e o erro do contexto é retornado.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	agora := time.Now()
+	b.tokens += agora.Sub(b.ultimo).Seconds() * b.taxa
+	if b.tokens > b.rajada {
+		b.tokens = b.rajada
+	}
+	b.ultimo = agora
+	b.tokens--
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+	espera := time.NewTimer(time.Duration(deficit / b.taxa * float64(time.Second)))
+	defer espera.Stop()
+	select {
+	case <-espera.C:
+		return nil
+	case <-ctx.Done():
+		// Devolve o token não utilizado, sem exceder a rajada
+		b.mu.Lock()
+		b.tokens = math.Min(b.tokens+1, b.rajada)
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// do envia a requisição respeitando o RateLimiter da sessão, quando configurado.
+func (vc *VaduClient) do(req *http.Request) (*http.Response, error) {
+	if vc.session.RateLimiter != nil {
+		if err := vc.session.RateLimiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return vc.httpClient.Do(req)
+}
@@ -1,139 +1,123 @@
 package vadu
 
-import (
-	"time"
-)
+import "encoding/json"
 
 // GrupoAnalise representa a estrutura de um grupo de análise.
 type GrupoAnalise struct {
-	IDGrupoAnalise       int    `json:"id_grupo_analise"`
-	NomeGrupoAnalise     string `json:"nome_grupo_analise"`
-	RatingStart          int    `json:"rating_start"`
-	RatingMinimo         int    `json:"rating_minimo"`
-	RatingMaximo         int    `json:"rating_maximo"`
-	QuantidadeAnalises   int    `json:"quantidade_analises"`
-	QuantidadeRegras     int    `json:"quantidade_regras"`
-	QuantidadeValidacoes int    `json:"quantidade_validacoes"`
+	IDGrupoAnalise       int                        `json:"id_grupo_analise"`
+	NomeGrupoAnalise     string                     `json:"nome_grupo_analise"`
+	RatingStart          int                        `json:"rating_start"`
+	RatingMinimo         int                        `json:"rating_minimo"`
+	RatingMaximo         int                        `json:"rating_maximo"`
+	QuantidadeAnalises   int                        `json:"quantidade_analises"`
+	QuantidadeRegras     int                        `json:"quantidade_regras"`
+	QuantidadeValidacoes int                        `json:"quantidade_validacoes"`
+	Extra                map[string]json.RawMessage `json:"-"` // Campos da resposta não mapeados pelo SDK
 }
 
 // PostBack define a estrutura opcional para o campo de postback
 type PostBack struct {
-	URL              string `json:"url"`
-	Token            string `json:"token"`
-	TipoDadosRetorno int    `json:"tipoDadosRetorno"`
+	URL              string           `json:"url"`
+	Token            string           `json:"token"`
+	TipoDadosRetorno TipoDadosRetorno `json:"tipoDadosRetorno"`
 }
 
 // EnviaCNPJsRequest define os dados que são enviados na requisição para a análise de CNPJs
 type EnviaCNPJsRequest struct {
-	CNPJEmpresa    string    `json:"cnpj_empresa"`
-	IDGrupoAnalise int       `json:"id_grupo_analise"`
-	ListaCNPJCPF   []string  `json:"lista_cnpj_cpf"`
-	PostBack       *PostBack `json:"postBack,omitempty"` // PostBack é opcional
+	CNPJEmpresa    Documento   `json:"cnpj_empresa"`
+	IDGrupoAnalise int         `json:"id_grupo_analise"`
+	ListaCNPJCPF   []Documento `json:"lista_cnpj_cpf"`
+	PostBack       *PostBack   `json:"postBack,omitempty"` // PostBack é opcional
 }
 
 // EnviaCNPJsResponse define a estrutura da resposta da API de envio de CNPJs
 type EnviaCNPJsResponse struct {
-	AnaliseID        int       `json:"analise_id"`
-	QuantidadeCNPJ   int       `json:"quantidade_cnpj"`
-	QuantidadeCPF    int       `json:"quantidade_cpf"`
-	Usuario          string    `json:"usuario"`
-	DataHoraEnvio    time.Time `json:"data_hora_envio"`
-	IDGrupoAnalise   int       `json:"id_grupo_analise"`
-	NomeLote         string    `json:"nome_lote"`
-	NomeGrupoAnalise string    `json:"nome_grupo_analise"`
+	AnaliseID        int                        `json:"analise_id"`
+	QuantidadeCNPJ   int                        `json:"quantidade_cnpj"`
+	QuantidadeCPF    int                        `json:"quantidade_cpf"`
+	Usuario          string                     `json:"usuario"`
+	DataHoraEnvio    DataHora                   `json:"data_hora_envio"`
+	IDGrupoAnalise   int                        `json:"id_grupo_analise"`
+	NomeLote         string                     `json:"nome_lote"`
+	NomeGrupoAnalise string                     `json:"nome_grupo_analise"`
+	Extra            map[string]json.RawMessage `json:"-"` // Campos da resposta não mapeados pelo SDK
 }
 
 // DadosIntegracao representa os dados detalhados enviados para análise.
 type DadosIntegracao struct {
-	CNPJCPF                       string  `json:"cnpjcpf"`
-	AtivoTotal                    float64 `json:"ativoTotal"`
-	AtivoCirculante               float64 `json:"ativoCirculante"`
-	AtivoNaoCirculante            float64 `json:"ativoNaoCirculante"`
-	AtivoRealizavelLongoPrazo     float64 `json:"ativoRealizavelLongoPrazo"`
-	DeducaoReceitaBruta           float64 `json:"deducaoReceitaBruta"`
-	DepreciacaoBens               float64 `json:"depreciacaoBens"`
-	Despesas                      float64 `json:"despesas"`
-	DisponivelCaixa               float64 `json:"disponivelCaixa"`
-	Emprestimo                    float64 `json:"emprestimo"`
-	EstoqueBalanco                float64 `json:"estoqueBalanco"`
-	LucroLiquido                  float64 `json:"lucroLiquido"`
-	PassivoCirculante             float64 `json:"passivoCirculante"`
-	PassivoNaoCirculante          float64 `json:"passivoNaoCirculante"`
-	PassivoTotal                  float64 `json:"passivoTotal"`
-	PatrimonioLiquido             float64 `json:"patrimonioLiquido"`
-	ReceitaLiquida                float64 `json:"receitaLiquida"`
-	ReceitaBruta                  float64 `json:"receitaBruta"`
-	VendasLiquidas                float64 `json:"vendasLiquidas"`
-	ScoreExterno                  int     `json:"scoreExterno"`
-	ProbabilidadeInadimplencia    int     `json:"probabilidadeInadimplencia"`
-	DividasBaixasPrejuizo         float64 `json:"dividasBaixasPrejuizo"`
-	QuantidadeInstituicoes        int     `json:"quantidadeInstituicoes"`
-	LimiteCreditoVencimentoAte360 float64 `json:"limiteCreditoVencimentoAte360Dias"`
-	CreditosVencerAte30Dias       float64 `json:"creditosVencerAte30Dias"`
-	Falencia                      int     `json:"falencia"`
-	ChequeSemFundos               int     `json:"chequeSemFundos"`
-	FaturamentoMedioMensal        float64 `json:"faturamentoMedioMensal"`
-	CapitalGiroSCR                float64 `json:"capitalGiroSCR"`
-	CapitalGiroLiquido            float64 `json:"capitalGiroLiquido"`
-	CapitalGiroProprio            float64 `json:"capitalGiroProprio"`
-	NecessidadeCapitalGiro        float64 `json:"necessidadeCapitalGiro"`
-	LiquidezCorrente              float64 `json:"liquidezCorrente"`
-	LiquidezSeca                  float64 `json:"liquidezSeca"`
-	LiquidezGeral                 float64 `json:"liquidezGeral"`
-	LiquidezImediata              float64 `json:"liquidezImediata"`
-	GrauSolvencia                 float64 `json:"grauSolvencia"`
-	Endividamento                 float64 `json:"endividamento"`
-	DependenciaRecursosTerceiros  float64 `json:"dependeciaRecursosTerceiros"`
-	EndividamentoCurtoPrazo       float64 `json:"endividamentoCurtoPrazo"`
-	NivelImobilizacao             float64 `json:"nivelImobilizacao"`
-	GrauDependenciaBancaria       float64 `json:"grauDependenciaBancaria"`
-	RetornoPatrimonioLiquidoROE   float64 `json:"retornoPatrimonioLiquidoROE"`
-	GiroAtivo                     float64 `json:"giroAtivo"`
-	RetornoSobreAtivoRAO          float64 `json:"retornoSobreAtivoRAO"`
-	RetornoSobreVendas            float64 `json:"retornoSobreVendas"`
-	MargemOperacional             float64 `json:"margemOperacional"`
-	RatingExterno                 string  `json:"ratingExterno"`
+	CNPJCPF                       Documento `json:"cnpjcpf"`
+	AtivoTotal                    Decimal   `json:"ativoTotal"`
+	AtivoCirculante               Decimal   `json:"ativoCirculante"`
+	AtivoNaoCirculante            Decimal   `json:"ativoNaoCirculante"`
+	AtivoRealizavelLongoPrazo     Decimal   `json:"ativoRealizavelLongoPrazo"`
+	DeducaoReceitaBruta           Decimal   `json:"deducaoReceitaBruta"`
+	DepreciacaoBens               Decimal   `json:"depreciacaoBens"`
+	Despesas                      Decimal   `json:"despesas"`
+	DisponivelCaixa               Decimal   `json:"disponivelCaixa"`
+	Emprestimo                    Decimal   `json:"emprestimo"`
+	EstoqueBalanco                Decimal   `json:"estoqueBalanco"`
+	LucroLiquido                  Decimal   `json:"lucroLiquido"`
+	PassivoCirculante             Decimal   `json:"passivoCirculante"`
+	PassivoNaoCirculante          Decimal   `json:"passivoNaoCirculante"`
+	PassivoTotal                  Decimal   `json:"passivoTotal"`
+	PatrimonioLiquido             Decimal   `json:"patrimonioLiquido"`
+	ReceitaLiquida                Decimal   `json:"receitaLiquida"`
+	ReceitaBruta                  Decimal   `json:"receitaBruta"`
+	VendasLiquidas                Decimal   `json:"vendasLiquidas"`
+	ScoreExterno                  int       `json:"scoreExterno"`
+	ProbabilidadeInadimplencia    int       `json:"probabilidadeInadimplencia"`
+	DividasBaixasPrejuizo         Decimal   `json:"dividasBaixasPrejuizo"`
+	QuantidadeInstituicoes        int       `json:"quantidadeInstituicoes"`
+	LimiteCreditoVencimentoAte360 Decimal   `json:"limiteCreditoVencimentoAte360Dias"`
+	CreditosVencerAte30Dias       Decimal   `json:"creditosVencerAte30Dias"`
+	Falencia                      int       `json:"falencia"`
+	ChequeSemFundos               int       `json:"chequeSemFundos"`
+	FaturamentoMedioMensal        Decimal   `json:"faturamentoMedioMensal"`
+	CapitalGiroSCR                Decimal   `json:"capitalGiroSCR"`
+	CapitalGiroLiquido            Decimal   `json:"capitalGiroLiquido"`
+	CapitalGiroProprio            Decimal   `json:"capitalGiroProprio"`
+	NecessidadeCapitalGiro        Decimal   `json:"necessidadeCapitalGiro"`
+	LiquidezCorrente              Decimal   `json:"liquidezCorrente"`
+	LiquidezSeca                  Decimal   `json:"liquidezSeca"`
+	LiquidezGeral                 Decimal   `json:"liquidezGeral"`
+	LiquidezImediata              Decimal   `json:"liquidezImediata"`
+	GrauSolvencia                 Decimal   `json:"grauSolvencia"`
+	Endividamento                 Decimal   `json:"endividamento"`
+	DependenciaRecursosTerceiros  Decimal   `json:"dependeciaRecursosTerceiros"`
+	EndividamentoCurtoPrazo       Decimal   `json:"endividamentoCurtoPrazo"`
+	NivelImobilizacao             Decimal   `json:"nivelImobilizacao"`
+	GrauDependenciaBancaria       Decimal   `json:"grauDependenciaBancaria"`
+	RetornoPatrimonioLiquidoROE   Decimal   `json:"retornoPatrimonioLiquidoROE"`
+	GiroAtivo                     Decimal   `json:"giroAtivo"`
+	RetornoSobreAtivoRAO          Decimal   `json:"retornoSobreAtivoRAO"`
+	RetornoSobreVendas            Decimal   `json:"retornoSobreVendas"`
+	MargemOperacional             Decimal   `json:"margemOperacional"`
+	RatingExterno                 string    `json:"ratingExterno"`
 }
 
+// LogAnalise representa o resultado de uma regra aplicada a um documento
 type LogAnalise struct {
-	AnaliseDescricao string `json:"analise_descricao"`
-	RegraDescricao   string `json:"regra_descricao"`
-	RegraCondicao    string `json:"regra_condicao"`
-	Erro             bool   `json:"erro"`
-	Alerta           bool   `json:"alerta"`
-	Liberado         bool   `json:"liberado"`
-	Transferido      bool   `json:"transferido"`
-	ErroConsulta     bool   `json:"erroConsulta"`
-	Bloqueio         bool   `json:"bloqueio"`
+	AnaliseDescricao string                     `json:"analise_descricao"`
+	RegraDescricao   string                     `json:"regra_descricao"`
+	RegraCondicao    string                     `json:"regra_condicao"`
+	Erro             bool                       `json:"erro"`
+	Alerta           bool                       `json:"alerta"`
+	Liberado         bool                       `json:"liberado"`
+	Transferido      bool                       `json:"transferido"`
+	ErroConsulta     NullBool                   `json:"erroConsulta"`
+	Bloqueio         bool                       `json:"bloqueio"`
+	Extra            map[string]json.RawMessage `json:"-"` // Campos da resposta não mapeados pelo SDK
 }
 
+// ResumoCNPJDatalhado representa o resumo de um documento analisado acompanhado dos logs das regras
 type ResumoCNPJDatalhado struct {
-	AnaliseID                 int          `json:"analise_id"`
-	AnaliseCNPJCPFID          int          `json:"analise_cnpj_cpf_id"`
-	CNPJCPF                   string       `json:"cnpj_cpf"`
-	Nome                      string       `json:"nome"`
-	Erro                      bool         `json:"erro"`
-	Alerta                    bool         `json:"alerta"`
-	Bloqueio                  bool         `json:"bloqueio"`
-	Rating                    int          `json:"rating"`
-	RatingSigla               string       `json:"rating_sigla"`
-	RatingDescricao           string       `json:"rating_descricao"`
-	Rating2                   int          `json:"rating2"`
-	Rating2Sigla              string       `json:"rating2_sigla"`
-	Rating2Descricao          string       `json:"rating2_descricao"`
-	NovaConsultaSerasa        bool         `json:"nova_consulta_serasa"`
-	NovaConsultaSerasaString  *string      `json:"nova_consulta_serasa_string_retorno"`
-	FlowSolicitacaoID         int          `json:"flowSolicitacao_id"`
-	FlowTarefaNome            string       `json:"flowTarefaNome"`
-	FlowTarefaID              int          `json:"flowTarefa_id"`
-	OrigemConsultaSerasa      int          `json:"origem_consulta_serasa"`
-	OrigemConsultaSerasaTexto string       `json:"origem_consulta_serasa_texto"`
-	Logs                      []LogAnalise `json:"logs"`
+	ResumoCNPJBase
+	Logs []LogAnalise `json:"logs"`
 }
 
 // EnviaCNPJsComDadosRequest representa a estrutura do corpo da requisição para envio de CNPJs com dados detalhados.
 type EnviaCNPJsComDadosRequest struct {
-	CNPJEmpresa                 string            `json:"cnpj_empresa"`
+	CNPJEmpresa                 Documento         `json:"cnpj_empresa"`
 	IDGrupoAnalise              int               `json:"id_grupo_analise"`
 	ListaCNPJCPFDadosIntegracao []DadosIntegracao `json:"lista_cnpj_cpf_dados_integracao"`
 	PostBack                    *PostBack         `json:"postBack,omitempty"` // PostBack é opcional
@@ -141,63 +125,99 @@ type EnviaCNPJsComDadosRequest struct {
 
 // StatusAnalise representa a resposta da API de status de análise
 type StatusAnalise struct {
-	QuantidadeCNPJsCPFs           int  `json:"quantidade_cnpj_cpf"`
-	QuantidadeConsultasReceita    int  `json:"quantidade_consultas_receita"`
-	PercentualConsultasReceita    int  `json:"percentual_consultas_receita"`
-	QuantidadeCNPJsCPFsConcluidos int  `json:"quantidade_cnpj_cpf_concluidos"`
-	PercentualConcluido           int  `json:"percentual_concluido"`
-	FinalizandoArquivo            bool `json:"finalizando_arquivo"`
-	Concluido                     bool `json:"concluido"`
+	QuantidadeCNPJsCPFs           int                        `json:"quantidade_cnpj_cpf"`
+	QuantidadeConsultasReceita    int                        `json:"quantidade_consultas_receita"`
+	PercentualConsultasReceita    int                        `json:"percentual_consultas_receita"`
+	QuantidadeCNPJsCPFsConcluidos int                        `json:"quantidade_cnpj_cpf_concluidos"`
+	PercentualConcluido           int                        `json:"percentual_concluido"`
+	FinalizandoArquivo            bool                       `json:"finalizando_arquivo"`
+	Concluido                     bool                       `json:"concluido"`
+	Extra                         map[string]json.RawMessage `json:"-"` // Campos da resposta não mapeados pelo SDK
 }
 
 // ResumoAnalise representa a resposta da API de resumo da análise
 type ResumoAnalise struct {
-	AnaliseID              int    `json:"analise_id"`
-	QuantidadeCNPJ         int    `json:"quantidade_cnpj"`
-	QuantidadeCPF          int    `json:"quantidade_cpf"`
-	CNPJEmpresa            string `json:"cnpj_empresa"`
-	Usuario                string `json:"usuario"`
-	DataHoraEnvio          string `json:"data_hora_envio"`
-	DataHoraConclusao      string `json:"data_hora_conclusao"`
-	Concluido              bool   `json:"concluido"`
-	Erro                   bool   `json:"erro"`
-	Alerta                 bool   `json:"alerta"`
-	Bloqueio               bool   `json:"bloqueio"`
-	QuantidadeCNPJAlerta   int    `json:"quantidade_cnpj_alerta"`
-	QuantidadeCNPJBloqueio int    `json:"quantidade_cnpj_bloqueio"`
-	QuantidadeCPFAlerta    int    `json:"quantidade_cpf_alerta"`
-	QuantidadeCPFBloqueio  int    `json:"quantidade_cpf_bloqueio"`
-	IDGrupoAnalise         int    `json:"id_grupo_analise"`
-	NomeGrupoAnalise       string `json:"nome_grupo_analise"`
-	RatingValor            int    `json:"rating_valor"`
-	RatingSigla            string `json:"rating_sigla"`
-	RatingDescricao        string `json:"rating_descricao"`
-	Rating2Valor           int    `json:"rating2_valor"`
-	Rating2Sigla           string `json:"rating2_sigla"`
-	Rating2Descricao       string `json:"rating2_descricao"`
-	NomeLote               string `json:"nome_lote"`
+	AnaliseID              int                        `json:"analise_id"`
+	QuantidadeCNPJ         int                        `json:"quantidade_cnpj"`
+	QuantidadeCPF          int                        `json:"quantidade_cpf"`
+	CNPJEmpresa            Documento                  `json:"cnpj_empresa"`
+	Usuario                string                     `json:"usuario"`
+	DataHoraEnvio          DataHora                   `json:"data_hora_envio"`
+	DataHoraConclusao      DataHora                   `json:"data_hora_conclusao"`
+	Concluido              bool                       `json:"concluido"`
+	Erro                   bool                       `json:"erro"`
+	Alerta                 bool                       `json:"alerta"`
+	Bloqueio               bool                       `json:"bloqueio"`
+	QuantidadeCNPJAlerta   int                        `json:"quantidade_cnpj_alerta"`
+	QuantidadeCNPJBloqueio int                        `json:"quantidade_cnpj_bloqueio"`
+	QuantidadeCPFAlerta    int                        `json:"quantidade_cpf_alerta"`
+	QuantidadeCPFBloqueio  int                        `json:"quantidade_cpf_bloqueio"`
+	IDGrupoAnalise         int                        `json:"id_grupo_analise"`
+	NomeGrupoAnalise       string                     `json:"nome_grupo_analise"`
+	RatingValor            int                        `json:"rating_valor"`
+	RatingSigla            string                     `json:"rating_sigla"`
+	RatingDescricao        string                     `json:"rating_descricao"`
+	Rating2Valor           int                        `json:"rating2_valor"`
+	Rating2Sigla           string                     `json:"rating2_sigla"`
+	Rating2Descricao       string                     `json:"rating2_descricao"`
+	NomeLote               string                     `json:"nome_lote"`
+	Extra                  map[string]json.RawMessage `json:"-"` // Campos da resposta não mapeados pelo SDK
+}
+
+// ResumoCNPJBase reúne os campos comuns ao resumo e ao resumo detalhado de um documento analisado
+type ResumoCNPJBase struct {
+	AnaliseID                 int                        `json:"analise_id"`
+	AnaliseCNPJCPFID          int                        `json:"analise_cnpj_cpf_id"`
+	CNPJCPF                   Documento                  `json:"cnpj_cpf"`
+	Nome                      string                     `json:"nome"`
+	Erro                      bool                       `json:"erro"`
+	Alerta                    bool                       `json:"alerta"`
+	Bloqueio                  bool                       `json:"bloqueio"`
+	Rating                    int                        `json:"rating"`
+	RatingSigla               string                     `json:"rating_sigla"`
+	RatingDescricao           string                     `json:"rating_descricao"`
+	Rating2                   int                        `json:"rating2"`
+	Rating2Sigla              string                     `json:"rating2_sigla"`
+	Rating2Descricao          string                     `json:"rating2_descricao"`
+	NovaConsultaSerasa        bool                       `json:"nova_consulta_serasa"`
+	NovaConsultaSerasaString  NullString                 `json:"nova_consulta_serasa_string_retorno"`
+	FlowSolicitacaoID         int                        `json:"flowSolicitacao_id"`
+	FlowTarefaNome            string                     `json:"flowTarefaNome"`
+	FlowTarefaID              int                        `json:"flowTarefa_id"`
+	OrigemConsultaSerasa      OrigemConsultaSerasa       `json:"origem_consulta_serasa"`
+	OrigemConsultaSerasaTexto string                     `json:"origem_consulta_serasa_texto"`
+	Extra                     map[string]json.RawMessage `json:"-"` // Campos da resposta não mapeados pelo SDK
 }
 
 // Estrutura para armazenar os dados do resumo de CNPJs analisados
 type ResumoCNPJ struct {
-	AnaliseID                 int    `json:"analise_id"`
-	AnaliseCNPJCPFID          int    `json:"analise_cnpj_cpf_id"`
-	CNPJCPF                   string `json:"cnpj_cpf"`
-	Nome                      string `json:"nome"`
-	Erro                      bool   `json:"erro"`
-	Alerta                    bool   `json:"alerta"`
-	Bloqueio                  bool   `json:"bloqueio"`
-	Rating                    int    `json:"rating"`
-	RatingSigla               string `json:"rating_sigla"`
-	RatingDescricao           string `json:"rating_descricao"`
-	Rating2                   int    `json:"rating2"`
-	Rating2Sigla              string `json:"rating2_sigla"`
-	Rating2Descricao          string `json:"rating2_descricao"`
-	NovaConsultaSerasa        bool   `json:"nova_consulta_serasa"`
-	NovaConsultaSerasaString  string `json:"nova_consulta_serasa_string_retorno"`
-	FlowSolicitacaoID         int    `json:"flowSolicitacao_id"`
-	FlowTarefaNome            string `json:"flowTarefaNome"`
-	FlowTarefaID              int    `json:"flowTarefa_id"`
-	OrigemConsultaSerasa      int    `json:"origem_consulta_serasa"`
-	OrigemConsultaSerasaTexto string `json:"origem_consulta_serasa_texto"`
+	ResumoCNPJBase
+}
+
+// Resumo retorna o resumo do documento sem os logs das regras.
+func (d ResumoCNPJDatalhado) Resumo() ResumoCNPJ {
+	return ResumoCNPJ{ResumoCNPJBase: d.ResumoCNPJBase}
+}
+
+// Detalhado retorna o resumo do documento acompanhado dos logs informados.
+func (r ResumoCNPJ) Detalhado(logs []LogAnalise) ResumoCNPJDatalhado {
+	return ResumoCNPJDatalhado{ResumoCNPJBase: r.ResumoCNPJBase, Logs: logs}
+}
+
+// MesclaResumosDetalhados combina os resumos de ListaResumoCNPJs com os logs de
+// ListaResumoCNPJsDetalhado, associando-os pelo documento normalizado. Os campos do resumo
+// prevalecem; documentos sem detalhamento são retornados sem logs.
+func MesclaResumosDetalhados(resumos []ResumoCNPJ, detalhados []ResumoCNPJDatalhado) []ResumoCNPJDatalhado {
+	logs := make(map[Documento][]LogAnalise, len(detalhados))
+	for _, detalhado := range detalhados {
+		documento := Documento(NormalizaDocumento(string(detalhado.CNPJCPF)))
+		logs[documento] = detalhado.Logs
+	}
+
+	mesclados := make([]ResumoCNPJDatalhado, 0, len(resumos))
+	for _, resumo := range resumos {
+		documento := Documento(NormalizaDocumento(string(resumo.CNPJCPF)))
+		mesclados = append(mesclados, resumo.Detalhado(logs[documento]))
+	}
+	return mesclados
 }
@@ -0,0 +1,44 @@
+package vadu_test
+
+import (
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ModelsTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestModelsTestSuite(t *testing.T) {
+	suite.Run(t, new(ModelsTestSuite))
+}
+
+func (s *ModelsTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestMesclaResumosDetalhados verifica a combinação de resumos e logs pelo documento
+func (s *ModelsTestSuite) TestMesclaResumosDetalhados() {
+	resumos := []vadu.ResumoCNPJ{
+		{ResumoCNPJBase: vadu.ResumoCNPJBase{AnaliseCNPJCPFID: 1, CNPJCPF: "98960887000164", Rating: 500}},
+		{ResumoCNPJBase: vadu.ResumoCNPJBase{AnaliseCNPJCPFID: 2, CNPJCPF: "33011770000199", Rating: 700}},
+		{ResumoCNPJBase: vadu.ResumoCNPJBase{CNPJCPF: "52998224725", Rating: 600}},
+	}
+	detalhados := []vadu.ResumoCNPJDatalhado{
+		// Associados pelo documento mesmo quando apenas um dos lados informa o ID ou a máscara
+		{ResumoCNPJBase: vadu.ResumoCNPJBase{CNPJCPF: "98.960.887/0001-64"}, Logs: []vadu.LogAnalise{{RegraDescricao: "Cadastro 14"}}},
+		{ResumoCNPJBase: vadu.ResumoCNPJBase{AnaliseCNPJCPFID: 3, CNPJCPF: "529.982.247-25"}, Logs: []vadu.LogAnalise{{RegraDescricao: "Cadastro 13"}}},
+	}
+
+	mesclados := vadu.MesclaResumosDetalhados(resumos, detalhados)
+	s.assert.Len(mesclados, 3)
+	s.assert.Equal(500, mesclados[0].Rating)
+	s.assert.Len(mesclados[0].Logs, 1)
+	s.assert.Empty(mesclados[1].Logs)
+	s.assert.Equal(resumos[1], mesclados[1].Resumo())
+	s.assert.Len(mesclados[2].Logs, 1)
+}
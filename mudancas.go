@@ -0,0 +1,273 @@
+package vadu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrDocumentosDiferentes indica a comparação de resumos de documentos diferentes.
+var ErrDocumentosDiferentes = errors.New("resumos de documentos diferentes")
+
+// TipoMudanca classifica as mudanças entre duas análises de um documento.
+type TipoMudanca string
+
+const (
+	// MudancaRating indica a alteração do rating principal.
+	MudancaRating TipoMudanca = "rating"
+	// MudancaRating2 indica a alteração do rating secundário.
+	MudancaRating2 TipoMudanca = "rating2"
+	// MudancaStatus indica a alteração de um indicador do documento: erro, alerta ou bloqueio.
+	MudancaStatus TipoMudanca = "status"
+	// MudancaRegraAdicionada indica uma regra que passou a disparar (erro, alerta ou bloqueio).
+	MudancaRegraAdicionada TipoMudanca = "regra_adicionada"
+	// MudancaRegraRemovida indica uma regra que deixou de disparar.
+	MudancaRegraRemovida TipoMudanca = "regra_removida"
+	// MudancaRegraAlterada indica uma regra que disparou nas duas análises com indicadores diferentes.
+	MudancaRegraAlterada TipoMudanca = "regra_alterada"
+)
+
+// Indicadores de MudancaStatus.
+const (
+	IndicadorErro     = "erro"
+	IndicadorAlerta   = "alerta"
+	IndicadorBloqueio = "bloqueio"
+)
+
+// MudancaDocumento descreve uma mudança entre duas análises do mesmo documento.
+type MudancaDocumento struct {
+	Tipo              TipoMudanca
+	Documento         Documento
+	AnaliseAnteriorID int
+	AnaliseAtualID    int
+
+	// MudancaRating e MudancaRating2
+	RatingAnterior Rating
+	RatingAtual    Rating
+	Delta          int // Diferença de pontuação, atual menos anterior
+
+	// MudancaStatus
+	Indicador string // IndicadorErro, IndicadorAlerta ou IndicadorBloqueio
+	Anterior  bool
+	Atual     bool
+
+	// Mudanças de regra: o log da análise anterior e/ou da atual
+	RegraAnterior *LogAnalise
+	RegraAtual    *LogAnalise
+}
+
+// Piora informa se a mudança é desfavorável: rating pior, indicador que passou a ser
+// verdadeiro, regra que passou a disparar ou regra que passou a bloquear.
+func (m MudancaDocumento) Piora() bool {
+	switch m.Tipo {
+	case MudancaRating, MudancaRating2:
+		return m.RatingAtual.PiorQue(m.RatingAnterior)
+	case MudancaStatus:
+		return m.Atual && !m.Anterior
+	case MudancaRegraAdicionada:
+		return true
+	case MudancaRegraAlterada:
+		return m.RegraAtual.Bloqueio && !m.RegraAnterior.Bloqueio ||
+			m.RegraAtual.Erro && !m.RegraAnterior.Erro
+	default:
+		return false
+	}
+}
+
+// String descreve a mudança em uma frase.
+func (m MudancaDocumento) String() string {
+	switch m.Tipo {
+	case MudancaRating, MudancaRating2:
+		return fmt.Sprintf("%s: %s de %s para %s (%+d)", m.Documento, m.Tipo, m.RatingAnterior, m.RatingAtual, m.Delta)
+	case MudancaStatus:
+		return fmt.Sprintf("%s: %s de %t para %t", m.Documento, m.Indicador, m.Anterior, m.Atual)
+	case MudancaRegraRemovida:
+		return fmt.Sprintf("%s: %s %q", m.Documento, m.Tipo, m.RegraAnterior.RegraDescricao)
+	default:
+		return fmt.Sprintf("%s: %s %q", m.Documento, m.Tipo, m.RegraAtual.RegraDescricao)
+	}
+}
+
+// regraDispara informa se a regra produziu erro, alerta ou bloqueio.
+func regraDispara(log LogAnalise) bool {
+	return log.Erro || log.Alerta || log.Bloqueio
+}
+
+// chaveRegra identifica a regra entre análises.
+func chaveRegra(log LogAnalise) string {
+	return strings.TrimSpace(log.AnaliseDescricao) + "\x00" + strings.TrimSpace(log.RegraDescricao)
+}
+
+// DiffResumos compara duas análises do mesmo documento e retorna as mudanças, na ordem:
+// ratings, indicadores e regras (na ordem dos logs). Retorna ErrDocumentosDiferentes se os
+// resumos forem de documentos diferentes.
+func DiffResumos(anterior, atual ResumoCNPJDatalhado) ([]MudancaDocumento, error) {
+	documento := Documento(NormalizaDocumento(string(atual.CNPJCPF)))
+	if NormalizaDocumento(string(anterior.CNPJCPF)) != string(documento) {
+		return nil, fmt.Errorf("%w: %q e %q", ErrDocumentosDiferentes, anterior.CNPJCPF, atual.CNPJCPF)
+	}
+
+	base := MudancaDocumento{
+		Documento:         documento,
+		AnaliseAnteriorID: anterior.AnaliseID,
+		AnaliseAtualID:    atual.AnaliseID,
+	}
+	var mudancas []MudancaDocumento
+
+	// Ratings
+	for _, rating := range []struct {
+		tipo            TipoMudanca
+		anterior, atual Rating
+	}{
+		{MudancaRating, anterior.RatingPrincipal(), atual.RatingPrincipal()},
+		{MudancaRating2, anterior.RatingSecundario(), atual.RatingSecundario()},
+	} {
+		if rating.anterior.Compare(rating.atual) != 0 {
+			mudanca := base
+			mudanca.Tipo, mudanca.RatingAnterior, mudanca.RatingAtual = rating.tipo, rating.anterior, rating.atual
+			mudanca.Delta = rating.atual.Delta(rating.anterior)
+			mudancas = append(mudancas, mudanca)
+		}
+	}
+
+	// Indicadores
+	for _, indicador := range []struct {
+		nome            string
+		anterior, atual bool
+	}{
+		{IndicadorErro, anterior.Erro, atual.Erro},
+		{IndicadorAlerta, anterior.Alerta, atual.Alerta},
+		{IndicadorBloqueio, anterior.Bloqueio, atual.Bloqueio},
+	} {
+		if indicador.anterior != indicador.atual {
+			mudanca := base
+			mudanca.Tipo, mudanca.Indicador = MudancaStatus, indicador.nome
+			mudanca.Anterior, mudanca.Atual = indicador.anterior, indicador.atual
+			mudancas = append(mudancas, mudanca)
+		}
+	}
+
+	// Regras
+	disparadas := make(map[string]LogAnalise)
+	for _, log := range anterior.Logs {
+		if regraDispara(log) {
+			disparadas[chaveRegra(log)] = log
+		}
+	}
+	vistas := make(map[string]bool)
+	for i := range atual.Logs {
+		log := atual.Logs[i]
+		chave := chaveRegra(log)
+		if !regraDispara(log) || vistas[chave] {
+			continue
+		}
+		vistas[chave] = true
+
+		mudanca := base
+		mudanca.RegraAtual = &atual.Logs[i]
+		regraAnterior, disparava := disparadas[chave]
+		switch {
+		case !disparava:
+			mudanca.Tipo = MudancaRegraAdicionada
+		case regraAnterior.Erro != log.Erro || regraAnterior.Alerta != log.Alerta || regraAnterior.Bloqueio != log.Bloqueio:
+			mudanca.Tipo, mudanca.RegraAnterior = MudancaRegraAlterada, &regraAnterior
+		default:
+			continue
+		}
+		mudancas = append(mudancas, mudanca)
+	}
+	for i := range anterior.Logs {
+		log := anterior.Logs[i]
+		chave := chaveRegra(log)
+		if !regraDispara(log) || vistas[chave] {
+			continue
+		}
+		vistas[chave] = true
+		mudanca := base
+		mudanca.Tipo, mudanca.RegraAnterior = MudancaRegraRemovida, &anterior.Logs[i]
+		mudancas = append(mudancas, mudanca)
+	}
+	return mudancas, nil
+}
+
+// Notificador recebe as mudanças detectadas entre análises.
+type Notificador interface {
+	Notifica(ctx context.Context, mudancas []MudancaDocumento) error
+}
+
+// NotificadorFunc adapta uma função à interface Notificador.
+type NotificadorFunc func(ctx context.Context, mudancas []MudancaDocumento) error
+
+// Notifica chama a função.
+func (f NotificadorFunc) Notifica(ctx context.Context, mudancas []MudancaDocumento) error {
+	return f(ctx, mudancas)
+}
+
+// DetectorMudancas compara análises sucessivas e envia as mudanças ao Notificador.
+type DetectorMudancas struct {
+	Notificador Notificador                 // Recebe as mudanças detectadas (opcional)
+	Filtro      func(MudancaDocumento) bool // Seleciona as mudanças notificadas (opcional, padrão todas)
+	Logger      *logrus.Logger              // Opcional, padrão logrus.StandardLogger
+}
+
+// Compara detecta as mudanças entre duas análises do mesmo documento e, havendo mudanças
+// que passem pelo Filtro, as envia ao Notificador. Retorna as mudanças filtradas.
+func (d *DetectorMudancas) Compara(ctx context.Context, anterior, atual ResumoCNPJDatalhado) ([]MudancaDocumento, error) {
+	mudancas, err := DiffResumos(anterior, atual)
+	if err != nil {
+		return nil, err
+	}
+	return d.notifica(ctx, mudancas)
+}
+
+// ComparaResultados detecta as mudanças dos documentos presentes nos dois resultados, como os
+// de duas execuções de Analyze, e as envia ao Notificador em uma única chamada. Documentos sem
+// resumo em algum dos resultados são ignorados.
+func (d *DetectorMudancas) ComparaResultados(ctx context.Context, anterior, atual *AnalysisResult) ([]MudancaDocumento, error) {
+	var mudancas []MudancaDocumento
+	for _, resultado := range atual.Resultados() {
+		resultadoAnterior, ok := anterior.Documentos[resultado.Documento]
+		if !ok || resultadoAnterior.Resumo == nil || resultado.Resumo == nil {
+			continue
+		}
+		doDocumento, err := DiffResumos(
+			ResumoCNPJDatalhado{ResumoCNPJBase: resultadoAnterior.Resumo.ResumoCNPJBase, Logs: resultadoAnterior.Logs},
+			ResumoCNPJDatalhado{ResumoCNPJBase: resultado.Resumo.ResumoCNPJBase, Logs: resultado.Logs},
+		)
+		if err != nil {
+			return nil, err
+		}
+		mudancas = append(mudancas, doDocumento...)
+	}
+	return d.notifica(ctx, mudancas)
+}
+
+// notifica aplica o filtro e envia as mudanças ao Notificador.
+func (d *DetectorMudancas) notifica(ctx context.Context, mudancas []MudancaDocumento) ([]MudancaDocumento, error) {
+	if d.Filtro != nil {
+		filtradas := mudancas[:0]
+		for _, mudanca := range mudancas {
+			if d.Filtro(mudanca) {
+				filtradas = append(filtradas, mudanca)
+			}
+		}
+		mudancas = filtradas
+	}
+	if len(mudancas) == 0 || d.Notificador == nil {
+		return mudancas, nil
+	}
+
+	logger := d.Logger
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+	if err := d.Notificador.Notifica(ctx, mudancas); err != nil {
+		logger.WithField("mudancas", len(mudancas)).WithError(err).Error("Erro ao notificar mudanças entre análises")
+		return mudancas, fmt.Errorf("erro ao notificar mudanças: %w", err)
+	}
+	logger.WithField("mudancas", len(mudancas)).Info("Mudanças entre análises notificadas")
+	return mudancas, nil
+}
@@ -0,0 +1,154 @@
+package vadu_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MudancasTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	ctx    context.Context
+	logger *logrus.Logger
+}
+
+func TestMudancasTestSuite(t *testing.T) {
+	suite.Run(t, new(MudancasTestSuite))
+}
+
+func (s *MudancasTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+}
+
+func resumoDetalhado(analiseID int, rating int, sigla string, alerta, bloqueio bool, logs ...vadu.LogAnalise) vadu.ResumoCNPJDatalhado {
+	return vadu.ResumoCNPJDatalhado{
+		ResumoCNPJBase: vadu.ResumoCNPJBase{
+			AnaliseID:    analiseID,
+			CNPJCPF:      "98960887000164",
+			Alerta:       alerta,
+			Bloqueio:     bloqueio,
+			Rating:       rating,
+			RatingSigla:  sigla,
+			Rating2:      0,
+			Rating2Sigla: "Fora de faixa: 0",
+		},
+		Logs: logs,
+	}
+}
+
+// TestDiffResumos verifica os eventos de rating, indicadores e regras
+func (s *MudancasTestSuite) TestDiffResumos() {
+	anterior := resumoDetalhado(100, 700, "A (700)", false, false,
+		vadu.LogAnalise{AnaliseDescricao: "Análise CNPJs", RegraDescricao: "Protestos", Alerta: true},
+		vadu.LogAnalise{AnaliseDescricao: "Análise CNPJs", RegraDescricao: "Sócios", Alerta: true},
+		vadu.LogAnalise{AnaliseDescricao: "Análise CNPJs", RegraDescricao: "Cadastro"},
+	)
+	atual := resumoDetalhado(200, 500, "B (500)", true, true,
+		vadu.LogAnalise{AnaliseDescricao: "Análise CNPJs", RegraDescricao: "Protestos", Alerta: true, Bloqueio: true},
+		vadu.LogAnalise{AnaliseDescricao: "Análise CNPJs", RegraDescricao: "Cadastro", Erro: true},
+		vadu.LogAnalise{AnaliseDescricao: "Análise CNPJs", RegraDescricao: "Sócios"},
+	)
+	atual.CNPJCPF = "98.960.887/0001-64"
+
+	mudancas, err := vadu.DiffResumos(anterior, atual)
+	s.assert.NoError(err)
+	tipos := make([]vadu.TipoMudanca, 0, len(mudancas))
+	for _, mudanca := range mudancas {
+		tipos = append(tipos, mudanca.Tipo)
+		s.assert.Equal(vadu.Documento("98960887000164"), mudanca.Documento)
+		s.assert.Equal(100, mudanca.AnaliseAnteriorID)
+		s.assert.Equal(200, mudanca.AnaliseAtualID)
+		// Apenas a regra que deixou de disparar é favorável
+		s.assert.Equal(mudanca.Tipo != vadu.MudancaRegraRemovida, mudanca.Piora(), mudanca.String())
+	}
+	s.assert.Equal([]vadu.TipoMudanca{
+		vadu.MudancaRating, vadu.MudancaStatus, vadu.MudancaStatus,
+		vadu.MudancaRegraAlterada, vadu.MudancaRegraAdicionada, vadu.MudancaRegraRemovida,
+	}, tipos)
+
+	s.assert.Equal(-200, mudancas[0].Delta)
+	s.assert.Equal("B", mudancas[0].RatingAtual.Letra)
+	s.assert.Equal(vadu.IndicadorAlerta, mudancas[1].Indicador)
+	s.assert.Equal(vadu.IndicadorBloqueio, mudancas[2].Indicador)
+	s.assert.Equal("Protestos", mudancas[3].RegraAtual.RegraDescricao)
+	s.assert.False(mudancas[3].RegraAnterior.Bloqueio)
+	s.assert.Equal("Cadastro", mudancas[4].RegraAtual.RegraDescricao)
+	s.assert.Equal("Sócios", mudancas[5].RegraAnterior.RegraDescricao)
+	s.assert.Contains(mudancas[0].String(), "A (700) para B (500) (-200)")
+
+	// A mudança inversa é favorável
+	inversas, err := vadu.DiffResumos(atual, anterior)
+	s.assert.NoError(err)
+	s.assert.Len(inversas, 6)
+	s.assert.False(inversas[0].Piora())
+	s.assert.Equal(200, inversas[0].Delta)
+
+	// Sem mudanças
+	mudancas, err = vadu.DiffResumos(anterior, anterior)
+	s.assert.NoError(err)
+	s.assert.Empty(mudancas)
+
+	outro := anterior
+	outro.CNPJCPF = "00360305000104"
+	_, err = vadu.DiffResumos(anterior, outro)
+	s.assert.ErrorIs(err, vadu.ErrDocumentosDiferentes)
+}
+
+// TestDetectorMudancas verifica o filtro, a notificação e a comparação de resultados de Analyze
+func (s *MudancasTestSuite) TestDetectorMudancas() {
+	var notificadas [][]vadu.MudancaDocumento
+	detector := &vadu.DetectorMudancas{
+		Notificador: vadu.NotificadorFunc(func(ctx context.Context, mudancas []vadu.MudancaDocumento) error {
+			notificadas = append(notificadas, mudancas)
+			return nil
+		}),
+		Filtro: vadu.MudancaDocumento.Piora,
+		Logger: s.logger,
+	}
+
+	anterior := resumoDetalhado(100, 700, "A (700)", false, false)
+	atual := resumoDetalhado(200, 500, "B (500)", false, false)
+	mudancas, err := detector.Compara(s.ctx, anterior, atual)
+	s.assert.NoError(err)
+	s.assert.Len(mudancas, 1)
+	s.assert.Equal(vadu.MudancaRating, mudancas[0].Tipo)
+	s.assert.Len(notificadas, 1)
+
+	// Apenas mudanças favoráveis: nada é notificado
+	_, err = detector.Compara(s.ctx, atual, anterior)
+	s.assert.NoError(err)
+	s.assert.Len(notificadas, 1)
+
+	// Comparação de resultados consolidados, com uma única notificação
+	resultado := func(resumo vadu.ResumoCNPJDatalhado) *vadu.AnalysisResult {
+		return &vadu.AnalysisResult{
+			Documentos: map[vadu.Documento]*vadu.ResultadoDocumento{
+				"98960887000164": {Documento: "98960887000164", Resumo: &vadu.ResumoCNPJ{ResumoCNPJBase: resumo.ResumoCNPJBase}, Logs: resumo.Logs},
+				"52998224725":    {Documento: "52998224725"},
+			},
+			Ordem: []vadu.Documento{"98960887000164", "52998224725"},
+		}
+	}
+	mudancas, err = detector.ComparaResultados(s.ctx, resultado(anterior), resultado(atual))
+	s.assert.NoError(err)
+	s.assert.Len(mudancas, 1)
+	s.assert.Len(notificadas, 2)
+
+	// Falha do notificador
+	detector.Notificador = vadu.NotificadorFunc(func(ctx context.Context, mudancas []vadu.MudancaDocumento) error {
+		return errors.New("indisponível")
+	})
+	mudancas, err = detector.Compara(s.ctx, anterior, atual)
+	s.assert.Error(err)
+	s.assert.Len(mudancas, 1)
+}
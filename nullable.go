@@ -0,0 +1,100 @@
+package vadu
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// NullString representa um texto que pode ser nulo na API. Diferente de *string
+// ou string, preserva a distinção entre null e "" na decodificação e na serialização.
+type NullString struct {
+	Valor  string
+	Valido bool // false quando o valor é null ou ausente
+}
+
+// NewNullString cria um NullString válido com o valor informado.
+func NewNullString(valor string) NullString {
+	return NullString{Valor: valor, Valido: true}
+}
+
+// String retorna o valor ou "" quando nulo.
+func (n NullString) String() string {
+	return n.Valor
+}
+
+// Ptr retorna um ponteiro para o valor ou nil quando nulo.
+func (n NullString) Ptr() *string {
+	if !n.Valido {
+		return nil
+	}
+	valor := n.Valor
+	return &valor
+}
+
+// MarshalJSON serializa o valor ou null.
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valido {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Valor)
+}
+
+// UnmarshalJSON decodifica um texto ou null.
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = NullString{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Valor); err != nil {
+		return err
+	}
+	n.Valido = true
+	return nil
+}
+
+// NullBool representa um booleano que pode ser nulo na API, preservando a
+// distinção entre null e false.
+type NullBool struct {
+	Valor  bool
+	Valido bool // false quando o valor é null ou ausente
+}
+
+// NewNullBool cria um NullBool válido com o valor informado.
+func NewNullBool(valor bool) NullBool {
+	return NullBool{Valor: valor, Valido: true}
+}
+
+// Verdadeiro informa se o valor é válido e true.
+func (n NullBool) Verdadeiro() bool {
+	return n.Valido && n.Valor
+}
+
+// Ptr retorna um ponteiro para o valor ou nil quando nulo.
+func (n NullBool) Ptr() *bool {
+	if !n.Valido {
+		return nil
+	}
+	valor := n.Valor
+	return &valor
+}
+
+// MarshalJSON serializa o valor ou null.
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	if !n.Valido {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Valor)
+}
+
+// UnmarshalJSON decodifica um booleano ou null.
+func (n *NullBool) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = NullBool{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Valor); err != nil {
+		return err
+	}
+	n.Valido = true
+	return nil
+}
@@ -0,0 +1,57 @@
+package vadu_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NullableTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestNullableTestSuite(t *testing.T) {
+	suite.Run(t, new(NullableTestSuite))
+}
+
+func (s *NullableTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestNullStringRoundTrip verifica a distinção entre null e "" na decodificação e na serialização
+func (s *NullableTestSuite) TestNullStringRoundTrip() {
+	var resumo vadu.ResumoCNPJ
+	s.assert.NoError(json.Unmarshal([]byte(`{"nova_consulta_serasa_string_retorno": null}`), &resumo))
+	s.assert.False(resumo.NovaConsultaSerasaString.Valido)
+	s.assert.Nil(resumo.NovaConsultaSerasaString.Ptr())
+
+	data, err := json.Marshal(resumo)
+	s.assert.NoError(err)
+	s.assert.Contains(string(data), `"nova_consulta_serasa_string_retorno":null`)
+
+	s.assert.NoError(json.Unmarshal([]byte(`{"nova_consulta_serasa_string_retorno": ""}`), &resumo))
+	s.assert.Equal(vadu.NewNullString(""), resumo.NovaConsultaSerasaString)
+
+	data, err = json.Marshal(resumo)
+	s.assert.NoError(err)
+	s.assert.Contains(string(data), `"nova_consulta_serasa_string_retorno":""`)
+}
+
+// TestNullBoolRoundTrip verifica a distinção entre null e false
+func (s *NullableTestSuite) TestNullBoolRoundTrip() {
+	var log vadu.LogAnalise
+	s.assert.NoError(json.Unmarshal([]byte(`{"erroConsulta": null}`), &log))
+	s.assert.False(log.ErroConsulta.Valido)
+	s.assert.False(log.ErroConsulta.Verdadeiro())
+
+	s.assert.NoError(json.Unmarshal([]byte(`{"erroConsulta": false}`), &log))
+	s.assert.Equal(vadu.NewNullBool(false), log.ErroConsulta)
+
+	data, err := json.Marshal(vadu.LogAnalise{ErroConsulta: vadu.NewNullBool(true)})
+	s.assert.NoError(err)
+	s.assert.Contains(string(data), `"erroConsulta":true`)
+}
@@ -0,0 +1,627 @@
+package vadu
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatoPlanilha identifica o formato do arquivo de planilha.
+type FormatoPlanilha int
+
+const (
+	// FormatoAutomatico detecta o formato pela extensão do arquivo ou pelo conteúdo.
+	FormatoAutomatico FormatoPlanilha = iota
+	// FormatoCSV é um arquivo de texto separado por ponto e vírgula, vírgula ou tabulação.
+	FormatoCSV
+	// FormatoXLSX é uma planilha do Excel (Office Open XML).
+	FormatoXLSX
+)
+
+// ErrPlanilhaInvalida indica um arquivo que não pôde ser lido como planilha.
+var ErrPlanilhaInvalida = errors.New("planilha inválida")
+
+// PlanilhaOptions configura a leitura de planilhas.
+type PlanilhaOptions struct {
+	Formato   FormatoPlanilha // Formato do arquivo (padrão FormatoAutomatico)
+	Aba       string          // Nome da aba no XLSX (padrão a primeira)
+	Separador rune            // Separador do CSV (padrão detectado entre ';', ',' e tabulação)
+
+	// Colunas associa o nome JSON de um campo de DadosIntegracao (ex.: "ativoTotal") ao
+	// cabeçalho da coluna na planilha. Campos não mapeados são procurados por um cabeçalho
+	// com o próprio nome JSON. Os cabeçalhos são comparados sem acentos, espaços, "_" e
+	// sem diferenciar maiúsculas e minúsculas. O documento é procurado também nas colunas
+	// "cnpj", "cpf", "cnpj_cpf" e "documento".
+	Colunas map[string]string
+}
+
+// ErroLinha descreve um problema em uma linha da planilha. As linhas com erro não são
+// incluídas no resultado.
+type ErroLinha struct {
+	Linha  int    // Número da linha na planilha, começando em 1
+	Coluna string // Cabeçalho da coluna, quando o problema é em uma célula
+	Valor  string // Conteúdo da célula
+	Err    error
+}
+
+// Error descreve o problema com a linha e a coluna.
+func (e ErroLinha) Error() string {
+	if e.Coluna == "" {
+		return fmt.Sprintf("linha %d: %v", e.Linha, e.Err)
+	}
+	return fmt.Sprintf("linha %d, coluna %q (%q): %v", e.Linha, e.Coluna, e.Valor, e.Err)
+}
+
+// Unwrap retorna o erro da linha.
+func (e ErroLinha) Unwrap() error {
+	return e.Err
+}
+
+// colunasDocumento são os cabeçalhos aceitos para a coluna do documento.
+var colunasDocumento = []string{"cnpjcpf", "cnpj_cpf", "cnpj", "cpf", "documento"}
+
+// LeDocumentosPlanilha lê a coluna de documentos de uma planilha, no formato esperado por
+// EnviaCNPJsParaAnalise. Documentos inválidos e vazios são reportados por linha.
+func LeDocumentosPlanilha(r io.Reader, opts PlanilhaOptions) ([]string, []ErroLinha, error) {
+	linhas, cabecalhoLinha, err := lePlanilha(r, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	cabecalho, coluna, err := colunaDocumento(linhas, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var documentos []string
+	var erros []ErroLinha
+	for i, linha := range linhas[1:] {
+		if linhaVazia(linha) {
+			continue
+		}
+		valor := celula(linha, coluna)
+		documento, err := documentoPlanilha(valor)
+		if err != nil {
+			erros = append(erros, ErroLinha{Linha: cabecalhoLinha + i + 1, Coluna: cabecalho, Valor: valor, Err: err})
+			continue
+		}
+		documentos = append(documentos, documento.String())
+	}
+	return documentos, erros, nil
+}
+
+// LeDadosIntegracaoPlanilha lê uma planilha com uma linha por documento e uma coluna por campo
+// de DadosIntegracao, no formato esperado por EnviaCNPJsComDadosParaAnalise. Números aceitam o
+// formato brasileiro (veja ParseNumeroBR). Células inválidas são reportadas por linha.
+func LeDadosIntegracaoPlanilha(r io.Reader, opts PlanilhaOptions) ([]DadosIntegracao, []ErroLinha, error) {
+	linhas, cabecalhoLinha, err := lePlanilha(r, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, colunaDoc, err := colunaDocumento(linhas, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Associar as colunas da planilha aos campos de DadosIntegracao
+	indices := indicesCabecalho(linhas[0])
+	tipo := reflect.TypeOf(DadosIntegracao{})
+	type colunaCampo struct {
+		indice    int
+		cabecalho string
+		campo     int
+	}
+	var colunas []colunaCampo
+	for i := 0; i < tipo.NumField(); i++ {
+		nome := strings.Split(tipo.Field(i).Tag.Get("json"), ",")[0]
+		if nome == "" || nome == "cnpjcpf" {
+			continue
+		}
+		cabecalho := nome
+		if mapeado, ok := opts.Colunas[nome]; ok {
+			cabecalho = mapeado
+		}
+		if indice, ok := indices[normalizaCabecalho(cabecalho)]; ok {
+			colunas = append(colunas, colunaCampo{indice: indice, cabecalho: linhas[0][indice], campo: i})
+		} else if _, mapeado := opts.Colunas[nome]; mapeado {
+			return nil, nil, fmt.Errorf("%w: coluna %q não encontrada", ErrPlanilhaInvalida, cabecalho)
+		}
+	}
+
+	var lista []DadosIntegracao
+	var erros []ErroLinha
+	for i, linha := range linhas[1:] {
+		if linhaVazia(linha) {
+			continue
+		}
+		numero := cabecalhoLinha + i + 1
+		var dados DadosIntegracao
+		documento, err := documentoPlanilha(celula(linha, colunaDoc))
+		if err != nil {
+			erros = append(erros, ErroLinha{Linha: numero, Coluna: linhas[0][colunaDoc], Valor: celula(linha, colunaDoc), Err: err})
+			continue
+		}
+		dados.CNPJCPF = documento
+
+		valido := true
+		valor := reflect.ValueOf(&dados).Elem()
+		for _, coluna := range colunas {
+			texto := celula(linha, coluna.indice)
+			if strings.TrimSpace(texto) == "" {
+				continue
+			}
+			if err := atribuiCelula(valor.Field(coluna.campo), texto); err != nil {
+				erros = append(erros, ErroLinha{Linha: numero, Coluna: coluna.cabecalho, Valor: texto, Err: err})
+				valido = false
+			}
+		}
+		if valido {
+			lista = append(lista, dados)
+		}
+	}
+	return lista, erros, nil
+}
+
+// LeDocumentosArquivo lê os documentos de um arquivo CSV ou XLSX, detectando o formato pela extensão.
+func LeDocumentosArquivo(caminho string, opts PlanilhaOptions) ([]string, []ErroLinha, error) {
+	arquivo, opts, err := abrePlanilha(caminho, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer arquivo.Close()
+	return LeDocumentosPlanilha(arquivo, opts)
+}
+
+// LeDadosIntegracaoArquivo lê os dados de integração de um arquivo CSV ou XLSX, detectando o formato pela extensão.
+func LeDadosIntegracaoArquivo(caminho string, opts PlanilhaOptions) ([]DadosIntegracao, []ErroLinha, error) {
+	arquivo, opts, err := abrePlanilha(caminho, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer arquivo.Close()
+	return LeDadosIntegracaoPlanilha(arquivo, opts)
+}
+
+func abrePlanilha(caminho string, opts PlanilhaOptions) (*os.File, PlanilhaOptions, error) {
+	if opts.Formato == FormatoAutomatico {
+		switch strings.ToLower(filepath.Ext(caminho)) {
+		case ".xlsx":
+			opts.Formato = FormatoXLSX
+		case ".csv", ".txt":
+			opts.Formato = FormatoCSV
+		}
+	}
+	arquivo, err := os.Open(caminho)
+	if err != nil {
+		return nil, opts, fmt.Errorf("erro ao abrir planilha: %w", err)
+	}
+	return arquivo, opts, nil
+}
+
+// ParseNumeroBR interpreta números no formato brasileiro ("1.234,56", "-0,5", "R$ 1.234,00",
+// "(1.234,56)" para negativos e "12,5%", lido como 12,5). Sem vírgula, pontos que separam grupos
+// de três dígitos são de milhar ("1.500" e "1.234.567"); nos demais casos, um único ponto é lido
+// como separador decimal, como nos valores exportados por sistemas ("1234.56" e "0.500").
+func ParseNumeroBR(valor string) (float64, error) {
+	texto, err := normalizaNumeroBR(valor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(texto, 64)
+}
+
+// normalizaNumeroBR converte um número no formato brasileiro para a notação com ponto decimal.
+func normalizaNumeroBR(valor string) (string, error) {
+	texto := strings.TrimSpace(valor)
+	texto = strings.TrimPrefix(texto, "R$")
+	texto = strings.TrimSuffix(texto, "%")
+	texto = strings.Map(func(r rune) rune {
+		if r == ' ' || r == ' ' {
+			return -1
+		}
+		return r
+	}, texto)
+
+	negativo := false
+	if strings.HasPrefix(texto, "(") && strings.HasSuffix(texto, ")") {
+		negativo = true
+		texto = texto[1 : len(texto)-1]
+	}
+
+	switch {
+	case strings.Contains(texto, ","):
+		texto = strings.Replace(strings.ReplaceAll(texto, ".", ""), ",", ".", 1)
+	case milharBR(texto):
+		texto = strings.ReplaceAll(texto, ".", "")
+	case strings.Count(texto, ".") > 1:
+		return "", fmt.Errorf("número inválido: %q", valor)
+	}
+	if _, err := strconv.ParseFloat(texto, 64); err != nil || texto == "" {
+		return "", fmt.Errorf("número inválido: %q", valor)
+	}
+	if negativo {
+		texto = "-" + strings.TrimPrefix(texto, "-")
+	}
+	return texto, nil
+}
+
+// milharBR informa se o texto é um inteiro com pontos separando grupos de três dígitos, como
+// "1.500" ou "-1.234.567". O primeiro grupo tem de um a três dígitos e não começa com zero.
+func milharBR(texto string) bool {
+	grupos := strings.Split(strings.TrimPrefix(texto, "-"), ".")
+	if len(grupos) < 2 || len(grupos[0]) > 3 || strings.HasPrefix(grupos[0], "0") {
+		return false
+	}
+	for i, grupo := range grupos {
+		if grupo == "" || !somenteDigitos(grupo) || (i > 0 && len(grupo) != 3) {
+			return false
+		}
+	}
+	return true
+}
+
+// tipoDecimal identifica os campos Decimal, lidos sem passar por float64.
+var tipoDecimal = reflect.TypeOf(Decimal{})
+
+// atribuiCelula converte o texto da célula para o tipo do campo.
+func atribuiCelula(campo reflect.Value, texto string) error {
+	if campo.Type() == tipoDecimal {
+		numero, err := ParseDecimalBR(texto)
+		if err != nil {
+			return err
+		}
+		campo.Set(reflect.ValueOf(numero))
+		return nil
+	}
+
+	switch campo.Kind() {
+	case reflect.Float64:
+		numero, err := ParseNumeroBR(texto)
+		if err != nil {
+			return err
+		}
+		campo.SetFloat(numero)
+	case reflect.Int:
+		numero, err := ParseNumeroBR(texto)
+		if err != nil {
+			return err
+		}
+		if numero != float64(int64(numero)) {
+			return fmt.Errorf("número inteiro esperado: %q", texto)
+		}
+		campo.SetInt(int64(numero))
+	case reflect.String:
+		campo.SetString(strings.TrimSpace(texto))
+	default:
+		return fmt.Errorf("tipo de campo não suportado: %s", campo.Type())
+	}
+	return nil
+}
+
+// documentoPlanilha normaliza e valida o documento de uma célula, restaurando os zeros à
+// esquerda removidos quando a coluna é formatada como número.
+func documentoPlanilha(valor string) (Documento, error) {
+	texto := NormalizaDocumento(valor)
+	if strings.ContainsAny(texto, "E") && !formatoCNPJ(texto) {
+		// Células numéricas do XLSX podem vir em notação científica (ex.: 9.8960887000164E+13)
+		if numero, err := strconv.ParseFloat(valor, 64); err == nil && numero == float64(int64(numero)) {
+			texto = strconv.FormatInt(int64(numero), 10)
+		}
+	}
+	if texto == "" {
+		return "", fmt.Errorf("%w: documento vazio", ErrDocumentoInvalido)
+	}
+	if somenteDigitos(texto) {
+		switch len(texto) {
+		case 12, 13:
+			texto = strings.Repeat("0", 14-len(texto)) + texto
+		case 9, 10:
+			texto = strings.Repeat("0", 11-len(texto)) + texto
+		}
+	}
+	return NewDocumento(texto)
+}
+
+// colunaDocumento encontra a coluna do documento no cabeçalho.
+func colunaDocumento(linhas [][]string, opts PlanilhaOptions) (string, int, error) {
+	indices := indicesCabecalho(linhas[0])
+	candidatos := colunasDocumento
+	if mapeado, ok := opts.Colunas["cnpjcpf"]; ok {
+		candidatos = []string{mapeado}
+	}
+	for _, candidato := range candidatos {
+		if indice, ok := indices[normalizaCabecalho(candidato)]; ok {
+			return linhas[0][indice], indice, nil
+		}
+	}
+	return "", 0, fmt.Errorf("%w: coluna de documento não encontrada (%s)", ErrPlanilhaInvalida, strings.Join(candidatos, ", "))
+}
+
+// indicesCabecalho indexa as colunas pelo cabeçalho normalizado.
+func indicesCabecalho(cabecalho []string) map[string]int {
+	indices := make(map[string]int, len(cabecalho))
+	for i, nome := range cabecalho {
+		chave := normalizaCabecalho(nome)
+		if _, existe := indices[chave]; !existe && chave != "" {
+			indices[chave] = i
+		}
+	}
+	return indices
+}
+
+// normalizaCabecalho remove acentos, espaços e "_" e converte para minúsculas.
+func normalizaCabecalho(nome string) string {
+	nome = semAcentos.Replace(strings.ToUpper(strings.TrimSpace(nome)))
+	nome = strings.NewReplacer(" ", "", "_", "", "-", "", ".", "").Replace(nome)
+	return strings.ToLower(nome)
+}
+
+func celula(linha []string, indice int) string {
+	if indice < len(linha) {
+		return linha[indice]
+	}
+	return ""
+}
+
+func linhaVazia(linha []string) bool {
+	for _, valor := range linha {
+		if strings.TrimSpace(valor) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// lePlanilha lê as linhas da planilha a partir do cabeçalho (a primeira linha não vazia) e
+// retorna também o número da linha do cabeçalho na planilha.
+func lePlanilha(r io.Reader, opts PlanilhaOptions) ([][]string, int, error) {
+	conteudo, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao ler planilha: %w", err)
+	}
+
+	formato := opts.Formato
+	if formato == FormatoAutomatico {
+		formato = FormatoCSV
+		if bytes.HasPrefix(conteudo, []byte("PK\x03\x04")) {
+			formato = FormatoXLSX
+		}
+	}
+
+	var linhas [][]string
+	switch formato {
+	case FormatoXLSX:
+		linhas, err = leXLSX(conteudo, opts.Aba)
+	default:
+		linhas, err = leCSV(conteudo, opts.Separador)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cabecalho := 1
+	for len(linhas) > 0 && linhaVazia(linhas[0]) {
+		linhas = linhas[1:]
+		cabecalho++
+	}
+	if len(linhas) == 0 {
+		return nil, 0, fmt.Errorf("%w: planilha sem cabeçalho", ErrPlanilhaInvalida)
+	}
+	return linhas, cabecalho, nil
+}
+
+// leCSV lê um CSV em UTF-8 (com ou sem BOM) ou ISO-8859-1.
+func leCSV(conteudo []byte, separador rune) ([][]string, error) {
+	conteudo = bytes.TrimPrefix(conteudo, []byte("\xef\xbb\xbf"))
+	texto := string(conteudo)
+	if !utf8.ValidString(texto) {
+		texto = latin1ParaUTF8(texto)
+	}
+
+	if separador == 0 {
+		primeira := texto
+		if fim := strings.IndexAny(texto, "\r\n"); fim >= 0 {
+			primeira = texto[:fim]
+		}
+		separador = ';'
+		for _, candidato := range []rune{';', '\t', ','} {
+			if strings.ContainsRune(primeira, candidato) {
+				separador = candidato
+				break
+			}
+		}
+	}
+
+	leitor := csv.NewReader(strings.NewReader(texto))
+	leitor.Comma = separador
+	leitor.FieldsPerRecord = -1
+	leitor.TrimLeadingSpace = true
+	linhas, err := leitor.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlanilhaInvalida, err)
+	}
+	return linhas, nil
+}
+
+// Estruturas mínimas do Office Open XML usadas na leitura de planilhas.
+type (
+	xlsxWorkbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	xlsxRelationships struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	xlsxTexto struct {
+		T    string `xml:"t"`
+		Runs []struct {
+			T string `xml:"t"`
+		} `xml:"r"`
+	}
+	xlsxSharedStrings struct {
+		Items []xlsxTexto `xml:"si"`
+	}
+	xlsxWorksheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R  string    `xml:"r,attr"`
+				T  string    `xml:"t,attr"`
+				V  string    `xml:"v"`
+				IS xlsxTexto `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+)
+
+func (t xlsxTexto) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var texto strings.Builder
+	for _, run := range t.Runs {
+		texto.WriteString(run.T)
+	}
+	return texto.String()
+}
+
+// leXLSX lê as células de uma aba do XLSX como texto, usando apenas a biblioteca padrão.
+func leXLSX(conteudo []byte, aba string) ([][]string, error) {
+	pacote, err := zip.NewReader(bytes.NewReader(conteudo), int64(len(conteudo)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlanilhaInvalida, err)
+	}
+	arquivos := make(map[string]*zip.File, len(pacote.File))
+	for _, arquivo := range pacote.File {
+		arquivos[arquivo.Name] = arquivo
+	}
+	leXML := func(nome string, destino interface{}) error {
+		arquivo, ok := arquivos[nome]
+		if !ok {
+			return fmt.Errorf("%w: %s não encontrado", ErrPlanilhaInvalida, nome)
+		}
+		conteudo, err := arquivo.Open()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrPlanilhaInvalida, err)
+		}
+		defer conteudo.Close()
+		if err := xml.NewDecoder(conteudo).Decode(destino); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrPlanilhaInvalida, nome, err)
+		}
+		return nil
+	}
+
+	// Localizar o arquivo da aba
+	var workbook xlsxWorkbook
+	var relacionamentos xlsxRelationships
+	if err := leXML("xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if err := leXML("xl/_rels/workbook.xml.rels", &relacionamentos); err != nil {
+		return nil, err
+	}
+	rid := ""
+	for _, sheet := range workbook.Sheets {
+		if aba == "" || strings.EqualFold(sheet.Name, aba) {
+			rid = sheet.RID
+			break
+		}
+	}
+	if rid == "" {
+		return nil, fmt.Errorf("%w: aba %q não encontrada", ErrPlanilhaInvalida, aba)
+	}
+	caminhoAba := ""
+	for _, relacionamento := range relacionamentos.Relationships {
+		if relacionamento.ID == rid {
+			caminhoAba = relacionamento.Target
+			if strings.HasPrefix(caminhoAba, "/") {
+				caminhoAba = strings.TrimPrefix(caminhoAba, "/")
+			} else {
+				caminhoAba = path.Join("xl", caminhoAba)
+			}
+		}
+	}
+
+	var compartilhados xlsxSharedStrings
+	if _, ok := arquivos["xl/sharedStrings.xml"]; ok {
+		if err := leXML("xl/sharedStrings.xml", &compartilhados); err != nil {
+			return nil, err
+		}
+	}
+	var planilha xlsxWorksheet
+	if err := leXML(caminhoAba, &planilha); err != nil {
+		return nil, err
+	}
+
+	var linhas [][]string
+	for i, row := range planilha.Rows {
+		numero := row.R
+		if numero == 0 {
+			numero = i + 1
+		}
+		for len(linhas) < numero {
+			linhas = append(linhas, nil)
+		}
+		var linha []string
+		for j, cell := range row.Cells {
+			coluna := j
+			if cell.R != "" {
+				coluna = colunaXLSX(cell.R)
+			}
+			for len(linha) <= coluna {
+				linha = append(linha, "")
+			}
+			switch cell.T {
+			case "s":
+				indice, err := strconv.Atoi(cell.V)
+				if err != nil || indice < 0 || indice >= len(compartilhados.Items) {
+					return nil, fmt.Errorf("%w: texto compartilhado inválido na célula %s", ErrPlanilhaInvalida, cell.R)
+				}
+				linha[coluna] = compartilhados.Items[indice].String()
+			case "inlineStr":
+				linha[coluna] = cell.IS.String()
+			case "b":
+				linha[coluna] = map[string]string{"1": "true", "0": "false"}[cell.V]
+			case "", "n":
+				// Números do XLSX usam ponto decimal; a vírgula evita que "1.234" seja lido como milhar
+				linha[coluna] = cell.V
+				if !strings.ContainsAny(cell.V, "Ee") {
+					linha[coluna] = strings.Replace(cell.V, ".", ",", 1)
+				}
+			default:
+				// Resultados de fórmula (str), erros (e) e datas (d) são textos
+				linha[coluna] = cell.V
+			}
+		}
+		linhas[numero-1] = linha
+	}
+	return linhas, nil
+}
+
+// colunaXLSX converte a referência da célula (ex.: "AB12") no índice da coluna (27).
+func colunaXLSX(referencia string) int {
+	coluna := 0
+	for _, r := range referencia {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		coluna = coluna*26 + int(r-'A'+1)
+	}
+	return coluna - 1
+}
@@ -0,0 +1,159 @@
+package vadu_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PlanilhaTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestPlanilhaTestSuite(t *testing.T) {
+	suite.Run(t, new(PlanilhaTestSuite))
+}
+
+func (s *PlanilhaTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// xlsx monta uma planilha mínima com textos compartilhados, textos inline e números.
+func (s *PlanilhaTestSuite) xlsx(sheet string) []byte {
+	arquivos := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Resumo" sheetId="1" r:id="rId2"/><sheet name="Dados" sheetId="2" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="worksheet" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>CNPJ</t></si><si><r><t>Ativo </t></r><r><t>Total</t></r></si><si><t>1.234,56</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": sheet,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData/></worksheet>`,
+	}
+	var buffer bytes.Buffer
+	pacote := zip.NewWriter(&buffer)
+	for nome, conteudo := range arquivos {
+		arquivo, err := pacote.Create(nome)
+		s.assert.NoError(err)
+		_, err = arquivo.Write([]byte(conteudo))
+		s.assert.NoError(err)
+	}
+	s.assert.NoError(pacote.Close())
+	return buffer.Bytes()
+}
+
+// TestParseNumeroBR verifica os formatos numéricos aceitos
+func (s *PlanilhaTestSuite) TestParseNumeroBR() {
+	casos := map[string]float64{
+		"1.234,56":      1234.56,
+		"-0,5":          -0.5,
+		"R$ 1.234.567":  1234567,
+		"(1.234,56)":    -1234.56,
+		"12,5%":         12.5,
+		"1234.56":       1234.56,
+		" 3 ":           3,
+		"9.8960887E+13": 98960887000000,
+		"1.500":         1500,
+		"12.345":        12345,
+		"1.234.567":     1234567,
+		"R$ 1.234":      1234,
+		"-1.500":        -1500,
+		"0.500":         0.5,
+		"1234.567":      1234.567,
+		"12.3456":       12.3456,
+	}
+	for entrada, esperado := range casos {
+		valor, err := vadu.ParseNumeroBR(entrada)
+		s.assert.NoError(err, entrada)
+		s.assert.Equal(esperado, valor, entrada)
+	}
+	_, err := vadu.ParseNumeroBR("1,2,3")
+	s.assert.Error(err)
+	_, err = vadu.ParseNumeroBR("1.23.4")
+	s.assert.Error(err)
+	_, err = vadu.ParseNumeroBR("")
+	s.assert.Error(err)
+}
+
+// TestLeDocumentosCSV verifica a leitura dos documentos e os erros por linha
+func (s *PlanilhaTestSuite) TestLeDocumentosCSV() {
+	csv := "\xef\xbb\xbfNome;CNPJ\nEmpresa A;98.960.887/0001-64\n;\nEmpresa B;360305000104\nEmpresa C;123\n"
+	documentos, erros, err := vadu.LeDocumentosPlanilha(strings.NewReader(csv), vadu.PlanilhaOptions{})
+	s.assert.NoError(err)
+	s.assert.Equal([]string{"98960887000164", "00360305000104"}, documentos)
+	s.assert.Len(erros, 1)
+	s.assert.Equal(5, erros[0].Linha)
+	s.assert.Equal("CNPJ", erros[0].Coluna)
+	s.assert.ErrorIs(erros[0], vadu.ErrDocumentoInvalido)
+
+	_, _, err = vadu.LeDocumentosPlanilha(strings.NewReader("nome,telefone\nA,1\n"), vadu.PlanilhaOptions{})
+	s.assert.ErrorIs(err, vadu.ErrPlanilhaInvalida)
+}
+
+// TestLeDadosIntegracaoCSV verifica o mapeamento de colunas e os números no formato brasileiro
+func (s *PlanilhaTestSuite) TestLeDadosIntegracaoCSV() {
+	csv := strings.Join([]string{
+		"documento;Ativo Total;Passivo_Circulante;Score;ratingExterno",
+		"98960887000164;\"1.234,56\";100;761;A",
+		"33011770000199;abc;200;1,5;B",
+	}, "\n")
+	opts := vadu.PlanilhaOptions{Colunas: map[string]string{"scoreExterno": "Score"}}
+	lista, erros, err := vadu.LeDadosIntegracaoPlanilha(strings.NewReader(csv), opts)
+	s.assert.NoError(err)
+	s.assert.Len(lista, 1)
+	s.assert.Equal(vadu.Documento("98960887000164"), lista[0].CNPJCPF)
+	s.assert.Equal(vadu.MustDecimal("1234.56"), lista[0].AtivoTotal)
+	s.assert.Equal(vadu.MustDecimal("100"), lista[0].PassivoCirculante)
+	s.assert.Equal(761, lista[0].ScoreExterno)
+	s.assert.Equal("A", lista[0].RatingExterno)
+
+	s.assert.Len(erros, 2)
+	s.assert.Equal(3, erros[0].Linha)
+	s.assert.Equal("Ativo Total", erros[0].Coluna)
+	s.assert.Equal("Score", erros[1].Coluna)
+
+	opts.Colunas["lucroLiquido"] = "Lucro"
+	_, _, err = vadu.LeDadosIntegracaoPlanilha(strings.NewReader(csv), opts)
+	s.assert.ErrorIs(err, vadu.ErrPlanilhaInvalida)
+}
+
+// TestLeDadosIntegracaoXLSX verifica a leitura de uma aba do XLSX
+func (s *PlanilhaTestSuite) TestLeDadosIntegracaoXLSX() {
+	conteudo := s.xlsx(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="2"><c r="A2" t="s"><v>0</v></c><c r="C2" t="s"><v>1</v></c></row>
+<row r="3"><c r="A3"><v>9.8960887000164E+13</v></c><c r="C3"><v>824167.11</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>33.011.770/0001-99</t></is></c><c r="C4"><v>1.234</v></c></row>
+<row r="5"><c r="A5" t="inlineStr"><is><t>00.360.305/0001-04</t></is></c><c r="C5" t="s"><v>2</v></c></row>
+<row r="6"><c r="A6" t="inlineStr"><is><t>11111111111111</t></is></c></row>
+<row r="7"><c r="A7" t="str"><f>A8</f><v>98.960.887/0001-64</v></c><c r="C7" t="str"><v>1.500,00</v></c></row>
+</sheetData></worksheet>`)
+
+	lista, erros, err := vadu.LeDadosIntegracaoPlanilha(bytes.NewReader(conteudo), vadu.PlanilhaOptions{Aba: "dados"})
+	s.assert.NoError(err)
+	s.assert.Len(lista, 4)
+	s.assert.Equal(vadu.Documento("98960887000164"), lista[0].CNPJCPF)
+	s.assert.Equal(vadu.MustDecimal("824167.11"), lista[0].AtivoTotal)
+	// Células numéricas usam ponto decimal, mesmo com três casas
+	s.assert.Equal(vadu.Documento("33011770000199"), lista[1].CNPJCPF)
+	s.assert.Equal(vadu.MustDecimal("1.234"), lista[1].AtivoTotal)
+	// Células de texto seguem o formato brasileiro
+	s.assert.Equal(vadu.Documento("00360305000104"), lista[2].CNPJCPF)
+	s.assert.Equal(vadu.MustDecimal("1234.56"), lista[2].AtivoTotal)
+	// Resultados de fórmula são textos e também seguem o formato brasileiro
+	s.assert.Equal(vadu.Documento("98960887000164"), lista[3].CNPJCPF)
+	s.assert.Equal(vadu.MustDecimal("1500"), lista[3].AtivoTotal)
+	s.assert.Len(erros, 1)
+	s.assert.Equal(6, erros[0].Linha)
+
+	_, _, err = vadu.LeDadosIntegracaoPlanilha(bytes.NewReader(conteudo), vadu.PlanilhaOptions{Aba: "Outra"})
+	s.assert.ErrorIs(err, vadu.ErrPlanilhaInvalida)
+}
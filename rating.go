@@ -0,0 +1,173 @@
+package vadu
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rating reúne o valor numérico, a letra e a descrição de um rating retornado
+// pela API, que hoje chegam em campos separados (ex.: rating, rating_sigla e
+// rating_descricao).
+type Rating struct {
+	Valor       int    // Pontuação numérica do rating
+	Letra       string // Letra do rating (ex.: "B"), vazia quando fora de faixa
+	ForaDeFaixa bool   // Indica que a pontuação não se enquadra em nenhuma faixa do grupo
+	Sigla       string // Texto original da sigla (ex.: "B (500)" ou "Fora de faixa: 0")
+	Descricao   string // Descrição da faixa (ex.: política de crédito associada)
+}
+
+var (
+	// siglaComValor reconhece siglas no formato "B (500)" ou "AA (-150)".
+	siglaComValor = regexp.MustCompile(`^\s*([^\s(]+)\s*\(\s*(-?\d+)\s*\)\s*$`)
+	// siglaForaDeFaixa reconhece siglas no formato "Fora de faixa: 0".
+	siglaForaDeFaixa = regexp.MustCompile(`(?i)^\s*fora\s+de\s+faixa\s*:?\s*(-?\d+)?\s*$`)
+)
+
+// NewRating monta um Rating a partir dos campos de valor, sigla e descrição da API.
+// O valor informado prevalece sobre o número presente na sigla.
+func NewRating(valor int, sigla, descricao string) Rating {
+	rating := Rating{
+		Valor:     valor,
+		Sigla:     sigla,
+		Descricao: descricao,
+	}
+
+	if siglaForaDeFaixa.MatchString(sigla) {
+		rating.ForaDeFaixa = true
+		return rating
+	}
+	if partes := siglaComValor.FindStringSubmatch(sigla); partes != nil {
+		rating.Letra = partes[1]
+		return rating
+	}
+	rating.Letra = strings.TrimSpace(sigla)
+	return rating
+}
+
+// ParseRatingSigla interpreta apenas a sigla, extraindo o valor numérico nela contido.
+func ParseRatingSigla(sigla string) (Rating, error) {
+	if partes := siglaForaDeFaixa.FindStringSubmatch(sigla); partes != nil {
+		valor := 0
+		if partes[1] != "" {
+			valor, _ = strconv.Atoi(partes[1])
+		}
+		return Rating{Valor: valor, ForaDeFaixa: true, Sigla: sigla}, nil
+	}
+	if partes := siglaComValor.FindStringSubmatch(sigla); partes != nil {
+		valor, err := strconv.Atoi(partes[2])
+		if err != nil {
+			return Rating{}, fmt.Errorf("valor inválido na sigla de rating %q: %w", sigla, err)
+		}
+		return Rating{Valor: valor, Letra: partes[1], Sigla: sigla}, nil
+	}
+	return Rating{}, fmt.Errorf("formato de sigla de rating não reconhecido: %q", sigla)
+}
+
+// Compare compara dois ratings, retornando -1 se r é pior que o outro, 0 se
+// equivalentes e 1 se melhor. Ratings fora de faixa são sempre piores que
+// ratings enquadrados; entre ratings da mesma condição, maior pontuação é melhor.
+func (r Rating) Compare(outro Rating) int {
+	if r.ForaDeFaixa != outro.ForaDeFaixa {
+		if r.ForaDeFaixa {
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case r.Valor < outro.Valor:
+		return -1
+	case r.Valor > outro.Valor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// MelhorQue informa se r é melhor que o outro rating.
+func (r Rating) MelhorQue(outro Rating) bool {
+	return r.Compare(outro) > 0
+}
+
+// PiorQue informa se r é pior que o outro rating.
+func (r Rating) PiorQue(outro Rating) bool {
+	return r.Compare(outro) < 0
+}
+
+// Delta retorna a diferença de pontuação entre r e o outro rating.
+func (r Rating) Delta(outro Rating) int {
+	return r.Valor - outro.Valor
+}
+
+// String retorna a sigla original ou, na ausência dela, uma representação equivalente.
+func (r Rating) String() string {
+	if r.Sigla != "" {
+		return r.Sigla
+	}
+	if r.ForaDeFaixa {
+		return fmt.Sprintf("Fora de faixa: %d", r.Valor)
+	}
+	if r.Letra != "" {
+		return fmt.Sprintf("%s (%d)", r.Letra, r.Valor)
+	}
+	return strconv.Itoa(r.Valor)
+}
+
+// RatingPrincipal retorna o rating principal da análise.
+func (r ResumoAnalise) RatingPrincipal() Rating {
+	return NewRating(r.RatingValor, r.RatingSigla, r.RatingDescricao)
+}
+
+// RatingSecundario retorna o rating secundário (rating2) da análise.
+func (r ResumoAnalise) RatingSecundario() Rating {
+	return NewRating(r.Rating2Valor, r.Rating2Sigla, r.Rating2Descricao)
+}
+
+// RatingPrincipal retorna o rating principal do documento.
+func (r ResumoCNPJBase) RatingPrincipal() Rating {
+	return NewRating(r.Rating, r.RatingSigla, r.RatingDescricao)
+}
+
+// RatingSecundario retorna o rating secundário (rating2) do documento.
+func (r ResumoCNPJBase) RatingSecundario() Rating {
+	return NewRating(r.Rating2, r.Rating2Sigla, r.Rating2Descricao)
+}
+
+// limiteSuperior retorna a maior pontuação possível no grupo. As regras do Vadu
+// descontam pontos a partir de RatingStart, por isso ele é considerado o teto
+// quando RatingMaximo é menor (a API retorna 0 quando não há máximo configurado).
+func (g GrupoAnalise) limiteSuperior() int {
+	if g.RatingMaximo > g.RatingStart {
+		return g.RatingMaximo
+	}
+	return g.RatingStart
+}
+
+// DentroDaFaixa informa se a pontuação do rating está entre RatingMinimo e o teto do grupo.
+func (g GrupoAnalise) DentroDaFaixa(r Rating) bool {
+	return r.Valor >= g.RatingMinimo && r.Valor <= g.limiteSuperior()
+}
+
+// PontosPerdidos retorna quantos pontos o rating perdeu em relação ao RatingStart do grupo.
+func (g GrupoAnalise) PontosPerdidos(r Rating) int {
+	return g.RatingStart - r.Valor
+}
+
+// PercentualRating retorna a posição do rating entre RatingMinimo (0) e o teto do grupo (1),
+// limitada a esse intervalo. Retorna 0 se o grupo não tiver faixa configurada.
+func (g GrupoAnalise) PercentualRating(r Rating) float64 {
+	amplitude := g.limiteSuperior() - g.RatingMinimo
+	if amplitude <= 0 {
+		return 0
+	}
+	percentual := float64(r.Valor-g.RatingMinimo) / float64(amplitude)
+	switch {
+	case percentual < 0:
+		return 0
+	case percentual > 1:
+		return 1
+	default:
+		return percentual
+	}
+}
@@ -0,0 +1,77 @@
+package vadu_test
+
+import (
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RatingTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestRatingTestSuite(t *testing.T) {
+	suite.Run(t, new(RatingTestSuite))
+}
+
+func (s *RatingTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+// TestNewRating verifica a interpretação das siglas retornadas pela API
+func (s *RatingTestSuite) TestNewRating() {
+	resumo := vadu.ResumoCNPJ{ResumoCNPJBase: vadu.ResumoCNPJBase{
+		Rating:           500,
+		RatingSigla:      "B (500)",
+		RatingDescricao:  "CCB--> 40% do faturamento mensal || ANT--> 20% do faturamento mensal",
+		Rating2:          0,
+		Rating2Sigla:     "Fora de faixa: 0",
+		Rating2Descricao: "",
+	}}
+
+	principal := resumo.RatingPrincipal()
+	s.assert.Equal(500, principal.Valor)
+	s.assert.Equal("B", principal.Letra)
+	s.assert.False(principal.ForaDeFaixa)
+	s.assert.Equal("B (500)", principal.String())
+
+	secundario := resumo.RatingSecundario()
+	s.assert.True(secundario.ForaDeFaixa)
+	s.assert.Empty(secundario.Letra)
+
+	rating, err := vadu.ParseRatingSigla("AA (-150)")
+	s.assert.NoError(err)
+	s.assert.Equal(-150, rating.Valor)
+	s.assert.Equal("AA", rating.Letra)
+
+	_, err = vadu.ParseRatingSigla("sem rating")
+	s.assert.Error(err)
+}
+
+// TestComparacao verifica a ordenação entre ratings
+func (s *RatingTestSuite) TestComparacao() {
+	a := vadu.NewRating(800, "A (800)", "")
+	b := vadu.NewRating(500, "B (500)", "")
+	foraDeFaixa := vadu.NewRating(900, "Fora de faixa: 900", "")
+
+	s.assert.True(a.MelhorQue(b))
+	s.assert.True(b.PiorQue(a))
+	s.assert.True(foraDeFaixa.PiorQue(b))
+	s.assert.Equal(0, a.Compare(vadu.NewRating(800, "A (800)", "")))
+	s.assert.Equal(300, a.Delta(b))
+}
+
+// TestGrupoAnalise verifica o enquadramento do rating na faixa do grupo
+func (s *RatingTestSuite) TestGrupoAnalise() {
+	grupo := vadu.GrupoAnalise{RatingStart: 1000, RatingMinimo: -15650, RatingMaximo: 0}
+	rating := vadu.NewRating(500, "B (500)", "")
+
+	s.assert.True(grupo.DentroDaFaixa(rating))
+	s.assert.False(grupo.DentroDaFaixa(vadu.NewRating(-20000, "", "")))
+	s.assert.Equal(500, grupo.PontosPerdidos(rating))
+	s.assert.InDelta(0.97, grupo.PercentualRating(rating), 0.001)
+	s.assert.Equal(0.0, vadu.GrupoAnalise{}.PercentualRating(rating))
+}
@@ -0,0 +1,194 @@
+package vadu
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Valores padrão de ResubmitOptions.
+const (
+	ReenviosPadrao      = 3
+	AtrasoReenvioPadrao = time.Minute
+)
+
+// ComErroConsulta informa se a análise do documento falhou por problemas nas consultas
+// externas: o resumo tem Erro ou algum log das regras tem ErroConsulta.
+func (r *ResultadoDocumento) ComErroConsulta() bool {
+	if r.Resumo != nil && r.Resumo.Erro {
+		return true
+	}
+	for _, log := range r.Logs {
+		if log.ErroConsulta.Verdadeiro() {
+			return true
+		}
+	}
+	return false
+}
+
+// DocumentosComErro retorna, na ordem do resultado, os documentos cuja análise falhou por
+// problemas nas consultas externas.
+func (r *AnalysisResult) DocumentosComErro() []Documento {
+	var documentos []Documento
+	for _, resultado := range r.Resultados() {
+		if resultado.ComErroConsulta() {
+			documentos = append(documentos, resultado.Documento)
+		}
+	}
+	return documentos
+}
+
+// ResubmitOptions configura o reenvio de documentos com erro.
+type ResubmitOptions struct {
+	MaximoTentativas int           // Reenvios no máximo (padrão ReenviosPadrao)
+	Atraso           time.Duration // Espera antes de cada reenvio (padrão AtrasoReenvioPadrao)
+	Espera           WaitOptions   // Configuração da espera pela conclusão de cada reenvio
+}
+
+// normaliza aplica os valores padrão.
+func (o ResubmitOptions) normaliza() ResubmitOptions {
+	if o.MaximoTentativas <= 0 {
+		o.MaximoTentativas = ReenviosPadrao
+	}
+	if o.Atraso <= 0 {
+		o.Atraso = AtrasoReenvioPadrao
+	}
+	return o
+}
+
+// Reenvio registra uma nova análise criada para documentos com erro da análise original.
+type Reenvio struct {
+	Tentativa         int
+	AnaliseID         int // Zero quando o envio falhou
+	AnaliseOriginalID int
+	Documentos        []Documento
+	Err               error // Erro do ciclo da nova análise, ou nil
+}
+
+// ResubmitResult reúne a análise original, os reenvios e o resultado consolidado.
+type ResubmitResult struct {
+	AnaliseOriginalID int
+	Reenvios          []Reenvio
+	// Resultado combina, para cada documento, o melhor resultado entre a análise original e
+	// os reenvios: o primeiro sem erro de consulta ou, se todos tiverem erro, o mais recente.
+	// Resumo.AnaliseID de cada documento indica a análise de origem.
+	Resultado *AnalysisResult
+	Pendentes []Documento // Documentos que continuaram com erro após todos os reenvios
+}
+
+// AnaliseIDs retorna o ID da análise original seguido dos IDs das análises de reenvio.
+func (r *ResubmitResult) AnaliseIDs() []int {
+	ids := []int{r.AnaliseOriginalID}
+	for _, reenvio := range r.Reenvios {
+		if reenvio.AnaliseID != 0 {
+			ids = append(ids, reenvio.AnaliseID)
+		}
+	}
+	return ids
+}
+
+// ResubmitErrored reenvia ao mesmo grupo de análise os documentos de uma análise concluída que
+// falharam por problemas nas consultas externas, aguardando opts.Atraso antes de cada reenvio,
+// até que nenhum documento tenha erro ou até opts.MaximoTentativas reenvios. Os documentos
+// enviados com DadosIntegracao são reenviados com os mesmos dados. O resultado original não é
+// alterado. Documentos que continuarem com erro são informados em Pendentes; um erro é
+// retornado apenas se o resultado original estiver incompleto ou se o ctx terminar.
+func (vc *VaduClient) ResubmitErrored(ctx context.Context, original *AnalysisResult, opts ResubmitOptions, auth AuthenticationInterface) (*ResubmitResult, error) {
+	if original == nil || original.Resumo == nil {
+		return nil, fmt.Errorf("o resultado da análise original não contém o resumo da análise")
+	}
+	opts = opts.normaliza()
+
+	// O resultado consolidado começa como uma cópia do original
+	consolidado := &AnalysisResult{
+		AnaliseID:  original.AnaliseID,
+		Envio:      original.Envio,
+		Status:     original.Status,
+		Resumo:     original.Resumo,
+		Documentos: make(map[Documento]*ResultadoDocumento, len(original.Documentos)),
+		Ordem:      append([]Documento(nil), original.Ordem...),
+	}
+	for documento, resultado := range original.Documentos {
+		consolidado.Documentos[documento] = resultado
+	}
+	result := &ResubmitResult{AnaliseOriginalID: original.AnaliseID, Resultado: consolidado}
+
+	for tentativa := 1; tentativa <= opts.MaximoTentativas; tentativa++ {
+		documentos := consolidado.DocumentosComErro()
+		if len(documentos) == 0 {
+			break
+		}
+
+		vc.logger.WithFields(logrus.Fields{
+			"analiseID":  original.AnaliseID,
+			"tentativa":  tentativa,
+			"documentos": len(documentos),
+			"atraso":     opts.Atraso,
+		}).Info("Reenviando documentos com erro de consulta")
+
+		espera := time.NewTimer(opts.Atraso)
+		select {
+		case <-ctx.Done():
+			espera.Stop()
+			result.Pendentes = documentos
+			return result, ctx.Err()
+		case <-espera.C:
+		}
+
+		req := AnalysisRequest{
+			CNPJEmpresa:    string(original.Resumo.CNPJEmpresa),
+			IDGrupoAnalise: original.Resumo.IDGrupoAnalise,
+			Espera:         opts.Espera,
+		}
+		for _, documento := range documentos {
+			req.ListaCNPJCPF = append(req.ListaCNPJCPF, string(documento))
+			if dados := consolidado.Documentos[documento].Dados; dados != nil {
+				req.ListaDados = append(req.ListaDados, *dados)
+			}
+		}
+		if len(req.ListaDados) != len(documentos) {
+			// Com dados apenas quando todos os documentos foram enviados com dados
+			req.ListaDados = nil
+		}
+
+		novo, err := vc.Analyze(ctx, req, auth)
+		reenvio := Reenvio{Tentativa: tentativa, AnaliseOriginalID: original.AnaliseID, Documentos: documentos, Err: err}
+		if novo != nil {
+			reenvio.AnaliseID = novo.AnaliseID
+		}
+		result.Reenvios = append(result.Reenvios, reenvio)
+		if ctx.Err() != nil {
+			result.Pendentes = documentos
+			return result, ctx.Err()
+		}
+		if err != nil {
+			vc.logger.WithFields(logrus.Fields{
+				"analiseID":  original.AnaliseID,
+				"tentativa":  tentativa,
+				"reenvioID":  reenvio.AnaliseID,
+				"documentos": len(documentos),
+			}).WithError(err).Warn("Falha no reenvio dos documentos com erro")
+			continue
+		}
+
+		// Substitui os resultados com erro pelos do reenvio
+		for _, documento := range documentos {
+			if resultado, ok := novo.Documentos[documento]; ok && resultado.Resumo != nil {
+				if resultado.Dados == nil {
+					resultado.Dados = consolidado.Documentos[documento].Dados
+				}
+				consolidado.Documentos[documento] = resultado
+			}
+		}
+	}
+
+	result.Pendentes = consolidado.DocumentosComErro()
+	vc.logger.WithFields(logrus.Fields{
+		"analiseID": original.AnaliseID,
+		"reenvios":  len(result.Reenvios),
+		"pendentes": len(result.Pendentes),
+	}).Info("Reenvio de documentos com erro finalizado")
+	return result, nil
+}
@@ -0,0 +1,173 @@
+package vadu_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReenvioTestSuite struct {
+	suite.Suite
+	assert         *assert.Assertions
+	ctx            context.Context
+	logger         *logrus.Logger
+	authentication *mock.MockAuthentication
+}
+
+func TestReenvioTestSuite(t *testing.T) {
+	suite.Run(t, new(ReenvioTestSuite))
+}
+
+func (s *ReenvioTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.ctx = context.Background()
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+	s.authentication = new(mock.MockAuthentication)
+	s.authentication.On("Token", testifymock.Anything).Return("mocked_token", nil)
+}
+
+// resultadoSimulado é o resultado de um documento em uma análise simulada.
+type resultadoSimulado struct {
+	erro         bool // Erro no resumo do documento
+	erroConsulta bool // ErroConsulta em um log das regras
+}
+
+var rotaAnaliseID = regexp.MustCompile(`/id/(\d+)(/cnpjcpf(/detalhado)?)?$`)
+
+// servidorReenvio cria uma análise a cada envio, com IDs a partir de 100, cujos documentos
+// têm os resultados da posição correspondente em analises.
+type servidorReenvio struct {
+	mu       sync.Mutex
+	analises []map[string]resultadoSimulado
+	enviados [][]string
+}
+
+func (m *servidorReenvio) client() *http.Client {
+	return &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			responde := func(body string) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+			}
+
+			if req.Method == http.MethodPost {
+				var corpo struct {
+					ListaCNPJCPF []string `json:"lista_cnpj_cpf"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&corpo); err != nil {
+					return nil, err
+				}
+				m.enviados = append(m.enviados, corpo.ListaCNPJCPF)
+				return responde(fmt.Sprintf(`{"analise_id": %d}`, 99+len(m.enviados)))
+			}
+
+			partes := rotaAnaliseID.FindStringSubmatch(req.URL.Path)
+			id, _ := strconv.Atoi(partes[1])
+			switch {
+			case strings.Contains(req.URL.Path, "/status/"):
+				return responde(`{"percentual_concluido": 100, "concluido": true}`)
+			case partes[2] == "":
+				return responde(fmt.Sprintf(`{"analise_id": %d, "cnpj_empresa": "33011770000199", "id_grupo_analise": 10802, "concluido": true}`, id))
+			}
+
+			var documentos []map[string]interface{}
+			for _, documento := range m.enviados[id-100] {
+				resultado := m.analises[id-100][documento]
+				item := map[string]interface{}{"analise_id": id, "cnpj_cpf": documento, "erro": resultado.erro}
+				if partes[3] != "" {
+					item["logs"] = []map[string]interface{}{{"regra_descricao": "Receita", "erroConsulta": resultado.erroConsulta}}
+				}
+				documentos = append(documentos, item)
+			}
+			body, _ := json.Marshal(documentos)
+			return responde(string(body))
+		},
+	}}
+}
+
+// TestResubmitErrored verifica o reenvio dos documentos com erro e a consolidação do melhor resultado
+func (s *ReenvioTestSuite) TestResubmitErrored() {
+	servidor := &servidorReenvio{analises: []map[string]resultadoSimulado{
+		{"98960887000164": {}, "00360305000104": {erro: true}, "52998224725": {erroConsulta: true}},
+		{"00360305000104": {}, "52998224725": {erroConsulta: true}},
+		{"52998224725": {}},
+	}}
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidor.client(), *session, s.logger)
+
+	original, err := vaduClient.Analyze(s.ctx, vadu.AnalysisRequest{
+		CNPJEmpresa:    "33011770000199",
+		IDGrupoAnalise: 10802,
+		ListaCNPJCPF:   []string{"98960887000164", "00360305000104", "52998224725"},
+	}, s.authentication)
+	s.assert.NoError(err)
+	s.assert.Equal([]vadu.Documento{"00360305000104", "52998224725"}, original.DocumentosComErro())
+
+	result, err := vaduClient.ResubmitErrored(s.ctx, original, vadu.ResubmitOptions{Atraso: time.Millisecond}, s.authentication)
+	s.assert.NoError(err)
+	s.assert.Equal([]int{100, 101, 102}, result.AnaliseIDs())
+	s.assert.Len(result.Reenvios, 2)
+	s.assert.Equal(100, result.Reenvios[1].AnaliseOriginalID)
+	s.assert.Equal([]vadu.Documento{"52998224725"}, result.Reenvios[1].Documentos)
+	s.assert.Equal([][]string{{"00360305000104", "52998224725"}, {"52998224725"}}, servidor.enviados[1:])
+	s.assert.Empty(result.Pendentes)
+
+	// O melhor resultado de cada documento, com a análise de origem
+	origens := map[string]int{"98960887000164": 100, "00360305000104": 101, "52998224725": 102}
+	for documento, analiseID := range origens {
+		resultado, ok := result.Resultado.Documento(documento)
+		s.assert.True(ok)
+		s.assert.False(resultado.ComErroConsulta())
+		s.assert.Equal(analiseID, resultado.Resumo.AnaliseID, documento)
+	}
+
+	// O resultado original não é alterado
+	s.assert.Len(original.DocumentosComErro(), 2)
+}
+
+// TestResubmitErroredPendentes verifica o limite de tentativas e o cancelamento
+func (s *ReenvioTestSuite) TestResubmitErroredPendentes() {
+	comErro := map[string]resultadoSimulado{"98960887000164": {erro: true}}
+	servidor := &servidorReenvio{analises: []map[string]resultadoSimulado{comErro, comErro, comErro, comErro}}
+	session, err := vadu.NewSession(vadu.Config{})
+	s.assert.NoError(err)
+	vaduClient := vadu.NewVaduClient(servidor.client(), *session, s.logger)
+
+	original, err := vaduClient.Analyze(s.ctx, vadu.AnalysisRequest{CNPJEmpresa: "33011770000199", IDGrupoAnalise: 10802, ListaCNPJCPF: []string{"98960887000164"}}, s.authentication)
+	s.assert.NoError(err)
+
+	result, err := vaduClient.ResubmitErrored(s.ctx, original, vadu.ResubmitOptions{MaximoTentativas: 2, Atraso: time.Millisecond}, s.authentication)
+	s.assert.NoError(err)
+	s.assert.Len(result.Reenvios, 2)
+	s.assert.Equal([]vadu.Documento{"98960887000164"}, result.Pendentes)
+	resultado, _ := result.Resultado.Documento("98960887000164")
+	s.assert.Equal(102, resultado.Resumo.AnaliseID)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	result, err = vaduClient.ResubmitErrored(ctx, original, vadu.ResubmitOptions{}, s.authentication)
+	s.assert.ErrorIs(err, context.Canceled)
+	s.assert.Empty(result.Reenvios)
+	s.assert.Len(servidor.enviados, 3)
+
+	_, err = vaduClient.ResubmitErrored(s.ctx, &vadu.AnalysisResult{}, vadu.ResubmitOptions{}, s.authentication)
+	s.assert.Error(err)
+}
@@ -0,0 +1,341 @@
+package vadu
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SchemaDrift descreve as divergências entre a resposta de um endpoint e os modelos do SDK.
+type SchemaDrift struct {
+	Endpoint      string   // Nome do método do cliente que recebeu a resposta
+	Desconhecidos []string // Campos presentes na resposta que não são mapeados pelo SDK
+	Ausentes      []string // Campos mapeados pelo SDK que não vieram na resposta
+}
+
+// Vazio informa se não há divergências.
+func (d SchemaDrift) Vazio() bool {
+	return len(d.Desconhecidos) == 0 && len(d.Ausentes) == 0
+}
+
+// SchemaDriftHandler recebe as divergências de schema detectadas em modo estrito.
+type SchemaDriftHandler func(drift SchemaDrift)
+
+// campoJSON é um campo de struct decodificado pelo encoding/json.
+type campoJSON struct {
+	Nome string
+	Tipo reflect.Type
+}
+
+var cacheCamposJSON sync.Map // reflect.Type -> []campoJSON
+
+// camposJSON lista os campos serializáveis de uma struct, incluindo os das structs embutidas sem tag.
+func camposJSON(t reflect.Type) []campoJSON {
+	if campos, ok := cacheCamposJSON.Load(t); ok {
+		return campos.([]campoJSON)
+	}
+
+	var campos []campoJSON
+	for i := 0; i < t.NumField(); i++ {
+		campo := t.Field(i)
+		tag := campo.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		nome := strings.Split(tag, ",")[0]
+		if campo.Anonymous && nome == "" && campo.Type.Kind() == reflect.Struct {
+			campos = append(campos, camposJSON(campo.Type)...)
+			continue
+		}
+		if !campo.IsExported() {
+			continue
+		}
+		if nome == "" {
+			nome = campo.Name
+		}
+		campos = append(campos, campoJSON{Nome: nome, Tipo: campo.Type})
+	}
+
+	cacheCamposJSON.Store(t, campos)
+	return campos
+}
+
+// modeloResposta informa se o tipo é um modelo de resposta, ou seja, uma struct com o mapa Extra.
+func modeloResposta(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	campo, ok := t.FieldByName("Extra")
+	return ok && campo.Type == reflect.TypeOf(map[string]json.RawMessage(nil))
+}
+
+// decodificaComExtras decodifica data em alvo (ponteiro para um tipo alias do modelo, sem
+// UnmarshalJSON próprio) e retorna os campos do objeto que não são mapeados pelo modelo.
+// Assim como o encoding/json, a correspondência dos nomes ignora maiúsculas e minúsculas.
+func decodificaComExtras(data []byte, alvo interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, alvo); err != nil {
+		return nil, err
+	}
+
+	var objeto map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objeto); err != nil || len(objeto) == 0 {
+		return nil, nil
+	}
+
+	conhecidos := make(map[string]bool)
+	for _, campo := range camposJSON(reflect.TypeOf(alvo).Elem()) {
+		conhecidos[strings.ToLower(campo.Nome)] = true
+	}
+
+	var extras map[string]json.RawMessage
+	for nome, valor := range objeto {
+		if conhecidos[strings.ToLower(nome)] {
+			continue
+		}
+		if extras == nil {
+			extras = make(map[string]json.RawMessage)
+		}
+		extras[nome] = valor
+	}
+	return extras, nil
+}
+
+// decodificaComExtra decodifica data em alvo, ponteiro para o tipo alias do modelo, e grava em
+// extra os campos desconhecidos. Implementa o UnmarshalJSON dos modelos de resposta.
+func decodificaComExtra[A any](data []byte, alvo *A, extra *map[string]json.RawMessage) error {
+	var valor A
+	extras, err := decodificaComExtras(data, &valor)
+	if err != nil {
+		return err
+	}
+	*alvo = valor
+	*extra = extras
+	return nil
+}
+
+// codificaComExtras serializa valor (um tipo alias do modelo, sem MarshalJSON próprio)
+// acrescentando os campos extras que não conflitem com os campos do modelo.
+func codificaComExtras(valor interface{}, extras map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(valor)
+	if err != nil || len(extras) == 0 {
+		return data, err
+	}
+
+	var objeto map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objeto); err != nil {
+		return nil, err
+	}
+	for nome, extra := range extras {
+		if _, existe := objeto[nome]; !existe {
+			objeto[nome] = extra
+		}
+	}
+	return json.Marshal(objeto)
+}
+
+// DetectaSchemaDrift compara o JSON de uma resposta com o modelo em alvo (ex.: *[]ResumoCNPJ)
+// e lista os campos desconhecidos e ausentes, inclusive em modelos aninhados (ex.: logs[].erro).
+// Em listas, um campo é considerado ausente se faltar em qualquer um dos itens.
+func DetectaSchemaDrift(endpoint string, data []byte, alvo interface{}) SchemaDrift {
+	desconhecidos := make(map[string]bool)
+	ausentes := make(map[string]bool)
+	comparaSchema("", json.RawMessage(data), reflect.TypeOf(alvo), desconhecidos, ausentes)
+
+	return SchemaDrift{
+		Endpoint:      endpoint,
+		Desconhecidos: chavesOrdenadas(desconhecidos),
+		Ausentes:      chavesOrdenadas(ausentes),
+	}
+}
+
+// comparaSchema percorre o JSON de acordo com o tipo do modelo, acumulando as divergências.
+func comparaSchema(prefixo string, data json.RawMessage, t reflect.Type, desconhecidos, ausentes map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Slice:
+		var itens []json.RawMessage
+		if err := json.Unmarshal(data, &itens); err != nil {
+			return
+		}
+		for _, item := range itens {
+			comparaSchema(prefixo, item, t.Elem(), desconhecidos, ausentes)
+		}
+
+	case modeloResposta(t):
+		var objeto map[string]json.RawMessage
+		if err := json.Unmarshal(data, &objeto); err != nil || objeto == nil {
+			return
+		}
+		presentes := make(map[string]string, len(objeto))
+		for nome := range objeto {
+			presentes[strings.ToLower(nome)] = nome
+		}
+
+		for _, campo := range camposJSON(t) {
+			nome, ok := presentes[strings.ToLower(campo.Nome)]
+			if !ok {
+				ausentes[prefixo+campo.Nome] = true
+				continue
+			}
+			delete(presentes, strings.ToLower(campo.Nome))
+
+			filho := campo.Tipo
+			caminho := prefixo + campo.Nome
+			for filho.Kind() == reflect.Ptr || filho.Kind() == reflect.Slice {
+				if filho.Kind() == reflect.Slice {
+					caminho += "[]"
+				}
+				filho = filho.Elem()
+			}
+			if modeloResposta(filho) {
+				comparaSchema(caminho+".", objeto[nome], campo.Tipo, desconhecidos, ausentes)
+			}
+		}
+
+		for _, nome := range presentes {
+			desconhecidos[prefixo+nome] = true
+		}
+	}
+}
+
+func chavesOrdenadas(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	chaves := make([]string, 0, len(m))
+	for chave := range m {
+		chaves = append(chaves, chave)
+	}
+	sort.Strings(chaves)
+	return chaves
+}
+
+// decodificaResposta lê e decodifica o corpo da resposta de um endpoint, verificando o schema em modo estrito.
+func (vc *VaduClient) decodificaResposta(endpoint string, body io.Reader, alvo interface{}) error {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, alvo); err != nil {
+		return err
+	}
+	vc.verificaSchema(endpoint, data, alvo)
+	return nil
+}
+
+// verificaSchema reporta as divergências de schema da resposta quando o modo estrito está habilitado.
+// Sem um SchemaDriftHandler configurado, as divergências são registradas no log.
+func (vc *VaduClient) verificaSchema(endpoint string, data []byte, alvo interface{}) {
+	if !vc.session.StrictSchema {
+		return
+	}
+
+	drift := DetectaSchemaDrift(endpoint, data, alvo)
+	if drift.Vazio() {
+		return
+	}
+
+	if vc.session.SchemaDriftHandler != nil {
+		vc.session.SchemaDriftHandler(drift)
+		return
+	}
+
+	vc.logger.WithFields(logrus.Fields{
+		"endpoint":      drift.Endpoint,
+		"desconhecidos": drift.Desconhecidos,
+		"ausentes":      drift.Ausentes,
+	}).Warn("Divergência entre a resposta da API e os modelos do SDK")
+}
+
+// UnmarshalJSON decodifica o grupo preservando os campos desconhecidos em Extra.
+func (g *GrupoAnalise) UnmarshalJSON(data []byte) error {
+	type alias GrupoAnalise
+	return decodificaComExtra(data, (*alias)(g), &g.Extra)
+}
+
+// MarshalJSON serializa o grupo incluindo os campos de Extra.
+func (g GrupoAnalise) MarshalJSON() ([]byte, error) {
+	type alias GrupoAnalise
+	return codificaComExtras(alias(g), g.Extra)
+}
+
+// UnmarshalJSON decodifica a resposta preservando os campos desconhecidos em Extra.
+func (r *EnviaCNPJsResponse) UnmarshalJSON(data []byte) error {
+	type alias EnviaCNPJsResponse
+	return decodificaComExtra(data, (*alias)(r), &r.Extra)
+}
+
+// MarshalJSON serializa a resposta incluindo os campos de Extra.
+func (r EnviaCNPJsResponse) MarshalJSON() ([]byte, error) {
+	type alias EnviaCNPJsResponse
+	return codificaComExtras(alias(r), r.Extra)
+}
+
+// UnmarshalJSON decodifica o log preservando os campos desconhecidos em Extra.
+func (l *LogAnalise) UnmarshalJSON(data []byte) error {
+	type alias LogAnalise
+	return decodificaComExtra(data, (*alias)(l), &l.Extra)
+}
+
+// MarshalJSON serializa o log incluindo os campos de Extra.
+func (l LogAnalise) MarshalJSON() ([]byte, error) {
+	type alias LogAnalise
+	return codificaComExtras(alias(l), l.Extra)
+}
+
+// UnmarshalJSON decodifica o status preservando os campos desconhecidos em Extra.
+func (s *StatusAnalise) UnmarshalJSON(data []byte) error {
+	type alias StatusAnalise
+	return decodificaComExtra(data, (*alias)(s), &s.Extra)
+}
+
+// MarshalJSON serializa o status incluindo os campos de Extra.
+func (s StatusAnalise) MarshalJSON() ([]byte, error) {
+	type alias StatusAnalise
+	return codificaComExtras(alias(s), s.Extra)
+}
+
+// UnmarshalJSON decodifica o resumo preservando os campos desconhecidos em Extra.
+func (r *ResumoAnalise) UnmarshalJSON(data []byte) error {
+	type alias ResumoAnalise
+	return decodificaComExtra(data, (*alias)(r), &r.Extra)
+}
+
+// MarshalJSON serializa o resumo incluindo os campos de Extra.
+func (r ResumoAnalise) MarshalJSON() ([]byte, error) {
+	type alias ResumoAnalise
+	return codificaComExtras(alias(r), r.Extra)
+}
+
+// UnmarshalJSON decodifica o resumo preservando os campos desconhecidos em Extra.
+func (r *ResumoCNPJ) UnmarshalJSON(data []byte) error {
+	type alias ResumoCNPJ
+	return decodificaComExtra(data, (*alias)(r), &r.Extra)
+}
+
+// MarshalJSON serializa o resumo incluindo os campos de Extra.
+func (r ResumoCNPJ) MarshalJSON() ([]byte, error) {
+	type alias ResumoCNPJ
+	return codificaComExtras(alias(r), r.Extra)
+}
+
+// UnmarshalJSON decodifica o resumo detalhado preservando os campos desconhecidos em Extra.
+func (r *ResumoCNPJDatalhado) UnmarshalJSON(data []byte) error {
+	type alias ResumoCNPJDatalhado
+	return decodificaComExtra(data, (*alias)(r), &r.Extra)
+}
+
+// MarshalJSON serializa o resumo detalhado incluindo os campos de Extra.
+func (r ResumoCNPJDatalhado) MarshalJSON() ([]byte, error) {
+	type alias ResumoCNPJDatalhado
+	return codificaComExtras(alias(r), r.Extra)
+}
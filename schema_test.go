@@ -0,0 +1,122 @@
+package vadu_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/contbank/vadu-sdk/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+	logger *logrus.Logger
+}
+
+func TestSchemaTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
+
+func (s *SchemaTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+	s.logger = logrus.New()
+	s.logger.SetOutput(ioutil.Discard)
+}
+
+// TestCamposExtras verifica a captura e a serialização dos campos desconhecidos
+func (s *SchemaTestSuite) TestCamposExtras() {
+	var resumo vadu.ResumoCNPJDatalhado
+	s.assert.NoError(json.Unmarshal([]byte(`{
+		"analise_id": 4768906,
+		"cnpj_cpf": "98960887000164",
+		"score_novo": 812,
+		"logs": [{"regra_descricao": "Cadastro 14", "peso_regra": 3}]
+	}`), &resumo))
+
+	s.assert.Equal(4768906, resumo.AnaliseID)
+	s.assert.Equal(vadu.Documento("98960887000164"), resumo.CNPJCPF)
+	s.assert.Equal(json.RawMessage("812"), resumo.Extra["score_novo"])
+	s.assert.Len(resumo.Extra, 1)
+	s.assert.Equal(json.RawMessage("3"), resumo.Logs[0].Extra["peso_regra"])
+
+	data, err := json.Marshal(resumo)
+	s.assert.NoError(err)
+	s.assert.Contains(string(data), `"score_novo":812`)
+	s.assert.Contains(string(data), `"peso_regra":3`)
+
+	var status vadu.StatusAnalise
+	s.assert.NoError(json.Unmarshal([]byte(`{"concluido": true, "Percentual_Concluido": 100}`), &status))
+	s.assert.True(status.Concluido)
+	s.assert.Equal(100, status.PercentualConcluido)
+	s.assert.Nil(status.Extra)
+}
+
+// TestDetectaSchemaDrift verifica a detecção de campos desconhecidos e ausentes
+func (s *SchemaTestSuite) TestDetectaSchemaDrift() {
+	data := []byte(`[{
+		"quantidade_cnpj_cpf": 1,
+		"quantidade_consultas_receita": 1,
+		"percentual_consultas_receita": 100,
+		"quantidade_cnpj_cpf_concluidos": 1,
+		"percentual_concluido": 100,
+		"concluido": true,
+		"etapa": "finalizada"
+	}]`)
+
+	drift := vadu.DetectaSchemaDrift("PegaStatusAnalise", data, &[]vadu.StatusAnalise{})
+	s.assert.Equal("PegaStatusAnalise", drift.Endpoint)
+	s.assert.Equal([]string{"etapa"}, drift.Desconhecidos)
+	s.assert.Equal([]string{"finalizando_arquivo"}, drift.Ausentes)
+
+	detalhado := []byte(`[{"analise_id": 1, "logs": [{"regra_descricao": "Cadastro", "peso": 1}]}]`)
+	drift = vadu.DetectaSchemaDrift("ListaResumoCNPJsDetalhado", detalhado, &[]vadu.ResumoCNPJDatalhado{})
+	s.assert.Contains(drift.Desconhecidos, "logs[].peso")
+	s.assert.Contains(drift.Ausentes, "logs[].erroConsulta")
+	s.assert.Contains(drift.Ausentes, "cnpj_cpf")
+	s.assert.NotContains(drift.Ausentes, "analise_id")
+}
+
+// TestModoEstrito verifica o envio das divergências ao handler configurado
+func (s *SchemaTestSuite) TestModoEstrito() {
+	httpClient := &http.Client{Transport: &mock.MockAuthHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(`[{"id_grupo_analise": 1, "nome_grupo_analise": "Grupo", "rating_start": 1000, "rating_minimo": 0, "rating_maximo": 1000, "quantidade_analises": 1, "quantidade_regras": 2, "quantidade_validacoes": 3, "ativo": true}]`))),
+			}, nil
+		},
+	}}
+
+	var drifts []vadu.SchemaDrift
+	session, err := vadu.NewSession(vadu.Config{
+		StrictSchema:       vadu.Bool(true),
+		SchemaDriftHandler: func(drift vadu.SchemaDrift) { drifts = append(drifts, drift) },
+	})
+	s.assert.NoError(err)
+
+	ctx := context.Background()
+	authentication := new(mock.MockAuthentication)
+	authentication.On("Token", ctx).Return("mocked_token", nil)
+
+	grupos, err := vadu.NewVaduClient(httpClient, *session, s.logger).ListaGruposAnalise(ctx, authentication)
+	s.assert.NoError(err)
+	s.assert.Equal(json.RawMessage("true"), grupos[0].Extra["ativo"])
+	s.assert.Len(drifts, 1)
+	s.assert.Equal("ListaGruposAnalise", drifts[0].Endpoint)
+	s.assert.Equal([]string{"ativo"}, drifts[0].Desconhecidos)
+	s.assert.Empty(drifts[0].Ausentes)
+
+	session.StrictSchema = false
+	drifts = nil
+	_, err = vadu.NewVaduClient(httpClient, *session, s.logger).ListaGruposAnalise(ctx, authentication)
+	s.assert.NoError(err)
+	s.assert.Empty(drifts)
+}
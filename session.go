@@ -10,24 +10,38 @@ import (
 
 // Config contém as configurações necessárias para inicializar uma sessão.
 type Config struct {
-	APIEndpoint   *string        // URL do API
-	LoginEndpoint *string        // URL de autenticação
-	ClientToken   *string        // Token do cliente
-	Cookie        *string        // Cookie de autenticação
-	Cache         *cache.Cache   // Cache para armazenar o token
-	HTTPClient    *http.Client   // Cliente HTTP personalizado
-	TokenTTL      *time.Duration // Tempo de expiração do token (opcional)
+	APIEndpoint            *string            // URL do API
+	LoginEndpoint          *string            // URL de autenticação
+	ClientToken            *string            // Token do cliente
+	Cookie                 *string            // Cookie de autenticação
+	Cache                  *cache.Cache       // Cache para armazenar o token
+	HTTPClient             *http.Client       // Cliente HTTP personalizado
+	TokenTTL               *time.Duration     // Tempo de expiração do token (opcional)
+	AuditTrail             *AuditTrail        // Trilha de auditoria das submissões e resultados (opcional)
+	AceitaCNPJAlfanumerico *bool              // Aceita CNPJs alfanuméricos (opcional, padrão VADU_ACEITA_CNPJ_ALFANUMERICO ou false)
+	BatchPolicy            *BatchPolicy       // Tratamento de lotes com problemas de validação (opcional, padrão BatchPolicySubmitAnyway)
+	StrictSchema           *bool              // Verifica divergências de schema nas respostas (opcional, padrão VADU_STRICT_SCHEMA ou false)
+	SchemaDriftHandler     SchemaDriftHandler // Recebe as divergências de schema em modo estrito (opcional, padrão log)
+	ConsistencyPolicy      *ConsistencyPolicy // Rejeição de dados de integração inconsistentes (opcional, padrão ConsistencyPolicyIgnore)
+	RateLimiter            RateLimiter        // Limita a taxa de requisições à API (opcional, padrão sem limite)
 }
 
 // Session representa a sessão autenticada com as configurações da API do Vadu.
 type Session struct {
-	APIEndpoint   string        // URL do API
-	LoginEndpoint string        // URL para autenticação
-	ClientToken   string        // Token do cliente
-	Cookie        string        // Cookie de autenticação
-	Cache         *cache.Cache  // Cache para tokens
-	HTTPClient    *http.Client  // Cliente HTTP
-	TokenTTL      time.Duration // Tempo de expiração do token
+	APIEndpoint            string             // URL do API
+	LoginEndpoint          string             // URL para autenticação
+	ClientToken            string             // Token do cliente
+	Cookie                 string             // Cookie de autenticação
+	Cache                  *cache.Cache       // Cache para tokens
+	HTTPClient             *http.Client       // Cliente HTTP
+	TokenTTL               time.Duration      // Tempo de expiração do token
+	AuditTrail             *AuditTrail        // Trilha de auditoria (nil desabilita a auditoria)
+	AceitaCNPJAlfanumerico bool               // Permite o envio de CNPJs alfanuméricos
+	BatchPolicy            BatchPolicy        // Tratamento de lotes com problemas de validação
+	StrictSchema           bool               // Verifica divergências de schema nas respostas
+	SchemaDriftHandler     SchemaDriftHandler // Recebe as divergências de schema (nil registra no log)
+	ConsistencyPolicy      ConsistencyPolicy  // Rejeição de dados de integração inconsistentes
+	RateLimiter            RateLimiter        // Limita a taxa de requisições à API (nil não limita)
 }
 
 // NewSession cria uma nova instância de `Session` com base nas configurações fornecidas.
@@ -61,14 +75,39 @@ func NewSession(config Config) (*Session, error) {
 		config.TokenTTL = &defaultTTL
 	}
 
+	if config.AceitaCNPJAlfanumerico == nil {
+		config.AceitaCNPJAlfanumerico = Bool(os.Getenv("VADU_ACEITA_CNPJ_ALFANUMERICO") == "true")
+	}
+
+	if config.BatchPolicy == nil {
+		defaultPolicy := BatchPolicySubmitAnyway
+		config.BatchPolicy = &defaultPolicy
+	}
+
+	if config.StrictSchema == nil {
+		config.StrictSchema = Bool(os.Getenv("VADU_STRICT_SCHEMA") == "true")
+	}
+
+	if config.ConsistencyPolicy == nil {
+		defaultConsistency := ConsistencyPolicyIgnore
+		config.ConsistencyPolicy = &defaultConsistency
+	}
+
 	// Inicializa a sessão
 	return &Session{
-		APIEndpoint:   *config.APIEndpoint,
-		LoginEndpoint: *config.LoginEndpoint,
-		ClientToken:   *config.ClientToken,
-		Cookie:        *config.Cookie,
-		Cache:         config.Cache,
-		HTTPClient:    config.HTTPClient,
-		TokenTTL:      *config.TokenTTL,
+		APIEndpoint:            *config.APIEndpoint,
+		LoginEndpoint:          *config.LoginEndpoint,
+		ClientToken:            *config.ClientToken,
+		Cookie:                 *config.Cookie,
+		Cache:                  config.Cache,
+		HTTPClient:             config.HTTPClient,
+		TokenTTL:               *config.TokenTTL,
+		AuditTrail:             config.AuditTrail,
+		AceitaCNPJAlfanumerico: *config.AceitaCNPJAlfanumerico,
+		BatchPolicy:            *config.BatchPolicy,
+		StrictSchema:           *config.StrictSchema,
+		SchemaDriftHandler:     config.SchemaDriftHandler,
+		ConsistencyPolicy:      *config.ConsistencyPolicy,
+		RateLimiter:            config.RateLimiter,
 	}, nil
 }
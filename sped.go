@@ -0,0 +1,429 @@
+package vadu
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrSPEDInvalido indica um arquivo que não segue o leiaute da ECD ou da ECF.
+	ErrSPEDInvalido = errors.New("arquivo SPED inválido")
+	// ErrSPEDSemDemonstracoes indica um arquivo sem os registros de balanço e DRE (J100/J150 ou L100/L300).
+	ErrSPEDSemDemonstracoes = errors.New("arquivo SPED sem balanço ou DRE")
+)
+
+// Origens de um arquivo SPED.
+const (
+	OrigemECD = "ECD"
+	OrigemECF = "ECF"
+)
+
+// ContaSPED identifica a conta de Balanco ou DRE que recebe o valor de uma linha do SPED.
+type ContaSPED string
+
+// Contas de destino disponíveis para o mapeamento do plano de contas.
+const (
+	ContaIgnorada                  ContaSPED = "-" // Descarta a linha, inclusive de mapeamentos por descrição
+	ContaAtivoTotal                ContaSPED = "ativoTotal"
+	ContaAtivoCirculante           ContaSPED = "ativoCirculante"
+	ContaAtivoNaoCirculante        ContaSPED = "ativoNaoCirculante"
+	ContaAtivoRealizavelLongoPrazo ContaSPED = "ativoRealizavelLongoPrazo"
+	ContaDisponivelCaixa           ContaSPED = "disponivelCaixa"
+	ContaContasReceber             ContaSPED = "contasReceber"
+	ContaEstoques                  ContaSPED = "estoques"
+	ContaPassivoCirculante         ContaSPED = "passivoCirculante"
+	ContaPassivoNaoCirculante      ContaSPED = "passivoNaoCirculante"
+	ContaPassivoTotal              ContaSPED = "passivoTotal"
+	ContaPatrimonioLiquido         ContaSPED = "patrimonioLiquido"
+	ContaFornecedores              ContaSPED = "fornecedores"
+	ContaEmprestimos               ContaSPED = "emprestimos"
+	ContaReceitaBruta              ContaSPED = "receitaBruta"
+	ContaDeducaoReceitaBruta       ContaSPED = "deducaoReceitaBruta"
+	ContaReceitaLiquida            ContaSPED = "receitaLiquida"
+	ContaDespesas                  ContaSPED = "despesas"
+	ContaDepreciacaoBens           ContaSPED = "depreciacaoBens"
+	ContaLucroOperacional          ContaSPED = "lucroOperacional"
+	ContaLucroLiquido              ContaSPED = "lucroLiquido"
+)
+
+// naturezaDevedora lista as contas cujo saldo positivo é devedor. As demais são credoras.
+var naturezaDevedora = map[ContaSPED]bool{
+	ContaAtivoTotal:                true,
+	ContaAtivoCirculante:           true,
+	ContaAtivoNaoCirculante:        true,
+	ContaAtivoRealizavelLongoPrazo: true,
+	ContaDisponivelCaixa:           true,
+	ContaContasReceber:             true,
+	ContaEstoques:                  true,
+	ContaDeducaoReceitaBruta:       true,
+	ContaDespesas:                  true,
+	ContaDepreciacaoBens:           true,
+}
+
+// MapeamentoPlanoContas associa as linhas do SPED às contas de Balanco e DRE. O código da
+// linha (COD_AGL na ECD, CODIGO na ECF) tem precedência; na ausência dele é usada a descrição,
+// comparada sem acentos, sem diferenciar maiúsculas e minúsculas. Linhas associadas à mesma
+// conta são somadas.
+type MapeamentoPlanoContas struct {
+	Codigos    map[string]ContaSPED
+	Descricoes map[string]ContaSPED
+}
+
+// MapeamentoPadrao retorna o mapeamento por descrição das linhas usuais do balanço e da DRE.
+// Como os códigos de aglutinação variam entre empresas, nenhum código é mapeado.
+func MapeamentoPadrao() MapeamentoPlanoContas {
+	descricoes := map[ContaSPED][]string{
+		ContaAtivoTotal:                {"ATIVO", "ATIVO TOTAL", "TOTAL DO ATIVO"},
+		ContaAtivoCirculante:           {"ATIVO CIRCULANTE"},
+		ContaAtivoNaoCirculante:        {"ATIVO NAO CIRCULANTE"},
+		ContaAtivoRealizavelLongoPrazo: {"REALIZAVEL A LONGO PRAZO", "ATIVO REALIZAVEL A LONGO PRAZO"},
+		ContaDisponivelCaixa:           {"DISPONIVEL", "DISPONIBILIDADES", "CAIXA E EQUIVALENTES DE CAIXA"},
+		ContaContasReceber:             {"CLIENTES", "CONTAS A RECEBER", "DUPLICATAS A RECEBER"},
+		ContaEstoques:                  {"ESTOQUE", "ESTOQUES"},
+		ContaPassivoCirculante:         {"PASSIVO CIRCULANTE"},
+		ContaPassivoNaoCirculante:      {"PASSIVO NAO CIRCULANTE", "EXIGIVEL A LONGO PRAZO"},
+		ContaPassivoTotal:              {"PASSIVO", "PASSIVO TOTAL", "TOTAL DO PASSIVO"},
+		ContaPatrimonioLiquido:         {"PATRIMONIO LIQUIDO"},
+		ContaFornecedores:              {"FORNECEDORES"},
+		ContaEmprestimos:               {"EMPRESTIMOS E FINANCIAMENTOS"},
+		ContaReceitaBruta:              {"RECEITA BRUTA", "RECEITA OPERACIONAL BRUTA", "RECEITA BRUTA DE VENDAS E SERVICOS"},
+		ContaDeducaoReceitaBruta:       {"DEDUCOES DA RECEITA BRUTA", "DEDUCOES DA RECEITA", "DEDUCOES DA RECEITA OPERACIONAL BRUTA"},
+		ContaReceitaLiquida:            {"RECEITA LIQUIDA", "RECEITA OPERACIONAL LIQUIDA"},
+		ContaDespesas:                  {"DESPESAS OPERACIONAIS"},
+		ContaDepreciacaoBens:           {"DEPRECIACAO E AMORTIZACAO"},
+		ContaLucroOperacional:          {"RESULTADO OPERACIONAL", "LUCRO OPERACIONAL"},
+		ContaLucroLiquido: {
+			"LUCRO LIQUIDO", "LUCRO LIQUIDO DO EXERCICIO", "LUCRO LIQUIDO DO PERIODO",
+			"RESULTADO LIQUIDO", "RESULTADO LIQUIDO DO EXERCICIO", "RESULTADO LIQUIDO DO PERIODO",
+			"LUCRO/PREJUIZO DO EXERCICIO", "LUCRO (PREJUIZO) DO EXERCICIO",
+		},
+	}
+
+	mapeamento := MapeamentoPlanoContas{Descricoes: make(map[string]ContaSPED)}
+	for conta, textos := range descricoes {
+		for _, texto := range textos {
+			mapeamento.Descricoes[texto] = conta
+		}
+	}
+	return mapeamento
+}
+
+// resolvedor retorna uma função que busca a conta de destino de uma linha, indicando se a
+// linha está mapeada (inclusive como ContaIgnorada).
+func (m MapeamentoPlanoContas) resolvedor() func(codigo, descricao string) (ContaSPED, bool) {
+	descricoes := make(map[string]ContaSPED, len(m.Descricoes))
+	for texto, conta := range m.Descricoes {
+		descricoes[normalizaDescricaoSPED(texto)] = conta
+	}
+	return func(codigo, descricao string) (ContaSPED, bool) {
+		if conta, ok := m.Codigos[codigo]; ok {
+			return conta, true
+		}
+		conta, ok := descricoes[normalizaDescricaoSPED(descricao)]
+		return conta, ok
+	}
+}
+
+var semAcentos = strings.NewReplacer(
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C",
+)
+
+// sinalDRE reconhece os marcadores de operação usados nas descrições da DRE, como "(-)" e "(=)".
+var sinalDRE = regexp.MustCompile(`^\s*\(\s*[-+=]\s*\)\s*`)
+
+// normalizaDescricaoSPED remove acentos, marcadores de operação, pontuação final e espaços
+// repetidos e converte para maiúsculas.
+func normalizaDescricaoSPED(descricao string) string {
+	descricao = sinalDRE.ReplaceAllString(descricao, "")
+	descricao = semAcentos.Replace(strings.ToUpper(descricao))
+	descricao = strings.TrimRight(strings.Join(strings.Fields(descricao), " "), ".:;-")
+	return strings.TrimSpace(descricao)
+}
+
+// LinhaSPED é uma linha de balanço ou DRE lida do arquivo.
+type LinhaSPED struct {
+	Registro  string  // J100, J150, L100 ou L300
+	Codigo    string  // COD_AGL (ECD) ou CODIGO (ECF)
+	Descricao string  // Descrição da linha
+	Valor     Decimal // Valor final do período, sem sinal
+	Natureza  string  // D (devedora) ou C (credora)
+}
+
+// valorConta retorna o valor da linha com o sinal da natureza da conta de destino.
+func (l LinhaSPED) valorConta(conta ContaSPED) Decimal {
+	if (l.Natureza == "D") == naturezaDevedora[conta] {
+		return l.Valor
+	}
+	return l.Valor.Neg()
+}
+
+// DemonstracoesSPED reúne o balanço e a DRE extraídos de um arquivo ECD ou ECF.
+type DemonstracoesSPED struct {
+	Origem      string      // OrigemECD ou OrigemECF
+	CNPJ        Documento   // CNPJ do registro 0000
+	DataInicial time.Time   // Início do período das demonstrações
+	DataFinal   time.Time   // Fim do período das demonstrações
+	Balanco     Balanco     // Contas do balanço
+	DRE         DRE         // Contas da DRE, com Meses calculado a partir do período
+	NaoMapeadas []LinhaSPED // Linhas sem conta de destino no mapeamento
+}
+
+// DadosIntegracao monta os dados de integração com os indicadores calculados pelo DadosIntegracaoBuilder.
+// Indicadores sem contas suficientes resultam em um *IndicadoresError, como no builder.
+func (d *DemonstracoesSPED) DadosIntegracao() (DadosIntegracao, error) {
+	return NewDadosIntegracaoBuilder(string(d.CNPJ)).
+		ComBalanco(d.Balanco).
+		ComDRE(d.DRE).
+		Build()
+}
+
+// periodoSPED acumula as linhas de um período (J005 na ECD, L030 na ECF).
+type periodoSPED struct {
+	inicio, fim time.Time
+	contas      map[ContaSPED]Decimal
+	naoMapeadas []LinhaSPED
+	linhas      int
+}
+
+// ParseSPED lê um arquivo da ECD (registros J100 e J150) ou da ECF (registros L100 e L300) e
+// associa as linhas às contas de Balanco e DRE conforme o mapeamento. Quando o arquivo contém
+// mais de um período, são usadas as demonstrações do período mais recente. Arquivos em
+// ISO-8859-1 são aceitos.
+func ParseSPED(r io.Reader, mapeamento MapeamentoPlanoContas) (*DemonstracoesSPED, error) {
+	demonstracoes := &DemonstracoesSPED{}
+	contaDestino := mapeamento.resolvedor()
+	var periodos []*periodoSPED
+	periodoAtual := func() *periodoSPED {
+		if len(periodos) == 0 {
+			periodos = append(periodos, &periodoSPED{
+				inicio: demonstracoes.DataInicial,
+				fim:    demonstracoes.DataFinal,
+				contas: make(map[ContaSPED]Decimal),
+			})
+		}
+		return periodos[len(periodos)-1]
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	numero := 0
+	for scanner.Scan() {
+		numero++
+		texto := strings.TrimSpace(scanner.Text())
+		if !utf8.ValidString(texto) {
+			texto = latin1ParaUTF8(texto)
+		}
+		if !strings.HasPrefix(texto, "|") {
+			continue
+		}
+		campos := strings.Split(strings.Trim(texto, "|"), "|")
+		campo := func(i int) string {
+			if i < len(campos) {
+				return strings.TrimSpace(campos[i])
+			}
+			return ""
+		}
+
+		var (
+			linha LinhaSPED
+			err   error
+		)
+		switch campos[0] {
+		case "0000":
+			if err := demonstracoes.leRegistro0000(campo); err != nil {
+				return nil, fmt.Errorf("%w: linha %d: %v", ErrSPEDInvalido, numero, err)
+			}
+			continue
+		case "J005", "L030":
+			inicio, errInicio := parseDataSPED(campo(1))
+			fim, errFim := parseDataSPED(campo(2))
+			if errInicio != nil || errFim != nil {
+				return nil, fmt.Errorf("%w: linha %d: período inválido", ErrSPEDInvalido, numero)
+			}
+			periodos = append(periodos, &periodoSPED{inicio: inicio, fim: fim, contas: make(map[ContaSPED]Decimal)})
+			continue
+		case "J100":
+			if len(campos) >= 11 {
+				// Leiaute 8 em diante: COD_AGL, IND_COD_AGL, NIVEL_AGL, COD_AGL_SUP, IND_GRP_BAL, DESCR_COD_AGL, VL_CTA_INI, IND_DC_CTA_INI, VL_CTA_FIN, IND_DC_CTA_FIN
+				linha, err = novaLinhaSPED("J100", campo(1), campo(6), campo(9), campo(10))
+			} else {
+				// Leiautes anteriores: COD_AGL, NIVEL_AGL, IND_GRP_BAL, DESCR_COD_AGL, VL_CTA, IND_DC_BAL
+				linha, err = novaLinhaSPED("J100", campo(1), campo(4), campo(5), campo(6))
+			}
+		case "J150":
+			if len(campos) >= 12 {
+				// Leiaute 8 em diante: NU_ORDEM, COD_AGL, IND_COD_AGL, NIVEL_AGL, COD_AGL_SUP, DESCR_COD_AGL, VL_CTA_INI, IND_DC_CTA_INI, VL_CTA_FIN, IND_DC_CTA_FIN
+				linha, err = novaLinhaSPED("J150", campo(2), campo(6), campo(9), campo(10))
+			} else {
+				// Leiautes anteriores: COD_AGL, NIVEL_AGL, DESCR_COD_AGL, VL_CTA, IND_VL (R/P credores, D/N devedores)
+				indicador := "D"
+				if campo(5) == "R" || campo(5) == "P" {
+					indicador = "C"
+				}
+				linha, err = novaLinhaSPED("J150", campo(1), campo(3), campo(4), indicador)
+			}
+		case "L100":
+			// CODIGO, DESCRICAO, TIPO, NIVEL, COD_NAT, COD_CTA_SUP, VAL_CTA_REF_INI, IND_VAL_CTA_REF_INI, VAL_CTA_REF_DEB, VAL_CTA_REF_CRED, VAL_CTA_REF_FIN, IND_VAL_CTA_REF_FIN
+			linha, err = novaLinhaSPED("L100", campo(1), campo(2), campo(11), campo(12))
+		case "L300":
+			// CODIGO, DESCRICAO, TIPO, NIVEL, COD_NAT, COD_CTA_SUP, VALOR, IND_VALOR
+			linha, err = novaLinhaSPED("L300", campo(1), campo(2), campo(7), campo(8))
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: linha %d: %v", ErrSPEDInvalido, numero, err)
+		}
+
+		periodo := periodoAtual()
+		periodo.linhas++
+		conta, mapeada := contaDestino(linha.Codigo, linha.Descricao)
+		switch {
+		case !mapeada:
+			periodo.naoMapeadas = append(periodo.naoMapeadas, linha)
+		case conta != ContaIgnorada:
+			periodo.contas[conta] = periodo.contas[conta].Add(linha.valorConta(conta))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo SPED: %w", err)
+	}
+	if demonstracoes.Origem == "" {
+		return nil, fmt.Errorf("%w: registro 0000 não encontrado", ErrSPEDInvalido)
+	}
+
+	// Usar o período mais recente que contenha demonstrações
+	var escolhido *periodoSPED
+	for _, periodo := range periodos {
+		if periodo.linhas > 0 && (escolhido == nil || !periodo.fim.Before(escolhido.fim)) {
+			escolhido = periodo
+		}
+	}
+	if escolhido == nil {
+		return nil, ErrSPEDSemDemonstracoes
+	}
+
+	demonstracoes.DataInicial, demonstracoes.DataFinal = escolhido.inicio, escolhido.fim
+	demonstracoes.NaoMapeadas = escolhido.naoMapeadas
+	demonstracoes.Balanco, demonstracoes.DRE = escolhido.demonstracoes()
+	return demonstracoes, nil
+}
+
+// leRegistro0000 identifica o tipo de arquivo, o CNPJ e o período.
+func (d *DemonstracoesSPED) leRegistro0000(campo func(int) string) error {
+	var cnpj, inicio, fim string
+	switch campo(1) {
+	case "LECD":
+		// LECD, DT_INI, DT_FIN, NOME, CNPJ
+		d.Origem, inicio, fim, cnpj = OrigemECD, campo(2), campo(3), campo(5)
+	case "LECF":
+		// LECF, COD_VER, CNPJ, NOME, IND_SIT_INI_PER, SIT_ESPECIAL, PAT_REMAN_CIS, DT_SIT_ESP, DT_INI, DT_FIN
+		d.Origem, inicio, fim, cnpj = OrigemECF, campo(9), campo(10), campo(3)
+	default:
+		return fmt.Errorf("tipo de escrituração desconhecido: %q", campo(1))
+	}
+
+	var err error
+	if d.DataInicial, err = parseDataSPED(inicio); err != nil {
+		return err
+	}
+	if d.DataFinal, err = parseDataSPED(fim); err != nil {
+		return err
+	}
+	d.CNPJ = Documento(NormalizaDocumento(cnpj))
+	return nil
+}
+
+// demonstracoes converte as contas acumuladas no período em Balanco e DRE.
+func (p *periodoSPED) demonstracoes() (Balanco, DRE) {
+	valor := func(conta ContaSPED) *Decimal {
+		if v, ok := p.contas[conta]; ok {
+			return DecimalPtr(v)
+		}
+		return nil
+	}
+
+	balanco := Balanco{
+		AtivoTotal:                valor(ContaAtivoTotal),
+		AtivoCirculante:           valor(ContaAtivoCirculante),
+		AtivoNaoCirculante:        valor(ContaAtivoNaoCirculante),
+		AtivoRealizavelLongoPrazo: valor(ContaAtivoRealizavelLongoPrazo),
+		DisponivelCaixa:           valor(ContaDisponivelCaixa),
+		ContasReceber:             valor(ContaContasReceber),
+		Estoques:                  valor(ContaEstoques),
+		PassivoCirculante:         valor(ContaPassivoCirculante),
+		PassivoNaoCirculante:      valor(ContaPassivoNaoCirculante),
+		PassivoTotal:              valor(ContaPassivoTotal),
+		PatrimonioLiquido:         valor(ContaPatrimonioLiquido),
+		Fornecedores:              valor(ContaFornecedores),
+		Emprestimos:               valor(ContaEmprestimos),
+	}
+	dre := DRE{
+		Meses:               mesesPeriodo(p.inicio, p.fim),
+		ReceitaBruta:        valor(ContaReceitaBruta),
+		DeducaoReceitaBruta: valor(ContaDeducaoReceitaBruta),
+		ReceitaLiquida:      valor(ContaReceitaLiquida),
+		Despesas:            valor(ContaDespesas),
+		DepreciacaoBens:     valor(ContaDepreciacaoBens),
+		LucroOperacional:    valor(ContaLucroOperacional),
+		LucroLiquido:        valor(ContaLucroLiquido),
+	}
+	return balanco, dre
+}
+
+// novaLinhaSPED interpreta o valor no formato do SPED (vírgula decimal) e o indicador D/C.
+func novaLinhaSPED(registro, codigo, descricao, valor, indicador string) (LinhaSPED, error) {
+	var numero Decimal
+	if valor != "" {
+		var err error
+		numero, err = ParseDecimal(strings.Replace(strings.ReplaceAll(valor, ".", ""), ",", ".", 1))
+		if err != nil {
+			return LinhaSPED{}, fmt.Errorf("valor inválido no registro %s: %q", registro, valor)
+		}
+	}
+	indicador = strings.ToUpper(indicador)
+	if indicador != "D" && indicador != "C" {
+		if !numero.IsZero() {
+			return LinhaSPED{}, fmt.Errorf("indicador de débito/crédito inválido no registro %s: %q", registro, indicador)
+		}
+		indicador = "D"
+	}
+	return LinhaSPED{Registro: registro, Codigo: codigo, Descricao: descricao, Valor: numero, Natureza: indicador}, nil
+}
+
+// parseDataSPED interpreta datas no formato ddmmaaaa.
+func parseDataSPED(valor string) (time.Time, error) {
+	data, err := time.ParseInLocation("02012006", valor, LocalizacaoSaoPaulo())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data inválida: %q", valor)
+	}
+	return data, nil
+}
+
+// mesesPeriodo retorna a quantidade de meses (inclusive) entre as datas, ou 0 se desconhecida.
+func mesesPeriodo(inicio, fim time.Time) int {
+	if inicio.IsZero() || fim.IsZero() || fim.Before(inicio) {
+		return 0
+	}
+	return (fim.Year()-inicio.Year())*12 + int(fim.Month()-inicio.Month()) + 1
+}
+
+// latin1ParaUTF8 converte um texto em ISO-8859-1 para UTF-8.
+func latin1ParaUTF8(texto string) string {
+	runas := make([]rune, len(texto))
+	for i := 0; i < len(texto); i++ {
+		runas[i] = rune(texto[i])
+	}
+	return string(runas)
+}
@@ -0,0 +1,135 @@
+package vadu_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/contbank/vadu-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SPEDTestSuite struct {
+	suite.Suite
+	assert *assert.Assertions
+}
+
+func TestSPEDTestSuite(t *testing.T) {
+	suite.Run(t, new(SPEDTestSuite))
+}
+
+func (s *SPEDTestSuite) SetupTest() {
+	s.assert = assert.New(s.T())
+}
+
+const arquivoECD = `|0000|LECD|01012023|31122023|WEBSOLUTIONS LTDA|98960887000164|SP||3550308||||0|1|0||0|0||N|N|0|0|1|
+|I001|0|
+|J001|0|
+|J005|01012023|31122023|1||
+|J100|1|T|1||A|ATIVO|800,00|D|1000,00|D||
+|J100|1.1|T|2|1|A|Ativo Circulante|500,00|D|600,00|D||
+|J100|1.1.1|D|3|1.1|A|Disponível|100,00|D|150,00|D||
+|J100|1.1.2|D|3|1.1|A|Clientes|200,00|D|250,00|D||
+|J100|1.1.3|D|3|1.1|A|Estoques|200,00|D|200,00|D||
+|J100|1.2|T|2|1|A|ATIVO NÃO CIRCULANTE|300,00|D|400,00|D||
+|J100|1.2.1|D|3|1.2|A|Realizável a Longo Prazo|50,00|D|100,00|D||
+|J100|2|T|1||P|PASSIVO|800,00|C|1000,00|C||
+|J100|2.1|T|2|2|P|PASSIVO CIRCULANTE|250,00|C|300,00|C||
+|J100|2.1.1|D|3|2.1|P|Fornecedores|100,00|C|120,00|C||
+|J100|2.1.2|D|3|2.1|P|Empréstimos e Financiamentos|50,00|C|80,00|C||
+|J100|2.2|T|2|2|P|PASSIVO NÃO CIRCULANTE|150,00|C|200,00|C||
+|J100|2.2.1|D|3|2.2|P|Empréstimos e Financiamentos|100,00|C|100,00|C||
+|J100|2.3|T|2|2|P|PATRIMÔNIO LÍQUIDO|400,00|C|500,00|C||
+|J150|1|3.1|T|1||RECEITA BRUTA|0,00|C|2800,00|C|R||
+|J150|2|3.2|D|1||(-) Deduções da Receita Bruta|0,00|D|400,00|D|D||
+|J150|3|3.3|T|1||RECEITA LÍQUIDA|0,00|C|2400,00|C|R||
+|J150|4|3.3.1|D|1||Despesas Operacionais|0,00|D|1700,00|D|D||
+|J150|5|3.4|T|1||RESULTADO OPERACIONAL|0,00|C|300,00|C|R||
+|J150|6|3.5|D|1||Outras receitas|0,00|C|10,00|C|R||
+|J150|7|3.9|T|1||LUCRO LÍQUIDO DO EXERCÍCIO|0,00|C|100,00|C|R||
+|J990|25|
+|9999|31|
+`
+
+// TestParseECD verifica a leitura do balanço e da DRE da ECD
+func (s *SPEDTestSuite) TestParseECD() {
+	demonstracoes, err := vadu.ParseSPED(strings.NewReader(arquivoECD), vadu.MapeamentoPadrao())
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.OrigemECD, demonstracoes.Origem)
+	s.assert.Equal(vadu.Documento("98960887000164"), demonstracoes.CNPJ)
+	s.assert.Equal(12, demonstracoes.DRE.Meses)
+	s.assert.Equal(vadu.MustDecimal("1000"), *demonstracoes.Balanco.AtivoTotal)
+	s.assert.Equal(vadu.MustDecimal("150"), *demonstracoes.Balanco.DisponivelCaixa)
+	s.assert.Equal(vadu.MustDecimal("180"), *demonstracoes.Balanco.Emprestimos)
+	s.assert.Equal(vadu.MustDecimal("400"), *demonstracoes.DRE.DeducaoReceitaBruta)
+	s.assert.Equal(vadu.MustDecimal("100"), *demonstracoes.DRE.LucroLiquido)
+	s.assert.Len(demonstracoes.NaoMapeadas, 1)
+	s.assert.Equal("Outras receitas", demonstracoes.NaoMapeadas[0].Descricao)
+
+	dados, err := demonstracoes.DadosIntegracao()
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.MustDecimal("200"), dados.FaturamentoMedioMensal)
+	s.assert.Equal(vadu.MustDecimal("1100"), dados.MargemOperacional)
+	s.assert.Equal(vadu.MustDecimal("2"), dados.LiquidezCorrente)
+	s.assert.Equal(vadu.MustDecimal("0.2"), dados.RetornoPatrimonioLiquidoROE)
+	s.assert.True(vadu.ValidaDadosIntegracao(dados, vadu.ConsistenciaOptions{}).Valido())
+}
+
+// TestMapeamentoPorCodigo verifica a precedência dos códigos e o descarte de linhas
+func (s *SPEDTestSuite) TestMapeamentoPorCodigo() {
+	mapeamento := vadu.MapeamentoPadrao()
+	mapeamento.Codigos = map[string]vadu.ContaSPED{
+		"3.5":   vadu.ContaDespesas,
+		"2.2.1": vadu.ContaIgnorada,
+	}
+
+	demonstracoes, err := vadu.ParseSPED(strings.NewReader(arquivoECD), mapeamento)
+	s.assert.NoError(err)
+	s.assert.Empty(demonstracoes.NaoMapeadas)
+	// Somada às despesas operacionais mapeadas pela descrição
+	s.assert.Equal(vadu.MustDecimal("1690"), *demonstracoes.DRE.Despesas)
+	s.assert.Equal(vadu.MustDecimal("80"), *demonstracoes.Balanco.Emprestimos)
+}
+
+// TestParseECF verifica a leitura dos registros L100 e L300, usando o período mais recente
+func (s *SPEDTestSuite) TestParseECF() {
+	arquivo := strings.Join([]string{
+		"|0000|LECF|0009|33011770000199|EMPRESA TESTE SA|0||||01012023|31122023|N||1|",
+		"|L001|0|",
+		"|L030|01012023|31032023|T01|",
+		"|L100|1|ATIVO|S|1|01||900,00|D|0,00|0,00|950,00|D|",
+		"|L030|01042023|30062023|T02|",
+		"|L100|1|ATIVO|S|1|01||950,00|D|0,00|0,00|1000,00|D|",
+		"|L100|2.03|PATRIM\xd4NIO L\xcdQUIDO|S|2|03|2|400,00|C|0,00|0,00|500,00|C|",
+		"|L300|3.01|RECEITA BRUTA|S|1|04||1200,00|C|",
+		"|L300|3.05|RECEITA L\xcdQUIDA|S|1|04||1200,00|C|",
+		"|L300|3.11|LUCRO L\xcdQUIDO DO PER\xcdODO|S|1|04||30,00|D|",
+	}, "\n")
+
+	demonstracoes, err := vadu.ParseSPED(strings.NewReader(arquivo), vadu.MapeamentoPadrao())
+	s.assert.NoError(err)
+	s.assert.Equal(vadu.OrigemECF, demonstracoes.Origem)
+	s.assert.Equal(vadu.Documento("33011770000199"), demonstracoes.CNPJ)
+	s.assert.Equal(3, demonstracoes.DRE.Meses)
+	s.assert.Equal(vadu.MustDecimal("1000"), *demonstracoes.Balanco.AtivoTotal)
+	s.assert.Equal(vadu.MustDecimal("500"), *demonstracoes.Balanco.PatrimonioLiquido)
+	s.assert.Equal(vadu.MustDecimal("1200"), *demonstracoes.DRE.ReceitaBruta)
+	s.assert.Equal(vadu.MustDecimal("-30"), *demonstracoes.DRE.LucroLiquido)
+
+	dados, err := demonstracoes.DadosIntegracao()
+	s.assert.ErrorIs(err, vadu.ErrValorAusente)
+	s.assert.Equal(vadu.MustDecimal("400"), dados.FaturamentoMedioMensal)
+}
+
+// TestParseSPEDInvalido verifica os erros de leiaute
+func (s *SPEDTestSuite) TestParseSPEDInvalido() {
+	_, err := vadu.ParseSPED(strings.NewReader("|J100|1|T|1||A|ATIVO|0,00|D|1000,00|D||"), vadu.MapeamentoPadrao())
+	s.assert.True(errors.Is(err, vadu.ErrSPEDInvalido))
+
+	_, err = vadu.ParseSPED(strings.NewReader("|0000|LECD|01012023|31122023|X|98960887000164|\n|J100|1|T|1||A|ATIVO|0,00|D|abc|D||"), vadu.MapeamentoPadrao())
+	s.assert.ErrorIs(err, vadu.ErrSPEDInvalido)
+
+	_, err = vadu.ParseSPED(strings.NewReader("|0000|LECD|01012023|31122023|X|98960887000164|\n|I001|0|"), vadu.MapeamentoPadrao())
+	s.assert.ErrorIs(err, vadu.ErrSPEDSemDemonstracoes)
+}